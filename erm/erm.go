@@ -83,10 +83,13 @@
 package erm
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"runtime"
+	"slices"
 	"strings"
+	"sync/atomic"
 
 	"golang.org/x/text/language"
 )
@@ -182,6 +185,12 @@ type Error interface {
 
 	// WithRootError sets the root error
 	WithRootError(root error) Error
+
+	// WithLocale sets the language used by Error and ErrMap
+	WithLocale(tag language.Tag) Error
+
+	// WithStack captures a stack trace regardless of the status code
+	WithStack() Error
 }
 
 // StackError represents an application error enriched with stack trace,
@@ -199,6 +208,7 @@ type Error interface {
 //   - value: Value being validated
 //   - params: Template parameters for i18n substitution
 //   - errors: Child errors for batch validation scenarios (single-level only)
+//   - locale: Language used by Error and ErrMap (English if unset)
 //
 // StackError values are immutable after creation and are safe for
 // concurrent access. They satisfy Go's standard error wrapping
@@ -214,6 +224,7 @@ type StackError struct {
 	value           interface{}
 	params          map[string]interface{}
 	errors          []Error
+	locale          language.Tag
 }
 
 // =============================================================================
@@ -258,11 +269,7 @@ func New(code int, msg string, err error) Error {
 	// Client errors (4xx) don't need stack traces for debugging
 	var stack []uintptr
 	if code == http.StatusInternalServerError {
-		// Capture up to 32 PCs, skipping appropriate frames
-		const depth = 32
-		var pcs [depth]uintptr
-		n := runtime.Callers(skip, pcs[:])
-		stack = pcs[:n]
+		stack = callers(skip)
 	}
 
 	return &StackError{
@@ -273,6 +280,61 @@ func New(code int, msg string, err error) Error {
 	}
 }
 
+// callers captures up to 32 program counters of its caller's stack, skipping
+// skip frames as runtime.Callers would if called there.
+func callers(skip int) []uintptr {
+	const depth = 32
+	var pcs [depth]uintptr
+	n := runtime.Callers(skip+1, pcs[:])
+	return pcs[:n]
+}
+
+// =============================================================================
+// Error Formatting
+// =============================================================================
+
+// FormatConfig controls how Error and LocalizedError combine the messages of
+// an error that collects several child errors.
+type FormatConfig struct {
+	// Separator joins the child messages. Empty uses "; ".
+	Separator string
+	// SortByField orders the child messages by field name instead of the
+	// order the errors were added. Errors of the same field keep their order.
+	SortByField bool
+}
+
+// DefaultFormatConfig is the default FormatConfig: messages in the order the
+// errors were added, separated by "; ".
+var DefaultFormatConfig = FormatConfig{
+	Separator:   "; ",
+	SortByField: false,
+}
+
+// formatConfig holds the FormatConfig set by SetFormatConfig.
+var formatConfig atomic.Pointer[FormatConfig]
+
+// SetFormatConfig sets the package-wide FormatConfig, e.g. newline-separated
+// messages sorted by field for stable log output:
+//
+//	erm.SetFormatConfig(erm.FormatConfig{Separator: "\n", SortByField: true})
+//
+// It is meant to be called once at startup.
+func SetFormatConfig(config FormatConfig) {
+	if config.Separator == "" {
+		config.Separator = DefaultFormatConfig.Separator
+	}
+	formatConfig.Store(&config)
+}
+
+// GetFormatConfig returns the FormatConfig set by SetFormatConfig, or
+// DefaultFormatConfig.
+func GetFormatConfig() FormatConfig {
+	if config := formatConfig.Load(); config != nil {
+		return *config
+	}
+	return DefaultFormatConfig
+}
+
 // =============================================================================
 // Basic StackError Methods
 // =============================================================================
@@ -287,7 +349,7 @@ func (e *StackError) Error() string {
 
 	// If we have a message key or child errors, use localized error formatting
 	if e.messageKey != "" || len(e.errors) > 0 {
-		return e.LocalizedError(language.English)
+		return e.LocalizedError(e.defaultTag())
 	}
 
 	// Otherwise use the existing logic for non-localized errors
@@ -308,15 +370,45 @@ func (e *StackError) Code() int {
 	return e.code
 }
 
-// Unwrap returns the underlying error for Go 1.13+ error wrapping support.
+// Unwrap returns the underlying error for Go 1.13+ error wrapping support,
+// or nil if there is none.
 func (e *StackError) Unwrap() error {
 	if e == nil {
 		return nil
 	}
-	if e.root != nil {
-		return e.root
+	return e.root
+}
+
+// Is reports whether any child error matches target, so that errors.Is
+// traverses the errors collected with AddError in addition to the root error.
+// A type cannot have both Unwrap() error and Unwrap() []error, hence the
+// explicit method.
+func (e *StackError) Is(target error) bool {
+	if e == nil {
+		return false
 	}
-	return e
+	for _, child := range e.errors {
+		if errors.Is(child, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first child error that matches target, so that errors.As
+// traverses the errors collected with AddError in addition to the root error.
+// Note that errors.As checks the container itself first: a target of type
+// erm.Error or *StackError matches the container, not a child.
+func (e *StackError) As(target interface{}) bool {
+	if e == nil {
+		return false
+	}
+	for _, child := range e.errors {
+		if errors.As(child, target) {
+			return true
+		}
+	}
+	return false
 }
 
 // Stack returns the captured stack trace as program counters.
@@ -454,6 +546,32 @@ func (e *StackError) WithRootError(root error) Error {
 	return &err
 }
 
+// WithStack captures a stack trace at the call site regardless of the status
+// code, to trace where an unexpected client error originated. An error that
+// already has a stack trace (e.g. a 500) keeps it.
+func (e *StackError) WithStack() Error {
+	if e == nil {
+		return nil
+	}
+	err := *e
+	if len(err.stack) == 0 {
+		err.stack = callers(2) // Skip runtime.Callers and WithStack
+	}
+	return &err
+}
+
+// WithLocale sets the language used by Error and ErrMap, so that an error
+// can be rendered in the request's language without passing the tag to every
+// call. LocalizedError and LocalizedErrMap still use the tag they are given.
+func (e *StackError) WithLocale(tag language.Tag) Error {
+	if e == nil {
+		return nil
+	}
+	err := *e
+	err.locale = tag
+	return &err
+}
+
 // =============================================================================
 // Error Collection Methods
 // =============================================================================
@@ -517,10 +635,21 @@ func (e *StackError) HasErrors() bool {
 // Localization Methods
 // =============================================================================
 
-// ErrMap returns a map of field names to error messages using the default English localizer.
-// Returns nil if no errors exist. Convenience method for LocalizedErrMap(language.English).
+// ErrMap returns a map of field names to error messages in the error's locale
+// (see WithLocale), English by default. Returns nil if no errors exist.
 func (e *StackError) ErrMap() map[string][]string {
-	return e.LocalizedErrMap(language.English)
+	if e == nil {
+		return nil
+	}
+	return e.LocalizedErrMap(e.defaultTag())
+}
+
+// defaultTag returns the language used by Error and ErrMap.
+func (e *StackError) defaultTag() language.Tag {
+	if e.locale == language.Und {
+		return language.English
+	}
+	return e.locale
 }
 
 // LocalizedError returns the error message for the specified language.
@@ -547,12 +676,22 @@ func (e *StackError) LocalizedError(tag language.Tag) string {
 
 // formatChildErrors handles formatting multiple child errors.
 func (e *StackError) formatChildErrors(tag language.Tag) string {
-	var messages []string
+	children := make([]Error, 0, len(e.errors))
 	for _, err := range e.errors {
 		if err != nil {
-			messages = append(messages, err.LocalizedError(tag))
+			children = append(children, err)
 		}
 	}
+	if GetFormatConfig().SortByField {
+		slices.SortStableFunc(children, func(a, b Error) int {
+			return strings.Compare(a.FieldName(), b.FieldName())
+		})
+	}
+
+	messages := make([]string, len(children))
+	for i, err := range children {
+		messages[i] = err.LocalizedError(tag)
+	}
 
 	switch len(messages) {
 	case 0:
@@ -566,16 +705,17 @@ func (e *StackError) formatChildErrors(tag language.Tag) string {
 
 // formatMultipleErrors formats multiple error messages.
 func (e *StackError) formatMultipleErrors(messages []string, tag language.Tag) string {
+	joined := strings.Join(messages, GetFormatConfig().Separator)
 	localizer := GetLocalizer(tag)
 	if localizer != nil {
 		return localizer.MustLocalize(&LocalizeConfig{
 			MessageID: MsgErrorMultiple,
 			TemplateData: map[string]interface{}{
-				"errors": strings.Join(messages, "; "),
+				"errors": joined,
 			},
 		})
 	}
-	return fmt.Sprintf("multiple errors: %s", strings.Join(messages, "; "))
+	return fmt.Sprintf("multiple errors: %s", joined)
 }
 
 // localizeMessage attempts to localize a message using the message key.
@@ -760,6 +900,56 @@ func Stack(err error) []uintptr {
 	return nil
 }
 
+// Cause returns the deepest error in err's chain, e.g. the original
+// database or network error behind an erm error, by calling Unwrap until an
+// error no longer wraps another one. Joined errors (Unwrap() []error) end the walk, since
+// they have no single cause.
+//
+// Returns nil for nil errors.
+//
+// Example:
+//
+//	err := erm.Internal("Failed to load user", fmt.Errorf("query: %w", sql.ErrNoRows))
+//	erm.Cause(err) == sql.ErrNoRows // true
+func Cause(err error) error {
+	for err != nil {
+		next := errors.Unwrap(err)
+		if next == nil {
+			return err
+		}
+		err = next
+	}
+	return nil
+}
+
+// Find returns the first child error of an erm container, searched depth
+// first, for which match returns true. errors.As cannot do this for erm
+// errors, since a target of type erm.Error matches the container itself.
+// The container is not passed to match.
+//
+// Returns nil if err is not an erm error or no child matches.
+//
+// Example:
+//
+//	emailErr := erm.Find(err, func(e erm.Error) bool {
+//		return e.FieldName() == "email"
+//	})
+func Find(err error, match func(Error) bool) Error {
+	var e Error
+	if !errors.As(err, &e) {
+		return nil
+	}
+	for _, child := range e.AllErrors() {
+		if match(child) {
+			return child
+		}
+		if found := Find(child, match); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
 // Wrap wraps an error with erm error capabilities while preserving
 // the original error's metadata when possible.
 //
@@ -854,11 +1044,29 @@ func Conflict(msg string, err error) Error {
 	return New(http.StatusConflict, msg, err)
 }
 
+// UnprocessableEntity creates a 422 Unprocessable Entity error, the
+// conventional status for semantically invalid input, as opposed to the 400
+// of a malformed request.
+func UnprocessableEntity(msg string, err error) Error {
+	return New(http.StatusUnprocessableEntity, msg, err)
+}
+
+// TooManyRequests creates a 429 Too Many Requests error.
+func TooManyRequests(msg string, err error) Error {
+	return New(http.StatusTooManyRequests, msg, err)
+}
+
 // Internal creates a 500 Internal Server Error.
 func Internal(msg string, err error) Error {
 	return New(http.StatusInternalServerError, msg, err)
 }
 
+// ServiceUnavailable creates a 503 Service Unavailable error. Unlike
+// Internal, it captures no stack trace.
+func ServiceUnavailable(msg string, err error) Error {
+	return New(http.StatusServiceUnavailable, msg, err)
+}
+
 // =============================================================================
 // Validation Error Constructors
 // =============================================================================
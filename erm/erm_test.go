@@ -4,9 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"os"
+	"slices"
 	"strings"
 	"testing"
 
+	"github.com/c3p0-box/utils/i18n"
 	"golang.org/x/text/language"
 )
 
@@ -167,8 +170,8 @@ func TestStackErrorMethods(t *testing.T) {
 	if errNoRoot.Error() != "test" {
 		t.Fatalf("Error() = %q, want %q", errNoRoot.Error(), "test")
 	}
-	if errNoRoot.Unwrap() != errNoRoot {
-		t.Fatal("Unwrap() should return the error itself when there is no root")
+	if errNoRoot.Unwrap() != nil {
+		t.Fatal("Unwrap() should return nil when there is no root")
 	}
 }
 
@@ -239,6 +242,76 @@ func TestStackError_WithRootError(t *testing.T) {
 // =============================================================================
 
 // TestValidationErrorCapabilities tests the new validation error capabilities
+// TestStackError_WithStack tests forcing a stack trace on non-500 errors
+func TestStackError_WithStack(t *testing.T) {
+	t.Run("nil receiver", func(t *testing.T) {
+		var err *StackError
+		if err.WithStack() != nil {
+			t.Error("WithStack on nil receiver should return nil")
+		}
+	})
+
+	t.Run("client error", func(t *testing.T) {
+		original := BadRequest("invalid", nil)
+		traced := original.WithStack()
+
+		if len(original.Stack()) != 0 {
+			t.Error("WithStack must not modify the receiver")
+		}
+		if Status(traced) != http.StatusBadRequest {
+			t.Errorf("Status() = %d, want %d", Status(traced), http.StatusBadRequest)
+		}
+		if !strings.Contains(FormatStack(traced), "TestStackError_WithStack") {
+			t.Errorf("stack should start at the WithStack call site, got:\n%s", FormatStack(traced))
+		}
+		if strings.Contains(FormatStack(traced), "erm.callers") {
+			t.Error("stack should not contain erm internals")
+		}
+	})
+
+	t.Run("keeps existing stack", func(t *testing.T) {
+		original := Internal("failed", nil)
+		traced := original.WithStack()
+		if len(traced.Stack()) == 0 || !slices.Equal(traced.Stack(), original.Stack()) {
+			t.Error("WithStack should keep the stack captured by New")
+		}
+	})
+}
+
+func TestStackError_WithLocale(t *testing.T) {
+	err := i18n.AddTranslations(language.German, map[string]*i18n.Translation{
+		MsgRequired: {Singular: "{{.field}} ist erforderlich"},
+	})
+	if err != nil {
+		t.Fatalf("failed to add translations: %v", err)
+	}
+
+	original := NewValidationError(MsgRequired, "email", "")
+	german := original.WithLocale(language.German)
+
+	if got := german.Error(); got != "email ist erforderlich" {
+		t.Errorf("Error() = %q, want German message", got)
+	}
+	if got := original.Error(); got != "email is required" {
+		t.Errorf("original Error() = %q, WithLocale must not modify the receiver", got)
+	}
+	if got := german.LocalizedError(language.English); got != "email is required" {
+		t.Errorf("LocalizedError(English) = %q, want English message", got)
+	}
+
+	container := New(http.StatusBadRequest, "", nil)
+	container.AddError(original)
+	localized := container.WithLocale(language.German)
+	if got := localized.ErrMap()["email"]; len(got) != 1 || got[0] != "email ist erforderlich" {
+		t.Errorf("ErrMap() = %v, want German messages", got)
+	}
+
+	var nilErr *StackError
+	if nilErr.WithLocale(language.German) != nil || nilErr.ErrMap() != nil {
+		t.Error("WithLocale and ErrMap should return nil for nil receiver")
+	}
+}
+
 func TestValidationErrorCapabilities(t *testing.T) {
 	t.Run("NewValidationError", func(t *testing.T) {
 		err := NewValidationError("validation.required", "email", "")
@@ -695,6 +768,98 @@ func TestAddErrors(t *testing.T) {
 	})
 }
 
+// TestFormatConfig tests the configurable format of multi-error containers
+func TestFormatConfig(t *testing.T) {
+	defer SetFormatConfig(DefaultFormatConfig)
+
+	container := New(http.StatusBadRequest, "", nil)
+	container.AddError(RequiredError("name", ""))
+	container.AddError(EmailError("email", "x"))
+	container.AddError(MinLengthError("email", "x", 5))
+
+	tests := []struct {
+		name     string
+		config   *FormatConfig
+		expected string
+	}{
+		{"default", nil, "multiple errors: name is required; email must be a valid email address; email must be at least 5 characters long"},
+		{"separator", &FormatConfig{Separator: "\n"}, "multiple errors: name is required\nemail must be a valid email address\nemail must be at least 5 characters long"},
+		{"sorted by field", &FormatConfig{SortByField: true}, "multiple errors: email must be a valid email address; email must be at least 5 characters long; name is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetFormatConfig(DefaultFormatConfig)
+			if tt.config != nil {
+				SetFormatConfig(*tt.config)
+			}
+			if got := container.Error(); got != tt.expected {
+				t.Errorf("Error() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	if got := GetFormatConfig().Separator; got != "; " {
+		t.Errorf("empty Separator should default to %q, got %q", "; ", got)
+	}
+}
+
+// quotaError is a custom error type wrapped by a child error in TestErrorCollection_IsAs.
+type quotaError struct{ limit int }
+
+func (e *quotaError) Error() string { return fmt.Sprintf("quota of %d exceeded", e.limit) }
+
+// TestErrorCollection_IsAs tests that errors.Is and errors.As traverse child errors
+func TestErrorCollection_IsAs(t *testing.T) {
+	errNotFound := errors.New("not found")
+	quota := &quotaError{limit: 10}
+
+	container := New(http.StatusBadRequest, "", nil)
+	container.AddError(RequiredError("email", ""))
+	container.AddError(Conflict("duplicate", fmt.Errorf("insert: %w", errNotFound)))
+	container.AddError(New(http.StatusTooManyRequests, "slow down", quota))
+
+	t.Run("Is", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			target   error
+			expected bool
+		}{
+			{"wrapped child root", errNotFound, true},
+			{"custom child root", quota, true},
+			{"absent", errors.New("other"), false},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if got := errors.Is(container, tt.target); got != tt.expected {
+					t.Errorf("errors.Is() = %v, want %v", got, tt.expected)
+				}
+			})
+		}
+	})
+
+	t.Run("As", func(t *testing.T) {
+		var target *quotaError
+		if !errors.As(container, &target) {
+			t.Fatal("errors.As should find the child's custom error")
+		}
+		if target.limit != 10 {
+			t.Errorf("limit = %d, want 10", target.limit)
+		}
+
+		var pathErr *os.PathError
+		if errors.As(container, &pathErr) {
+			t.Error("errors.As should not match an absent type")
+		}
+	})
+
+	t.Run("without children", func(t *testing.T) {
+		if errors.Is(New(http.StatusBadRequest, "bad", nil), errNotFound) {
+			t.Error("errors.Is should be false for an error without root or children")
+		}
+	})
+}
+
 // =============================================================================
 // Helper Function Tests
 // =============================================================================
@@ -824,6 +989,75 @@ func TestFormatStack(t *testing.T) {
 	})
 }
 
+// TestCause tests the Cause function
+func TestCause(t *testing.T) {
+	root := errors.New("connection refused")
+	selfRooted := BadRequest("invalid", nil)
+	joined := errors.Join(root, errors.New("other"))
+
+	tests := []struct {
+		name     string
+		err      error
+		expected error
+	}{
+		{"nil", nil, nil},
+		{"standard error", root, root},
+		{"erm error", Internal("db failed", root), root},
+		{"erm error without root", selfRooted, selfRooted},
+		{"nested erm errors", Conflict("outer", Internal("inner", fmt.Errorf("query: %w", root))), root},
+		{"wrapped erm error without root", fmt.Errorf("handler: %w", selfRooted), selfRooted},
+		{"joined errors", fmt.Errorf("batch: %w", joined), joined},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Cause(tt.err); got != tt.expected {
+				t.Errorf("Cause() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestFind tests that Find reaches a specific child of an erm container
+func TestFind(t *testing.T) {
+	emailErr := RequiredError("email", "")
+	passwordErr := MinLengthError("password", "123", 8)
+
+	nested := New(http.StatusBadRequest, "address", nil)
+	nested.AddError(passwordErr)
+
+	container := New(http.StatusBadRequest, "", nil)
+	container.AddError(emailErr)
+	container.AddError(nested)
+
+	byField := func(field string) func(Error) bool {
+		return func(e Error) bool { return e.FieldName() == field }
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		match    func(Error) bool
+		expected Error
+	}{
+		{"nil", nil, byField("email"), nil},
+		{"standard error", errors.New("boom"), byField("email"), nil},
+		{"direct child", container, byField("email"), emailErr},
+		{"nested child", container, byField("password"), passwordErr},
+		{"wrapped container", fmt.Errorf("handler: %w", container), byField("email"), emailErr},
+		{"container not matched", container, func(e Error) bool { return e == container }, nil},
+		{"absent", container, byField("name"), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Find(tt.err, tt.match); got != tt.expected {
+				t.Errorf("Find() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
 // TestWrap tests the Wrap function comprehensively
 func TestWrap(t *testing.T) {
 	t.Run("wrap nil", func(t *testing.T) {
@@ -884,7 +1118,10 @@ func TestConvenienceConstructors(t *testing.T) {
 		{"Unauthorized", Unauthorized, http.StatusUnauthorized, false},
 		{"Forbidden", Forbidden, http.StatusForbidden, false},
 		{"Conflict", Conflict, http.StatusConflict, false},
+		{"UnprocessableEntity", UnprocessableEntity, http.StatusUnprocessableEntity, false},
+		{"TooManyRequests", TooManyRequests, http.StatusTooManyRequests, false},
 		{"Internal", Internal, http.StatusInternalServerError, true},
+		{"ServiceUnavailable", ServiceUnavailable, http.StatusServiceUnavailable, false},
 	}
 
 	for _, tt := range tests {
@@ -906,7 +1143,7 @@ func TestConvenienceConstructors(t *testing.T) {
 				if tt.wantStack {
 					t.Fatal("stack trace should be captured for Internal (500) errors")
 				} else {
-					t.Fatal("stack trace should not be captured for client (4xx) errors")
+					t.Fatal("stack trace should not be captured for non-500 errors")
 				}
 			}
 
@@ -966,11 +1203,11 @@ func TestNotFound(t *testing.T) {
 			t.Fatalf("Status() = %d, want %d", Status(err), http.StatusNotFound)
 		}
 
-		if errors.Unwrap(err) == nil {
-			t.Fatal("Unwrap should return self when created with nil error")
+		if errors.Unwrap(err) != nil {
+			t.Fatal("Unwrap should return nil when created with nil error")
 		}
-		if err.Unwrap().Error() != "resource is not found" {
-			t.Fatal("Unwrapped error should return self which did not match")
+		if err.Error() != "resource is not found" {
+			t.Fatalf("Error() = %q, want %q", err.Error(), "resource is not found")
 		}
 	})
 }
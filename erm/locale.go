@@ -38,80 +38,120 @@ const (
 	MsgMaxLength   = "validation.max_length"
 	MsgExactLength = "validation.exact_length"
 
-	MsgEmail        = "validation.email"
-	MsgURL          = "validation.url"
-	MsgNumeric      = "validation.numeric"
-	MsgAlpha        = "validation.alpha"
-	MsgAlphaNumeric = "validation.alpha_numeric"
-	MsgRegex        = "validation.regex"
-	MsgIn           = "validation.in"
-	MsgNotIn        = "validation.not_in"
-	MsgContains     = "validation.contains"
-	MsgStartsWith   = "validation.starts_with"
-	MsgEndsWith     = "validation.ends_with"
-	MsgLowercase    = "validation.lowercase"
-	MsgUppercase    = "validation.uppercase"
-	MsgInteger      = "validation.integer"
-	MsgFloat        = "validation.float"
-	MsgJSON         = "validation.json"
-	MsgBase64       = "validation.base64"
-	MsgUUID         = "validation.uuid"
-	MsgSlug         = "validation.slug"
-	MsgMin          = "validation.min_value"
-	MsgMax          = "validation.max_value"
-	MsgBetween      = "validation.between"
-	MsgZero         = "validation.zero"
-	MsgEqual        = "validation.equal"
-	MsgEqualTo      = "validation.equal_to"
-	MsgGreaterThan  = "validation.greater_than"
-	MsgLessThan     = "validation.less_than"
-	MsgPositive     = "validation.positive"
-	MsgNegative     = "validation.negative"
-	MsgEven         = "validation.even"
-	MsgOdd          = "validation.odd"
-	MsgMultipleOf   = "validation.multiple_of"
-	MsgFinite       = "validation.finite"
-	MsgPrecision    = "validation.precision"
-	MsgInvalid      = "validation.invalid"
-	MsgDuplicate    = "validation.duplicate"
+	MsgEmail                = "validation.email"
+	MsgURL                  = "validation.url"
+	MsgNumeric              = "validation.numeric"
+	MsgAlpha                = "validation.alpha"
+	MsgAlphaNumeric         = "validation.alpha_numeric"
+	MsgRegex                = "validation.regex"
+	MsgIn                   = "validation.in"
+	MsgNotIn                = "validation.not_in"
+	MsgContains             = "validation.contains"
+	MsgStartsWith           = "validation.starts_with"
+	MsgEndsWith             = "validation.ends_with"
+	MsgLowercase            = "validation.lowercase"
+	MsgUppercase            = "validation.uppercase"
+	MsgInteger              = "validation.integer"
+	MsgFloat                = "validation.float"
+	MsgJSON                 = "validation.json"
+	MsgBase64               = "validation.base64"
+	MsgUUID                 = "validation.uuid"
+	MsgSlug                 = "validation.slug"
+	MsgMin                  = "validation.min_value"
+	MsgMax                  = "validation.max_value"
+	MsgBetween              = "validation.between"
+	MsgZero                 = "validation.zero"
+	MsgEqual                = "validation.equal"
+	MsgEqualTo              = "validation.equal_to"
+	MsgGreaterThan          = "validation.greater_than"
+	MsgLessThan             = "validation.less_than"
+	MsgPositive             = "validation.positive"
+	MsgNegative             = "validation.negative"
+	MsgEven                 = "validation.even"
+	MsgOdd                  = "validation.odd"
+	MsgMultipleOf           = "validation.multiple_of"
+	MsgFinite               = "validation.finite"
+	MsgPrecision            = "validation.precision"
+	MsgInvalid              = "validation.invalid"
+	MsgDuplicate            = "validation.duplicate"
+	MsgInRanges             = "validation.in_ranges"
+	MsgStep                 = "validation.step"
+	MsgRequiredIf           = "validation.required_if"
+	MsgRequiredUnless       = "validation.required_unless"
+	MsgLuhn                 = "validation.luhn"
+	MsgISBN                 = "validation.isbn"
+	MsgEAN                  = "validation.ean"
+	MsgUPC                  = "validation.upc"
+	MsgPostalCode           = "validation.postal_code"
+	MsgMinEntropy           = "validation.min_entropy"
+	MsgBlocklist            = "validation.blocklist"
+	MsgStartsWithAny        = "validation.starts_with_any"
+	MsgEndsWithAny          = "validation.ends_with_any"
+	MsgUTF8                 = "validation.utf8"
+	MsgNumericPrecision     = "validation.numeric_precision"
+	MsgWholeNumber          = "validation.whole_number"
+	MsgNumericLength        = "validation.numeric_length"
+	MsgNumericLengthBetween = "validation.numeric_length_between"
+	MsgEnum                 = "validation.enum"
+	MsgMultipleOfAll        = "validation.multiple_of_all"
+	MsgMultipleOfAny        = "validation.multiple_of_any"
 
 	// Negated validation message constants
 
-	MsgNotEmpty        = "validation.not_empty"
-	MsgNotEqualTo      = "validation.not_equal_to"
-	MsgNotMinLength    = "validation.not_min_length"
-	MsgNotMaxLength    = "validation.not_max_length"
-	MsgNotExactLength  = "validation.not_exact_length"
-	MsgNotBetween      = "validation.not_between"
-	MsgNotEmail        = "validation.not_email"
-	MsgNotURL          = "validation.not_url"
-	MsgNotNumeric      = "validation.not_numeric"
-	MsgNotAlpha        = "validation.not_alpha"
-	MsgNotAlphaNumeric = "validation.not_alpha_numeric"
-	MsgNotRegex        = "validation.not_regex"
-	MsgNotContains     = "validation.not_contains"
-	MsgNotStartsWith   = "validation.not_starts_with"
-	MsgNotEndsWith     = "validation.not_ends_with"
-	MsgNotLowercase    = "validation.not_lowercase"
-	MsgNotUppercase    = "validation.not_uppercase"
-	MsgNotInteger      = "validation.not_integer"
-	MsgNotFloat        = "validation.not_float"
-	MsgNotJSON         = "validation.not_json"
-	MsgNotBase64       = "validation.not_base64"
-	MsgNotUUID         = "validation.not_uuid"
-	MsgNotSlug         = "validation.not_slug"
-	MsgNotZero         = "validation.not_zero"
-	MsgNotMinValue     = "validation.not_min_value"
-	MsgNotMaxValue     = "validation.not_max_value"
-	MsgNotGreaterThan  = "validation.not_greater_than"
-	MsgNotLessThan     = "validation.not_less_than"
-	MsgNotPositive     = "validation.not_positive"
-	MsgNotNegative     = "validation.not_negative"
-	MsgNotEven         = "validation.not_even"
-	MsgNotOdd          = "validation.not_odd"
-	MsgNotMultipleOf   = "validation.not_multiple_of"
-	MsgNotFinite       = "validation.not_finite"
-	MsgNotPrecision    = "validation.not_precision"
+	MsgNotEmpty                = "validation.not_empty"
+	MsgNotEqualTo              = "validation.not_equal_to"
+	MsgNotMinLength            = "validation.not_min_length"
+	MsgNotMaxLength            = "validation.not_max_length"
+	MsgNotExactLength          = "validation.not_exact_length"
+	MsgNotBetween              = "validation.not_between"
+	MsgNotEmail                = "validation.not_email"
+	MsgNotURL                  = "validation.not_url"
+	MsgNotNumeric              = "validation.not_numeric"
+	MsgNotAlpha                = "validation.not_alpha"
+	MsgNotAlphaNumeric         = "validation.not_alpha_numeric"
+	MsgNotRegex                = "validation.not_regex"
+	MsgNotContains             = "validation.not_contains"
+	MsgNotStartsWith           = "validation.not_starts_with"
+	MsgNotEndsWith             = "validation.not_ends_with"
+	MsgNotLowercase            = "validation.not_lowercase"
+	MsgNotUppercase            = "validation.not_uppercase"
+	MsgNotInteger              = "validation.not_integer"
+	MsgNotFloat                = "validation.not_float"
+	MsgNotJSON                 = "validation.not_json"
+	MsgNotBase64               = "validation.not_base64"
+	MsgNotUUID                 = "validation.not_uuid"
+	MsgNotSlug                 = "validation.not_slug"
+	MsgNotZero                 = "validation.not_zero"
+	MsgNotMinValue             = "validation.not_min_value"
+	MsgNotMaxValue             = "validation.not_max_value"
+	MsgNotGreaterThan          = "validation.not_greater_than"
+	MsgNotLessThan             = "validation.not_less_than"
+	MsgNotPositive             = "validation.not_positive"
+	MsgNotNegative             = "validation.not_negative"
+	MsgNotEven                 = "validation.not_even"
+	MsgNotOdd                  = "validation.not_odd"
+	MsgNotMultipleOf           = "validation.not_multiple_of"
+	MsgNotFinite               = "validation.not_finite"
+	MsgNotPrecision            = "validation.not_precision"
+	MsgNotInRanges             = "validation.not_in_ranges"
+	MsgNotStep                 = "validation.not_step"
+	MsgNotLuhn                 = "validation.not_luhn"
+	MsgNotISBN                 = "validation.not_isbn"
+	MsgNotEAN                  = "validation.not_ean"
+	MsgNotUPC                  = "validation.not_upc"
+	MsgNotPostalCode           = "validation.not_postal_code"
+	MsgNotMinEntropy           = "validation.not_min_entropy"
+	MsgNotBlocklist            = "validation.not_blocklist"
+	MsgNotStartsWithAny        = "validation.not_starts_with_any"
+	MsgNotEndsWithAny          = "validation.not_ends_with_any"
+	MsgNotUTF8                 = "validation.not_utf8"
+	MsgNotNumericPrecision     = "validation.not_numeric_precision"
+	MsgNotWholeNumber          = "validation.not_whole_number"
+	MsgNotNumericLength        = "validation.not_numeric_length"
+	MsgNotNumericLengthBetween = "validation.not_numeric_length_between"
+	MsgNotEnum                 = "validation.not_enum"
+	MsgNotMultipleOfAll        = "validation.not_multiple_of_all"
+	MsgNotMultipleOfAny        = "validation.not_multiple_of_any"
 
 	// Special validation message constants
 
@@ -123,6 +163,10 @@ const (
 	MsgErrorMultiple       = "error.multiple"
 	MsgErrorNotFound       = "error.not_found"
 	MsgErrorInvalidRequest = "error.invalid_request"
+	MsgErrorBodyRequired   = "error.body_required"
+	MsgErrorFileTooLarge   = "error.file_too_large"
+	MsgErrorTooManyFiles   = "error.too_many_files"
+	MsgErrorBodyTooLarge   = "error.body_too_large"
 	MsgErrorInactive       = "error.inactive"
 )
 
@@ -363,6 +407,90 @@ func initializeMessages() {
 			Singular: "{{.field}} must have at most {{.places}} decimal places",
 			Plural:   "",
 		},
+		MsgInRanges: {
+			Singular: "{{.field}} must be within one of the ranges {{.ranges}}",
+			Plural:   "",
+		},
+		MsgStep: {
+			Singular: "{{.field}} must be {{.base}} plus a multiple of {{.step}}",
+			Plural:   "",
+		},
+		MsgRequiredIf: {
+			Singular: "{{.field}} is required when {{.other}} is {{.value}}",
+			Plural:   "",
+		},
+		MsgRequiredUnless: {
+			Singular: "{{.field}} is required unless {{.other}} is {{.value}}",
+			Plural:   "",
+		},
+		MsgLuhn: {
+			Singular: "{{.field}} must have a valid Luhn checksum",
+			Plural:   "",
+		},
+		MsgISBN: {
+			Singular: "{{.field}} must be a valid ISBN",
+			Plural:   "",
+		},
+		MsgEAN: {
+			Singular: "{{.field}} must be a valid EAN barcode",
+			Plural:   "",
+		},
+		MsgUPC: {
+			Singular: "{{.field}} must be a valid UPC barcode",
+			Plural:   "",
+		},
+		MsgPostalCode: {
+			Singular: "{{.field}} must be a valid postal code",
+			Plural:   "",
+		},
+		MsgMinEntropy: {
+			Singular: "{{.field}} is too predictable; use a more random value",
+			Plural:   "",
+		},
+		MsgBlocklist: {
+			Singular: "{{.field}} must not contain \"{{.word}}\"",
+			Plural:   "",
+		},
+		MsgStartsWithAny: {
+			Singular: "{{.field}} must start with one of: {{.prefixes}}",
+			Plural:   "",
+		},
+		MsgEndsWithAny: {
+			Singular: "{{.field}} must end with one of: {{.suffixes}}",
+			Plural:   "",
+		},
+		MsgUTF8: {
+			Singular: "{{.field}} must be valid UTF-8 text",
+			Plural:   "",
+		},
+		MsgNumericPrecision: {
+			Singular: "{{.field}} must have at most {{.integer_digits}} digits before and {{.fraction_digits}} digits after the decimal point",
+			Plural:   "",
+		},
+		MsgWholeNumber: {
+			Singular: "{{.field}} must be a whole number",
+			Plural:   "",
+		},
+		MsgNumericLength: {
+			Singular: "{{.field}} must be exactly {{.length}} digits",
+			Plural:   "",
+		},
+		MsgNumericLengthBetween: {
+			Singular: "{{.field}} must be between {{.min}} and {{.max}} digits",
+			Plural:   "",
+		},
+		MsgEnum: {
+			Singular: "{{.field}} must be one of: {{.values}}",
+			Plural:   "",
+		},
+		MsgMultipleOfAll: {
+			Singular: "{{.field}} must be a multiple of all of: {{.divisors}}",
+			Plural:   "",
+		},
+		MsgMultipleOfAny: {
+			Singular: "{{.field}} must be a multiple of one of: {{.divisors}}",
+			Plural:   "",
+		},
 		MsgDivisorZero: {
 			Singular: "{{.field}} divisor cannot be zero",
 			Plural:   "",
@@ -508,6 +636,82 @@ func initializeMessages() {
 			Singular: "{{.field}} must not be less than {{.value}}",
 			Plural:   "",
 		},
+		MsgNotInRanges: {
+			Singular: "{{.field}} must not be within any of the ranges {{.ranges}}",
+			Plural:   "",
+		},
+		MsgNotStep: {
+			Singular: "{{.field}} must not be {{.base}} plus a multiple of {{.step}}",
+			Plural:   "",
+		},
+		MsgNotLuhn: {
+			Singular: "{{.field}} must not have a valid Luhn checksum",
+			Plural:   "",
+		},
+		MsgNotISBN: {
+			Singular: "{{.field}} must not be a valid ISBN",
+			Plural:   "",
+		},
+		MsgNotEAN: {
+			Singular: "{{.field}} must not be a valid EAN barcode",
+			Plural:   "",
+		},
+		MsgNotUPC: {
+			Singular: "{{.field}} must not be a valid UPC barcode",
+			Plural:   "",
+		},
+		MsgNotPostalCode: {
+			Singular: "{{.field}} must not be a valid postal code",
+			Plural:   "",
+		},
+		MsgNotMinEntropy: {
+			Singular: "{{.field}} must have less than {{.min}} bits of entropy",
+			Plural:   "",
+		},
+		MsgNotBlocklist: {
+			Singular: "{{.field}} must contain one of: {{.words}}",
+			Plural:   "",
+		},
+		MsgNotStartsWithAny: {
+			Singular: "{{.field}} must not start with any of: {{.prefixes}}",
+			Plural:   "",
+		},
+		MsgNotEndsWithAny: {
+			Singular: "{{.field}} must not end with any of: {{.suffixes}}",
+			Plural:   "",
+		},
+		MsgNotUTF8: {
+			Singular: "{{.field}} must not be valid UTF-8 text",
+			Plural:   "",
+		},
+		MsgNotNumericPrecision: {
+			Singular: "{{.field}} must have more than {{.integer_digits}} digits before or {{.fraction_digits}} digits after the decimal point",
+			Plural:   "",
+		},
+		MsgNotWholeNumber: {
+			Singular: "{{.field}} must not be a whole number",
+			Plural:   "",
+		},
+		MsgNotNumericLength: {
+			Singular: "{{.field}} must not be exactly {{.length}} digits",
+			Plural:   "",
+		},
+		MsgNotNumericLengthBetween: {
+			Singular: "{{.field}} must not be between {{.min}} and {{.max}} digits",
+			Plural:   "",
+		},
+		MsgNotEnum: {
+			Singular: "{{.field}} must not be one of: {{.values}}",
+			Plural:   "",
+		},
+		MsgNotMultipleOfAll: {
+			Singular: "{{.field}} must not be a multiple of all of: {{.divisors}}",
+			Plural:   "",
+		},
+		MsgNotMultipleOfAny: {
+			Singular: "{{.field}} must not be a multiple of any of: {{.divisors}}",
+			Plural:   "",
+		},
 		MsgErrorMultiple: {
 			Singular: "multiple errors: {{.errors}}",
 			Plural:   "",
@@ -520,6 +724,22 @@ func initializeMessages() {
 			Singular: "invalid request",
 			Plural:   "",
 		},
+		MsgErrorBodyRequired: {
+			Singular: "request body is required",
+			Plural:   "",
+		},
+		MsgErrorFileTooLarge: {
+			Singular: "{{.field}} must not be larger than {{.max}} bytes",
+			Plural:   "",
+		},
+		MsgErrorTooManyFiles: {
+			Singular: "at most {{.max}} files may be uploaded",
+			Plural:   "",
+		},
+		MsgErrorBodyTooLarge: {
+			Singular: "request body must not be larger than {{.max}} bytes",
+			Plural:   "",
+		},
 		MsgErrorInactive: {
 			Singular: "{{.field}} is inactive",
 			Plural:   "",
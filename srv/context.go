@@ -1,22 +1,66 @@
 package srv
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"sync"
+
+	"github.com/c3p0-box/utils/erm"
+	"golang.org/x/text/language"
 )
 
+// ErrInvalidRedirectCode is returned by Context.Redirect for status codes
+// outside the 3xx range.
+var ErrInvalidRedirectCode = errors.New("invalid redirect status code")
+
+// ErrInvalidJSONPCallback is wrapped by the error returned from Context.JSONP
+// when the callback is not a valid JavaScript identifier.
+var ErrInvalidJSONPCallback = errors.New("invalid JSONP callback")
+
+// Context is the request context passed to every HandlerFunc. HttpContext
+// is its implementation; middleware and handlers should depend on the
+// interface so that wrappers can decorate it.
+//
+// Context grows as the package gains features, and adding a method is a
+// breaking change for types that implement it from scratch. Such types
+// (e.g. test doubles) should embed *HttpContext and override only what
+// they need. The following methods were added after the initial release:
+// SetContext, Context, SetRequest, SetResponse, Hijack, Upgrade, JSONPretty,
+// SetEscapeHTML, JSONP, NoContent, EarlyHints, RoutePattern, RouteName,
+// ParamInt, ParamInt64, ParamUUID, Logger, SetLogger, Error, BadRequest and
+// NotFound.
 type Context interface {
 	Set(key string, value interface{})
 	Get(key string) interface{}
+	SetContext(key, value interface{})
+	Context() context.Context
 	Request() *http.Request
+	SetRequest(r *http.Request)
 	Response() http.ResponseWriter
+	SetResponse(w http.ResponseWriter)
+	Logger() *slog.Logger
+	SetLogger(logger *slog.Logger)
 	IsTLS() bool
 	IsWebSocket() bool
+	Hijack() (net.Conn, *bufio.ReadWriter, error)
+	Upgrade(protocols ...string) (net.Conn, *bufio.ReadWriter, error)
 	Method() string
 	Path() string
+	RoutePattern() string
+	RouteName() string
 	Param(key string) string
+	ParamInt(key string) (int, error)
+	ParamInt64(key string) (int64, error)
+	ParamUUID(key string) (string, error)
 	Query() url.Values
 	QueryParam(key string) string
 	FormValue(key string) string
@@ -28,11 +72,19 @@ type Context interface {
 	AddHeader(key, value string)
 	SetCookie(cookie *http.Cookie)
 	JSON(code int, v interface{}) error
+	JSONPretty(code int, v interface{}, indent string) error
+	SetEscapeHTML(escape bool)
+	JSONP(code int, callback string, v interface{}) error
 	String(code int, text string) error
 	Redirect(code int, path string) error
+	NoContent(code int) error
 	HTML(code int, html string) error
 	HTMLBlob(code int, html []byte) error
 	WriteHeader(code int)
+	EarlyHints(links ...string)
+	Error(code int, msg string) erm.Error
+	BadRequest(msg string) erm.Error
+	NotFound(msg string) erm.Error
 }
 
 // HttpContext provides a convenient wrapper around http.Request and http.ResponseWriter
@@ -47,6 +99,13 @@ type HttpContext struct {
 	values         map[string]interface{}
 	query          url.Values
 	path           string
+	// routePattern and routeName are set by the Mux for the matched route.
+	routePattern string
+	routeName    string
+	// logger is the request-scoped logger, built on first use.
+	logger *slog.Logger
+	// disableHTMLEscape turns off escaping of <, > and & in JSON responses.
+	disableHTMLEscape bool
 }
 
 // NewHttpContext creates a new HttpContext instance wrapping the provided
@@ -62,6 +121,24 @@ func NewHttpContext(w http.ResponseWriter, r *http.Request) *HttpContext {
 	}
 }
 
+// NewTestContext creates a Context for unit-testing a HandlerFunc directly,
+// without registering it on a Mux. It takes its arguments in the order of
+// httptest, and is otherwise equivalent to NewHttpContext. Path parameters
+// are set on the request with SetPathValue, and middleware can be tested by
+// calling it with the context and a stub next handler.
+//
+// Example:
+//
+//	req := httptest.NewRequest("GET", "/users/42", nil)
+//	req.SetPathValue("id", "42")
+//	rec := httptest.NewRecorder()
+//
+//	err := getUser(srv.NewTestContext(req, rec))
+//	// inspect err, rec.Code and rec.Body
+func NewTestContext(req *http.Request, rec http.ResponseWriter) Context {
+	return NewHttpContext(rec, req)
+}
+
 // ============================
 // Value Store Methods
 // ============================
@@ -86,6 +163,37 @@ func (c *HttpContext) Get(key string) interface{} {
 	return nil
 }
 
+// ContextValue returns the value stored under key with ctx.Set, converted to
+// T. It returns the zero value of T and false if the key is missing or holds
+// a value of a different type, instead of panicking like a bare type
+// assertion would.
+//
+// Example:
+//
+//	session, ok := srv.ContextValue[*srv.Session](ctx, "session")
+//	if !ok {
+//		return erm.Internal("session middleware not configured", nil)
+//	}
+func ContextValue[T any](ctx Context, key string) (T, bool) {
+	value, ok := ctx.Get(key).(T)
+	return value, ok
+}
+
+// SetContext stores a value in the request's context.Context under key, so
+// that it is visible to code that only sees the standard context (tracing,
+// database drivers, loggers), e.g. via ctx.Request().Context().Value(key).
+//
+// The request is replaced with a shallow copy carrying the new context, so
+// call ctx.Request() again after SetContext instead of reusing an earlier
+// *http.Request. As with context.WithValue, key should be of an unexported
+// type to avoid collisions between packages.
+//
+// Values set with Set are only visible through the srv Context; use
+// SetContext for values that must cross into libraries.
+func (c *HttpContext) SetContext(key, value interface{}) {
+	c.request = c.request.WithContext(context.WithValue(c.request.Context(), key, value))
+}
+
 // ============================
 // Request Access Methods
 // ============================
@@ -95,11 +203,70 @@ func (c *HttpContext) Request() *http.Request {
 	return c.request
 }
 
+// SetRequest replaces the underlying http.Request. Middleware uses it to
+// derive a new request, most commonly one carrying a different
+// context.Context:
+//
+//	timeoutCtx, cancel := context.WithTimeout(ctx.Context(), 5*time.Second)
+//	defer cancel()
+//	ctx.SetRequest(ctx.Request().WithContext(timeoutCtx))
+func (c *HttpContext) SetRequest(r *http.Request) {
+	c.request = r
+}
+
+// Context returns the request's context.Context. It is canceled when the
+// client disconnects or the request completes, and carries any deadline set
+// by middleware, so pass it to downstream calls (database queries, outgoing
+// HTTP requests) to propagate cancellation.
+//
+// It is shorthand for ctx.Request().Context().
+func (c *HttpContext) Context() context.Context {
+	return c.request.Context()
+}
+
 // Response returns the underlying http.ResponseWriter object.
 func (c *HttpContext) Response() http.ResponseWriter {
 	return c.responseWriter
 }
 
+// SetResponse replaces the underlying http.ResponseWriter. Middleware uses it
+// to wrap the writer (for example to buffer or inspect the response) before
+// calling the next handler; all response helpers write through the new writer.
+func (c *HttpContext) SetResponse(w http.ResponseWriter) {
+	c.responseWriter = w
+}
+
+// Logger returns a logger for the request, derived from slog.Default with
+// the fields "method", "path" and, for routed requests, "route" (see
+// RoutePattern). Middleware add their own fields with SetLogger, e.g.
+// RequestIDMiddleware adds "request-id", so that every log line of a
+// request can be correlated.
+//
+// Example:
+//
+//	ctx.Logger().Info("created user", "id", id)
+func (c *HttpContext) Logger() *slog.Logger {
+	if c.logger == nil {
+		attrs := []any{
+			slog.String("method", c.Method()),
+			slog.String("path", c.Request().URL.Path),
+		}
+		if route := c.RoutePattern(); route != "" {
+			attrs = append(attrs, slog.String("route", route))
+		}
+		c.logger = slog.Default().With(attrs...)
+	}
+	return c.logger
+}
+
+// SetLogger replaces the request's logger. Middleware typically add fields
+// to the current one:
+//
+//	ctx.SetLogger(ctx.Logger().With("user", user.ID))
+func (c *HttpContext) SetLogger(logger *slog.Logger) {
+	c.logger = logger
+}
+
 // ============================
 // Request Information Methods
 // ============================
@@ -134,6 +301,27 @@ func (c *HttpContext) SetPath(path string) {
 	c.path = path
 }
 
+// RoutePattern returns the pattern of the route that matched the request,
+// e.g. "/users/{id}", without the method. Unlike Path it has a bounded set
+// of values, which makes it suitable for metrics labels and logs. It is
+// empty if the request was not routed by a ServeMux.
+func (c *HttpContext) RoutePattern() string {
+	if c.routePattern == "" {
+		pattern := c.Request().Pattern
+		if i := strings.IndexAny(pattern, " \t"); i >= 0 {
+			pattern = strings.TrimLeft(pattern[i:], " \t")
+		}
+		c.routePattern = pattern
+	}
+	return c.routePattern
+}
+
+// RouteName returns the name the matched route was registered with via
+// Mux.Get, Mux.Post, etc. It is empty for unnamed routes.
+func (c *HttpContext) RouteName() string {
+	return c.routeName
+}
+
 // ============================
 // Request Parameter Methods
 // ============================
@@ -155,6 +343,74 @@ func (c *HttpContext) Param(key string) string {
 	return c.Request().PathValue(key)
 }
 
+// ParamInt returns the path parameter parsed as a base-10 int. A missing
+// parameter yields an erm required error and a malformed one an erm
+// "integer" validation error, both with status 400 and the parameter name
+// as field, so handlers can return them as is.
+//
+// Example:
+//
+//	id, err := ctx.ParamInt("id")
+//	if err != nil {
+//	    return err
+//	}
+func (c *HttpContext) ParamInt(key string) (int, error) {
+	n, err := c.ParamInt64(key)
+	if err == nil && int64(int(n)) != n {
+		err = erm.NewValidationError(erm.MsgInteger, key, c.Param(key))
+	}
+	if err != nil {
+		return 0, err
+	}
+	return int(n), nil
+}
+
+// ParamInt64 returns the path parameter parsed as a base-10 int64, with
+// the same errors as ParamInt.
+func (c *HttpContext) ParamInt64(key string) (int64, error) {
+	value := c.Param(key)
+	if value == "" {
+		return 0, erm.RequiredError(key, value)
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, erm.NewValidationError(erm.MsgInteger, key, value)
+	}
+	return n, nil
+}
+
+// ParamUUID returns the path parameter if it is a UUID in the canonical
+// 8-4-4-4-12 hex form, lowercased. A missing parameter yields an erm
+// required error and a malformed one an erm "uuid" validation error.
+func (c *HttpContext) ParamUUID(key string) (string, error) {
+	value := c.Param(key)
+	if value == "" {
+		return "", erm.RequiredError(key, value)
+	}
+	if !isUUID(value) {
+		return "", erm.NewValidationError(erm.MsgUUID, key, value)
+	}
+	return strings.ToLower(value), nil
+}
+
+// isUUID reports whether s is a UUID in the 8-4-4-4-12 hex form.
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case i == 8 || i == 13 || i == 18 || i == 23:
+			if c != '-' {
+				return false
+			}
+		case !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'):
+			return false
+		}
+	}
+	return true
+}
+
 // FormValue returns the value of the specified form parameter.
 // It parses the form data if not already parsed.
 func (c *HttpContext) FormValue(key string) string {
@@ -217,7 +473,88 @@ func (c *HttpContext) SetCookie(cookie *http.Cookie) {
 func (c *HttpContext) JSON(code int, v interface{}) error {
 	c.SetHeader(HeaderContentType, MIMEApplicationJSON)
 	c.Response().WriteHeader(code)
-	return json.NewEncoder(c.Response()).Encode(v)
+	return c.jsonEncoder().Encode(v)
+}
+
+// JSONPretty writes an indented JSON response with the specified status code,
+// using indent for each nesting level (e.g. "  "). It is intended for
+// human-read endpoints such as debug pages; JSON stays compact.
+// The Content-Type header is automatically set to "application/json".
+func (c *HttpContext) JSONPretty(code int, v interface{}, indent string) error {
+	c.SetHeader(HeaderContentType, MIMEApplicationJSON)
+	c.Response().WriteHeader(code)
+	encoder := c.jsonEncoder()
+	encoder.SetIndent("", indent)
+	return encoder.Encode(v)
+}
+
+// SetEscapeHTML controls whether JSON and JSONPretty escape <, > and & as
+// \u003c, \u003e and \u0026. Escaping is enabled by default because it makes
+// JSON safe to embed in HTML; disable it for clients that cannot handle the
+// unicode escapes, e.g. in URLs with query strings. JSONP always escapes.
+//
+// Use Mux.EscapeHTML to change the default for all routes of a Mux.
+func (c *HttpContext) SetEscapeHTML(escape bool) {
+	c.disableHTMLEscape = !escape
+}
+
+// jsonEncoder returns a JSON encoder for the response writer, honoring the
+// SetEscapeHTML setting.
+func (c *HttpContext) jsonEncoder() *json.Encoder {
+	encoder := json.NewEncoder(c.Response())
+	encoder.SetEscapeHTML(!c.disableHTMLEscape)
+	return encoder
+}
+
+// JSONP writes a JSONP response: v encoded as JSON and wrapped in a call to
+// callback. The Content-Type header is set to "application/javascript".
+//
+// The callback usually comes from the query string, so it is validated to be
+// a dotted JavaScript identifier (e.g. "cb" or "jQuery.handlers.cb_1") of at
+// most 128 characters; anything else is rejected with a 400 Bad Request
+// erm.Error wrapping ErrInvalidJSONPCallback. As additional hardening the body
+// starts with an empty comment, which defeats content-sniffing attacks such as
+// Rosetta Flash, and X-Content-Type-Options is set to "nosniff".
+//
+// Example:
+//
+//	return ctx.JSONP(200, ctx.QueryParam("callback"), data)
+func (c *HttpContext) JSONP(code int, callback string, v interface{}) error {
+	if !isValidJSONPCallback(callback) {
+		return erm.BadRequest("invalid JSONP callback", ErrInvalidJSONPCallback)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	c.SetHeader(HeaderContentType, MIMEApplicationJavaScriptCharsetUTF8)
+	c.SetHeader(HeaderXContentTypeOptions, "nosniff")
+	c.Response().WriteHeader(code)
+	_, err = c.Response().Write([]byte("/**/" + callback + "(" + string(data) + ");"))
+	return err
+}
+
+// isValidJSONPCallback reports whether callback is a non-empty, dot-separated
+// sequence of JavaScript identifiers made of ASCII letters, digits, "_" and "$".
+func isValidJSONPCallback(callback string) bool {
+	if callback == "" || len(callback) > 128 {
+		return false
+	}
+	for _, part := range strings.Split(callback, ".") {
+		if part == "" {
+			return false
+		}
+		for i, r := range part {
+			switch {
+			case r == '_' || r == '$' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'):
+			case r >= '0' && r <= '9' && i > 0:
+			default:
+				return false
+			}
+		}
+	}
+	return true
 }
 
 // String writes a plain text response with the specified status code.
@@ -250,8 +587,21 @@ func (c *HttpContext) HTMLBlob(code int, blob []byte) error {
 // Redirect sends an HTTP redirect response with the specified status code and URL.
 // Common status codes are 301 (permanent), 302 (found), 303 (see other),
 // 307 (temporary), and 308 (permanent redirect).
+//
+// It returns an error wrapping ErrInvalidRedirectCode, without writing
+// anything, if code is not in the 3xx range.
 func (c *HttpContext) Redirect(code int, url string) error {
-	c.SetHeader("Location", url)
+	if code < http.StatusMultipleChoices || code > http.StatusPermanentRedirect {
+		return fmt.Errorf("%w: %d", ErrInvalidRedirectCode, code)
+	}
+	c.SetHeader(HeaderLocation, url)
+	c.Response().WriteHeader(code)
+	return nil
+}
+
+// NoContent sends a response with the given status code and no body,
+// typically 204 No Content for DELETE handlers.
+func (c *HttpContext) NoContent(code int) error {
 	c.Response().WriteHeader(code)
 	return nil
 }
@@ -260,3 +610,113 @@ func (c *HttpContext) Redirect(code int, url string) error {
 func (c *HttpContext) WriteHeader(code int) {
 	c.Response().WriteHeader(code)
 }
+
+// EarlyHints sends a 103 Early Hints informational response carrying the
+// given Link header values, so that browsers can start preloading assets
+// while the final response is still being prepared. The links stay set and
+// are repeated on the final response.
+//
+// Early hints are only sent over HTTP/2 and later; on HTTP/1.x, where some
+// clients and proxies mishandle 1xx responses, EarlyHints is a no-op.
+//
+// Example:
+//
+//	ctx.EarlyHints(
+//		"</static/app.css>; rel=preload; as=style",
+//		"</static/app.js>; rel=preload; as=script",
+//	)
+//	return ctx.HTML(200, renderPage())
+func (c *HttpContext) EarlyHints(links ...string) {
+	if len(links) == 0 || c.Request().ProtoMajor < 2 {
+		return
+	}
+	for _, link := range links {
+		c.AddHeader(HeaderLink, link)
+	}
+	c.Response().WriteHeader(http.StatusEarlyHints)
+}
+
+// ============================
+// Error Methods
+// ============================
+
+// Error returns an erm error with the given HTTP status code and user-facing
+// message, for handlers to return instead of building it with erm.New. The
+// Mux's default error handler responds with the status and message of erm
+// errors below 500.
+//
+// Example:
+//
+//	if !allowed {
+//		return ctx.Error(http.StatusForbidden, "not allowed to edit this post")
+//	}
+func (c *HttpContext) Error(code int, msg string) erm.Error {
+	return erm.New(code, msg, nil)
+}
+
+// BadRequest returns a 400 Bad Request erm error with the given message.
+//
+// Example:
+//
+//	id, err := strconv.Atoi(ctx.Param("id"))
+//	if err != nil {
+//		return ctx.BadRequest("invalid id")
+//	}
+func (c *HttpContext) BadRequest(msg string) erm.Error {
+	return erm.BadRequest(msg, nil)
+}
+
+// NotFound returns a 404 Not Found erm error with the given message.
+//
+// Example:
+//
+//	user, ok := users[id]
+//	if !ok {
+//		return ctx.NotFound("user not found")
+//	}
+func (c *HttpContext) NotFound(msg string) erm.Error {
+	return erm.New(http.StatusNotFound, msg, nil)
+}
+
+// WriteValidationError writes err as a JSON object mapping field names to
+// messages, the format of erm.Error.ErrMap, with the status code err carries:
+// 400 for vix validation errors unless changed with vix.SetValidationStatus.
+// Messages are localized in the language stored under "locale" by
+// LocaleMiddleware, if any. An error that does not wrap an erm.Error is not
+// written but returned as a 500 erm error, so that the handler returns it to
+// the error handler without exposing its message. Nothing is written if err is
+// nil.
+//
+// Example:
+//
+//	result := vix.Form().
+//		Add(vix.String(req.Email, "email").Required().Email()).
+//		Validate()
+//	if !result.Valid() {
+//		return srv.WriteValidationError(ctx, result.Error()) // {"email": ["email is required"]}
+//	}
+func WriteValidationError(ctx Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var ermErr erm.Error
+	if !errors.As(err, &ermErr) {
+		return erm.Wrap(err)
+	}
+
+	var errMap map[string][]string
+	locale, hasLocale := ContextValue[language.Tag](ctx, "locale")
+	if hasLocale {
+		errMap = ermErr.LocalizedErrMap(locale)
+	} else {
+		errMap = ermErr.ErrMap()
+	}
+	if errMap == nil {
+		// No message key: report the safe message under the key ErrMap uses
+		// for errors without a field name
+		errMap = map[string][]string{"error": {erm.Message(ermErr)}}
+	}
+
+	return ctx.JSON(ermErr.Code(), errMap)
+}
@@ -1,15 +1,28 @@
 package srv
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
 	"net/url"
+	"reflect"
 	"strings"
 	"sync"
 	"testing"
+
+	"github.com/c3p0-box/utils/erm"
+	"github.com/c3p0-box/utils/i18n"
+	"golang.org/x/text/language"
 )
 
 // ============================
@@ -55,6 +68,37 @@ func TestNewHttpContext(t *testing.T) {
 	}
 }
 
+func TestNewTestContext(t *testing.T) {
+	getUser := func(ctx Context) error {
+		id, err := ctx.ParamInt("id")
+		if err != nil {
+			return err
+		}
+		return ctx.JSON(http.StatusOK, map[string]int{"id": id})
+	}
+
+	req := httptest.NewRequest("GET", "/users/42?fields=name", nil)
+	req.SetPathValue("id", "42")
+	rec := httptest.NewRecorder()
+
+	ctx := NewTestContext(req, rec)
+	if err := getUser(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != `{"id":42}` {
+		t.Errorf("Unexpected response: %d %s", rec.Code, rec.Body.String())
+	}
+	if ctx.QueryParam("fields") != "name" {
+		t.Errorf("Expected query parameter 'name', got '%s'", ctx.QueryParam("fields"))
+	}
+
+	req = httptest.NewRequest("GET", "/users/abc", nil)
+	req.SetPathValue("id", "abc")
+	if err := getUser(NewTestContext(req, httptest.NewRecorder())); erm.Status(err) != http.StatusBadRequest {
+		t.Errorf("Expected 400 error, got %v", err)
+	}
+}
+
 func TestHttpContext_ValueStore(t *testing.T) {
 	req := httptest.NewRequest("GET", "/test", nil)
 	rec := httptest.NewRecorder()
@@ -125,6 +169,121 @@ func TestHttpContext_ValueStore_ThreadSafety(t *testing.T) {
 	// If we get here without panicking, the thread safety test passed
 }
 
+func TestContextValue(t *testing.T) {
+	ctx := NewHttpContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/test", nil))
+	ctx.Set("count", 42)
+	ctx.Set("session", &Session{ID: "abc"})
+
+	if v, ok := ContextValue[int](ctx, "count"); !ok || v != 42 {
+		t.Errorf("Expected 42, true; got %v, %v", v, ok)
+	}
+	if session, ok := ContextValue[*Session](ctx, "session"); !ok || session.ID != "abc" {
+		t.Errorf("Expected session 'abc', got %v, %v", session, ok)
+	}
+	if v, ok := ContextValue[string](ctx, "count"); ok || v != "" {
+		t.Errorf("Expected zero value and false on type mismatch, got %q, %v", v, ok)
+	}
+	if v, ok := ContextValue[*Session](ctx, "missing"); ok || v != nil {
+		t.Errorf("Expected nil and false for missing key, got %v, %v", v, ok)
+	}
+}
+
+func TestHttpContext_SetContext(t *testing.T) {
+	type ctxKey struct{}
+
+	t.Run("value visible through request context", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/test", nil)
+		ctx := NewHttpContext(httptest.NewRecorder(), req)
+
+		ctx.SetContext(ctxKey{}, "trace-123")
+
+		if val := ctx.Request().Context().Value(ctxKey{}); val != "trace-123" {
+			t.Errorf("Expected 'trace-123', got %v", val)
+		}
+		if req.Context().Value(ctxKey{}) != nil {
+			t.Error("Expected original request to be left untouched")
+		}
+	})
+
+	t.Run("propagates from middleware to handler", func(t *testing.T) {
+		mux := NewMux()
+		mux.Middleware(func(next HandlerFunc) HandlerFunc {
+			return func(ctx Context) error {
+				ctx.SetContext(ctxKey{}, "from-middleware")
+				return next(ctx)
+			}
+		})
+		mux.Get("", "/items/{id}", func(ctx Context) error {
+			val, _ := ctx.Request().Context().Value(ctxKey{}).(string)
+			return ctx.String(http.StatusOK, val+":"+ctx.Param("id"))
+		})
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest("GET", "/items/42", nil))
+
+		if rec.Body.String() != "from-middleware:42" {
+			t.Errorf("Expected 'from-middleware:42', got %q", rec.Body.String())
+		}
+	})
+}
+
+func TestHttpContext_Context(t *testing.T) {
+	type ctxKey struct{}
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	ctx := NewHttpContext(httptest.NewRecorder(), req)
+
+	if ctx.Context() != req.Context() {
+		t.Error("Expected Context to return the request context")
+	}
+
+	cancelCtx, cancel := context.WithCancel(ctx.Context())
+	ctx.SetRequest(ctx.Request().WithContext(cancelCtx))
+	cancel()
+
+	select {
+	case <-ctx.Context().Done():
+	default:
+		t.Error("Expected swapped context to be canceled")
+	}
+
+	ctx.SetContext(ctxKey{}, "value")
+	if ctx.Context().Value(ctxKey{}) != "value" {
+		t.Error("Expected Context to reflect SetContext values")
+	}
+}
+
+func TestHttpContext_Logger(t *testing.T) {
+	var buf bytes.Buffer
+	oldLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	defer slog.SetDefault(oldLogger)
+
+	ctx := NewHttpContext(httptest.NewRecorder(), httptest.NewRequest("DELETE", "/items/9", nil))
+	if ctx.Logger() != ctx.Logger() {
+		t.Error("Expected Logger to return the same logger on each call")
+	}
+	ctx.Logger().Info("first")
+	ctx.SetLogger(ctx.Logger().With("user", "alice"))
+	ctx.Logger().Info("second")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 log lines, got %d: %s", len(lines), buf.String())
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, "method=DELETE") || !strings.Contains(line, "path=/items/9") {
+			t.Errorf("Expected request fields in %q", line)
+		}
+		if strings.Contains(line, "route=") {
+			t.Errorf("Expected no route field for unrouted request in %q", line)
+		}
+	}
+	if strings.Contains(lines[0], "user=") || !strings.Contains(lines[1], "user=alice") {
+		t.Errorf("Expected SetLogger to add fields to later lines only: %v", lines)
+	}
+}
+
 func TestHttpContext_RequestInformation(t *testing.T) {
 	t.Run("basic request info", func(t *testing.T) {
 		req := httptest.NewRequest("POST", "/api/test", nil)
@@ -232,6 +391,58 @@ func TestHttpContext_Parameters(t *testing.T) {
 	})
 }
 
+func TestHttpContext_TypedParams(t *testing.T) {
+	newCtx := func(value string) *HttpContext {
+		req := httptest.NewRequest("GET", "/items/x", nil)
+		if value != "" {
+			req.SetPathValue("id", value)
+		}
+		return NewHttpContext(httptest.NewRecorder(), req)
+	}
+
+	tests := []struct {
+		name        string
+		value       string
+		parse       func(ctx Context) (interface{}, error)
+		expected    interface{}
+		expectedKey string
+	}{
+		{"int", "42", func(ctx Context) (interface{}, error) { return ctx.ParamInt("id") }, 42, ""},
+		{"negative int", "-7", func(ctx Context) (interface{}, error) { return ctx.ParamInt("id") }, -7, ""},
+		{"int not a number", "abc", func(ctx Context) (interface{}, error) { return ctx.ParamInt("id") }, 0, erm.MsgInteger},
+		{"int with fraction", "1.5", func(ctx Context) (interface{}, error) { return ctx.ParamInt("id") }, 0, erm.MsgInteger},
+		{"int missing", "", func(ctx Context) (interface{}, error) { return ctx.ParamInt("id") }, 0, erm.MsgRequired},
+		{"int64", "9007199254740993", func(ctx Context) (interface{}, error) { return ctx.ParamInt64("id") }, int64(9007199254740993), ""},
+		{"int64 overflow", "9223372036854775808", func(ctx Context) (interface{}, error) { return ctx.ParamInt64("id") }, int64(0), erm.MsgInteger},
+		{"uuid", "0F8FAD5B-D9CB-469F-A165-70867728950E", func(ctx Context) (interface{}, error) { return ctx.ParamUUID("id") }, "0f8fad5b-d9cb-469f-a165-70867728950e", ""},
+		{"uuid malformed", "0f8fad5b-d9cb-469f-a165-70867728950", func(ctx Context) (interface{}, error) { return ctx.ParamUUID("id") }, "", erm.MsgUUID},
+		{"uuid bad separator", "0f8fad5b_d9cb-469f-a165-70867728950e", func(ctx Context) (interface{}, error) { return ctx.ParamUUID("id") }, "", erm.MsgUUID},
+		{"uuid missing", "", func(ctx Context) (interface{}, error) { return ctx.ParamUUID("id") }, "", erm.MsgRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.parse(newCtx(tt.value))
+			if got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+			if tt.expectedKey == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			var e erm.Error
+			if !errors.As(err, &e) {
+				t.Fatalf("Expected erm.Error, got %v", err)
+			}
+			if e.MessageKey() != tt.expectedKey || e.FieldName() != "id" || erm.Status(e) != http.StatusBadRequest {
+				t.Errorf("Unexpected error: key=%s field=%s status=%d", e.MessageKey(), e.FieldName(), erm.Status(e))
+			}
+		})
+	}
+}
+
 func TestHttpContext_Headers(t *testing.T) {
 	req := httptest.NewRequest("GET", "/test", nil)
 	req.Header.Set("Authorization", "Bearer token123")
@@ -437,6 +648,91 @@ func TestHttpContext_ResponseMethods(t *testing.T) {
 		}
 	})
 
+	t.Run("JSONPretty response", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/debug/vars", nil)
+		rec := httptest.NewRecorder()
+		ctx := NewHttpContext(rec, req)
+
+		if err := ctx.JSONPretty(200, map[string]int{"a": 1, "b": 2}, "  "); err != nil {
+			t.Fatalf("JSONPretty failed: %v", err)
+		}
+
+		if ct := rec.Header().Get(HeaderContentType); ct != MIMEApplicationJSON {
+			t.Errorf("Expected Content-Type %q, got %q", MIMEApplicationJSON, ct)
+		}
+		expected := "{\n  \"a\": 1,\n  \"b\": 2\n}\n"
+		if rec.Body.String() != expected {
+			t.Errorf("Expected body %q, got %q", expected, rec.Body.String())
+		}
+	})
+
+	t.Run("JSON HTML escaping", func(t *testing.T) {
+		data := map[string]string{"url": "/search?q=<a>&page=2"}
+		tests := []struct {
+			name     string
+			escape   bool
+			expected string
+		}{
+			{name: "escaped by default", escape: true, expected: `{"url":"/search?q=\u003ca\u003e\u0026page=2"}`},
+			{name: "escaping disabled", escape: false, expected: `{"url":"/search?q=<a>&page=2"}`},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				req := httptest.NewRequest("GET", "/test", nil)
+				rec := httptest.NewRecorder()
+				ctx := NewHttpContext(rec, req)
+				if !tt.escape {
+					ctx.SetEscapeHTML(false)
+				}
+
+				if err := ctx.JSON(200, data); err != nil {
+					t.Fatalf("JSON failed: %v", err)
+				}
+				if got := strings.TrimSpace(rec.Body.String()); got != tt.expected {
+					t.Errorf("Expected body %s, got %s", tt.expected, got)
+				}
+			})
+		}
+	})
+
+	t.Run("JSONP response", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/data?callback=app.cb_1", nil)
+		rec := httptest.NewRecorder()
+		ctx := NewHttpContext(rec, req)
+
+		if err := ctx.JSONP(200, ctx.QueryParam("callback"), map[string]string{"msg": "</script>"}); err != nil {
+			t.Fatalf("JSONP failed: %v", err)
+		}
+
+		if ct := rec.Header().Get(HeaderContentType); ct != MIMEApplicationJavaScriptCharsetUTF8 {
+			t.Errorf("Expected Content-Type %q, got %q", MIMEApplicationJavaScriptCharsetUTF8, ct)
+		}
+		if nosniff := rec.Header().Get(HeaderXContentTypeOptions); nosniff != "nosniff" {
+			t.Errorf("Expected X-Content-Type-Options nosniff, got %q", nosniff)
+		}
+		expected := `/**/app.cb_1({"msg":"\u003c/script\u003e"});`
+		if rec.Body.String() != expected {
+			t.Errorf("Expected body %q, got %q", expected, rec.Body.String())
+		}
+	})
+
+	t.Run("JSONP rejects unsafe callbacks", func(t *testing.T) {
+		for _, callback := range []string{"", "alert(1)//", "a..b", "1cb", "cb;", "cb\u2028", strings.Repeat("a", 129)} {
+			req := httptest.NewRequest("GET", "/data", nil)
+			rec := httptest.NewRecorder()
+			ctx := NewHttpContext(rec, req)
+
+			err := ctx.JSONP(200, callback, "data")
+			if !errors.Is(err, ErrInvalidJSONPCallback) || erm.Status(err) != http.StatusBadRequest {
+				t.Errorf("JSONP(%q): expected 400 ErrInvalidJSONPCallback, got %v", callback, err)
+			}
+			if rec.Body.Len() != 0 {
+				t.Errorf("JSONP(%q): expected nothing written, got %q", callback, rec.Body.String())
+			}
+		}
+	})
+
 	t.Run("Redirect response", func(t *testing.T) {
 		req := httptest.NewRequest("GET", "/old-page", nil)
 		rec := httptest.NewRecorder()
@@ -454,6 +750,38 @@ func TestHttpContext_ResponseMethods(t *testing.T) {
 		}
 	})
 
+	t.Run("Redirect rejects non-3xx status", func(t *testing.T) {
+		for _, code := range []int{200, 299, 309, 404} {
+			req := httptest.NewRequest("GET", "/old-page", nil)
+			rec := httptest.NewRecorder()
+			ctx := NewHttpContext(rec, req)
+
+			err := ctx.Redirect(code, "/new-page")
+			if !errors.Is(err, ErrInvalidRedirectCode) {
+				t.Errorf("Redirect(%d): expected ErrInvalidRedirectCode, got %v", code, err)
+			}
+			if rec.Header().Get(HeaderLocation) != "" {
+				t.Errorf("Redirect(%d): expected no Location header", code)
+			}
+		}
+	})
+
+	t.Run("NoContent", func(t *testing.T) {
+		req := httptest.NewRequest("DELETE", "/items/1", nil)
+		rec := httptest.NewRecorder()
+		ctx := NewHttpContext(rec, req)
+
+		if err := ctx.NoContent(204); err != nil {
+			t.Fatalf("NoContent failed: %v", err)
+		}
+		if rec.Code != 204 {
+			t.Errorf("Expected status code 204, got %d", rec.Code)
+		}
+		if rec.Body.Len() != 0 {
+			t.Errorf("Expected empty body, got %q", rec.Body.String())
+		}
+	})
+
 	t.Run("WriteHeader", func(t *testing.T) {
 		req := httptest.NewRequest("GET", "/test", nil)
 		rec := httptest.NewRecorder()
@@ -467,6 +795,306 @@ func TestHttpContext_ResponseMethods(t *testing.T) {
 	})
 }
 
+func TestHttpContext_Upgrade(t *testing.T) {
+	const key = "dGhlIHNhbXBsZSBub25jZQ=="
+
+	t.Run("handshake and hijacked connection", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, rw, err := NewHttpContext(w, r).Upgrade("chat.v2", "chat.v1")
+			if err != nil {
+				t.Errorf("Upgrade failed: %v", err)
+				return
+			}
+			defer conn.Close()
+			line, _ := rw.ReadString('\n')
+			_, _ = rw.WriteString("echo " + line)
+			_ = rw.Flush()
+		}))
+		defer server.Close()
+
+		conn, err := net.Dial("tcp", server.Listener.Addr().String())
+		if err != nil {
+			t.Fatalf("Dial failed: %v", err)
+		}
+		defer conn.Close()
+
+		fmt.Fprintf(conn, "GET / HTTP/1.1\r\nHost: example.com\r\nUpgrade: websocket\r\n"+
+			"Connection: keep-alive, Upgrade\r\nSec-WebSocket-Key: %s\r\n"+
+			"Sec-WebSocket-Version: 13\r\nSec-WebSocket-Protocol: chat.v1, chat.v2\r\n\r\n", key)
+
+		reader := bufio.NewReader(conn)
+		resp, err := http.ReadResponse(reader, nil)
+		if err != nil {
+			t.Fatalf("ReadResponse failed: %v", err)
+		}
+		if resp.StatusCode != http.StatusSwitchingProtocols {
+			t.Fatalf("Expected status 101, got %d", resp.StatusCode)
+		}
+		// Accept value from RFC 6455, section 1.3.
+		if got := resp.Header.Get(HeaderSecWebSocketAccept); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+			t.Errorf("Unexpected Sec-WebSocket-Accept %q", got)
+		}
+		// The server's preference wins over the client's offer order.
+		if got := resp.Header.Get(HeaderSecWebSocketProtocol); got != "chat.v2" {
+			t.Errorf("Expected protocol chat.v2, got %q", got)
+		}
+
+		fmt.Fprint(conn, "ping\n")
+		line, err := reader.ReadString('\n')
+		if err != nil || line != "echo ping\n" {
+			t.Errorf("Expected echoed line over hijacked connection, got %q (%v)", line, err)
+		}
+	})
+
+	tests := []struct {
+		name     string
+		method   string
+		headers  map[string]string
+		expected int
+	}{
+		{
+			name:     "not GET",
+			method:   "POST",
+			headers:  map[string]string{"Upgrade": "websocket", "Connection": "Upgrade", "Sec-WebSocket-Version": "13", "Sec-WebSocket-Key": key},
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "missing upgrade header",
+			method:   "GET",
+			headers:  map[string]string{"Connection": "Upgrade", "Sec-WebSocket-Version": "13", "Sec-WebSocket-Key": key},
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "unsupported version",
+			method:   "GET",
+			headers:  map[string]string{"Upgrade": "websocket", "Connection": "Upgrade", "Sec-WebSocket-Version": "8", "Sec-WebSocket-Key": key},
+			expected: http.StatusUpgradeRequired,
+		},
+		{
+			name:     "invalid key",
+			method:   "GET",
+			headers:  map[string]string{"Upgrade": "websocket", "Connection": "Upgrade", "Sec-WebSocket-Version": "13", "Sec-WebSocket-Key": "short"},
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "cross-origin",
+			method:   "GET",
+			headers:  map[string]string{"Upgrade": "websocket", "Connection": "Upgrade", "Sec-WebSocket-Version": "13", "Sec-WebSocket-Key": key, "Origin": "https://evil.example"},
+			expected: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/", nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			rec := httptest.NewRecorder()
+
+			_, _, err := NewHttpContext(rec, req).Upgrade()
+			if !errors.Is(err, ErrWebSocketHandshake) {
+				t.Fatalf("Expected ErrWebSocketHandshake, got %v", err)
+			}
+			if status := erm.Status(err); status != tt.expected {
+				t.Errorf("Expected status %d, got %d", tt.expected, status)
+			}
+		})
+	}
+}
+
+func TestSameOrigin(t *testing.T) {
+	tests := []struct {
+		name   string
+		origin string
+		want   bool
+	}{
+		{"no origin", "", true},
+		{"same host", "https://example.com", true},
+		{"same host different case", "https://EXAMPLE.com", true},
+		{"other host", "https://evil.example", false},
+		{"other port", "https://example.com:8443", false},
+		{"invalid origin", "://bad", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://example.com/ws", nil)
+			if tt.origin != "" {
+				req.Header.Set(HeaderOrigin, tt.origin)
+			}
+			if got := SameOrigin(req); got != tt.want {
+				t.Errorf("SameOrigin() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("custom CheckOrigin", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "http://example.com/ws", nil)
+		req.Header.Set(HeaderUpgrade, "websocket")
+		req.Header.Set(HeaderConnection, "Upgrade")
+		req.Header.Set(HeaderSecWebSocketVersion, "13")
+		req.Header.Set(HeaderSecWebSocketKey, "dGhlIHNhbXBsZSBub25jZQ==")
+		req.Header.Set(HeaderOrigin, "https://app.example.org")
+
+		ctx := NewHttpContext(httptest.NewRecorder(), req)
+		_, _, err := ctx.UpgradeWithConfig(WebSocketConfig{
+			CheckOrigin: func(r *http.Request) bool { return r.Header.Get(HeaderOrigin) == "https://app.example.org" },
+		})
+		// The origin is accepted; the recorder cannot be hijacked.
+		if err == nil || erm.Status(err) == http.StatusForbidden {
+			t.Errorf("Expected hijack error after accepted origin, got %v", err)
+		}
+	})
+}
+
+func TestHttpContext_EarlyHints(t *testing.T) {
+	const link = "</app.css>; rel=preload; as=style"
+
+	t.Run("sent over HTTP/2", func(t *testing.T) {
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := NewHttpContext(w, r)
+			ctx.EarlyHints(link)
+			_ = ctx.String(http.StatusOK, "page")
+		}))
+		server.EnableHTTP2 = true
+		server.StartTLS()
+		defer server.Close()
+
+		var hints []int
+		var hintLinks []string
+		trace := &httptrace.ClientTrace{
+			Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+				hints = append(hints, code)
+				hintLinks = append(hintLinks, header.Values(HeaderLink)...)
+				return nil
+			},
+		}
+		req, _ := http.NewRequestWithContext(httptrace.WithClientTrace(context.Background(), trace), "GET", server.URL, nil)
+		resp, err := server.Client().Do(req)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.ProtoMajor != 2 {
+			t.Fatalf("Expected HTTP/2, got %s", resp.Proto)
+		}
+		if len(hints) != 1 || hints[0] != http.StatusEarlyHints {
+			t.Fatalf("Expected one 103 response, got %v", hints)
+		}
+		if len(hintLinks) != 1 || hintLinks[0] != link {
+			t.Errorf("Expected Link %q in early hints, got %v", link, hintLinks)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected final status 200, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("no-op on HTTP/1.1", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		rec := httptest.NewRecorder()
+		ctx := NewHttpContext(rec, req)
+
+		ctx.EarlyHints(link)
+		_ = ctx.String(http.StatusOK, "page")
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", rec.Code)
+		}
+		if got := rec.Header().Get(HeaderLink); got != "" {
+			t.Errorf("Expected no Link header, got %q", got)
+		}
+	})
+}
+
+func TestWriteValidationError(t *testing.T) {
+	if err := i18n.AddTranslations(language.German, map[string]*i18n.Translation{
+		erm.MsgRequired: {Singular: "{{.field}} ist erforderlich"},
+	}); err != nil {
+		t.Fatalf("failed to add translations: %v", err)
+	}
+
+	container := erm.New(http.StatusUnprocessableEntity, "", nil)
+	container.AddError(erm.RequiredError("email", ""))
+	container.AddError(erm.MinLengthError("password", "123", 8))
+
+	tests := []struct {
+		name           string
+		err            error
+		locale         *language.Tag
+		expectedStatus int
+		expectedBody   map[string][]string
+	}{
+		{"container", container, nil, http.StatusUnprocessableEntity, map[string][]string{
+			"email":    {"email is required"},
+			"password": {"password must be at least 8 characters long"},
+		}},
+		{"single validation error", erm.RequiredError("name", ""), nil, http.StatusBadRequest, map[string][]string{
+			"name": {"name is required"},
+		}},
+		{"localized", erm.RequiredError("name", ""), &language.German, http.StatusBadRequest, map[string][]string{
+			"name": {"name ist erforderlich"},
+		}},
+		{"erm error without message key", erm.Conflict("already exists", errors.New("duplicate key")), nil, http.StatusConflict, map[string][]string{
+			"error": {"already exists"},
+		}},
+		{"wrapped container", fmt.Errorf("signup: %w", container), nil, http.StatusUnprocessableEntity, map[string][]string{
+			"email":    {"email is required"},
+			"password": {"password must be at least 8 characters long"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			ctx := NewTestContext(httptest.NewRequest("POST", "/signup", nil), rec)
+			if tt.locale != nil {
+				ctx.Set("locale", *tt.locale)
+			}
+
+			if err := WriteValidationError(ctx, tt.err); err != nil {
+				t.Fatalf("WriteValidationError() error = %v", err)
+			}
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.expectedStatus)
+			}
+			var body map[string][]string
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid JSON body %q: %v", rec.Body.String(), err)
+			}
+			if !reflect.DeepEqual(body, tt.expectedBody) {
+				t.Errorf("body = %v, want %v", body, tt.expectedBody)
+			}
+		})
+	}
+
+	t.Run("standard error", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		ctx := NewTestContext(httptest.NewRequest("POST", "/signup", nil), rec)
+		root := errors.New("connection refused")
+
+		err := WriteValidationError(ctx, root)
+		if !errors.Is(err, root) || erm.Status(err) != http.StatusInternalServerError {
+			t.Errorf("WriteValidationError() = %v (status %d), want a 500 wrapping the error", err, erm.Status(err))
+		}
+		if rec.Body.Len() != 0 {
+			t.Errorf("nothing should be written for a standard error, got %q", rec.Body.String())
+		}
+	})
+
+	t.Run("nil error", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		ctx := NewTestContext(httptest.NewRequest("POST", "/signup", nil), rec)
+		if err := WriteValidationError(ctx, nil); err != nil {
+			t.Fatalf("WriteValidationError(nil) error = %v", err)
+		}
+		if rec.Body.Len() != 0 {
+			t.Errorf("nothing should be written for a nil error, got %q", rec.Body.String())
+		}
+	})
+}
+
 // ============================
 // Benchmark Tests
 // ============================
@@ -0,0 +1,338 @@
+package srv
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/c3p0-box/utils/erm"
+)
+
+// HeaderIdempotencyKey is the request header carrying the client-generated
+// idempotency key.
+const HeaderIdempotencyKey = "Idempotency-Key"
+
+// HeaderIdempotentReplayed is set to "true" on responses replayed from the
+// idempotency store.
+const HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+// ErrIdempotencyInFlight is returned by IdempotencyStore.Reserve when another
+// request with the same key is still being processed.
+var ErrIdempotencyInFlight = errors.New("request with this idempotency key is in progress")
+
+// IdempotentResponse is a response captured by IdempotencyMiddleware.
+type IdempotentResponse struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// IdempotencyStore persists responses for IdempotencyMiddleware. Like the
+// session Store, it abstracts the storage backend so that an in-memory
+// implementation can be swapped for a shared one (e.g. Redis) when running
+// multiple instances.
+//
+// Implementations must be safe for concurrent use and Reserve must be atomic.
+type IdempotencyStore interface {
+	// Reserve claims key for a new request. It returns the cached response
+	// if one exists, ErrIdempotencyInFlight if the key is currently reserved
+	// by another request, or (nil, nil) if the key was reserved successfully.
+	Reserve(key string) (*IdempotentResponse, error)
+	// Save stores the response for a reserved key, completing the request.
+	Save(key string, response *IdempotentResponse) error
+	// Release drops the reservation for key without storing a response, so
+	// the request can be retried.
+	Release(key string) error
+}
+
+// IdempotencyMiddleware returns a HandlerFunc-based middleware that
+// de-duplicates retried requests carrying an Idempotency-Key header.
+//
+// The first request with a given key is executed normally and its response
+// (status, headers and body) is stored. Repeated requests with the same key,
+// method and path receive the stored response with an
+// "Idempotent-Replayed: true" header instead of executing the handler again.
+// A repeat that arrives while the first request is still running gets a
+// 409 Conflict.
+//
+// Responses are only stored for successful executions: if the handler returns
+// an error, panics, responds with a 5xx status or streams its response, the
+// reservation is released so the client can retry.
+//
+// Safe methods (GET, HEAD, OPTIONS, TRACE) and requests without the header are
+// passed through untouched.
+//
+// IdempotencyMiddleware uses DefaultIdempotencyConfig, which scopes keys to
+// the client IP, so one client is never replayed another's response. Use
+// IdempotencyMiddlewareWithConfig with a Scope function when requests are
+// authenticated, or with an IPResolver when running behind proxies.
+//
+// Example:
+//
+//	store := srv.NewInMemoryIdempotencyStore(24 * time.Hour)
+//	defer store.Close()
+//
+//	mux.Middleware(srv.IdempotencyMiddleware(store))
+//	mux.Post("charges", "/charges", createCharge)
+func IdempotencyMiddleware(store IdempotencyStore) HandlerFuncMiddleware {
+	return IdempotencyMiddlewareWithConfig(store, DefaultIdempotencyConfig)
+}
+
+// IdempotencyConfig defines the configuration for
+// IdempotencyMiddlewareWithConfig.
+type IdempotencyConfig struct {
+	// Skipper bypasses the middleware for requests for which it returns
+	// true, e.g. to exempt some paths.
+	//
+	// Optional. Default value nil (never skip).
+	Skipper Skipper
+
+	// Scope returns the owner of the request, such as the authenticated user
+	// ID or the client IP. It is part of the stored key, so a response is
+	// only replayed to requests with the same scope, method, path and key.
+	// It runs before the handler, so it can only use what earlier middleware
+	// put in the context.
+	//
+	// Optional. Default value nil (keys are scoped to the client IP).
+	Scope func(ctx Context) string
+
+	// IPResolver resolves the client IP used as the scope when Scope is nil.
+	// Configure it with the addresses of your load balancers or reverse
+	// proxies, otherwise all clients behind them share one scope.
+	//
+	// Optional. Default value nil (no trusted proxies; the host part of
+	// RemoteAddr is used).
+	IPResolver *IPResolver
+}
+
+// DefaultIdempotencyConfig is the default Idempotency middleware config.
+var DefaultIdempotencyConfig = IdempotencyConfig{
+	Skipper:    nil,
+	Scope:      nil,
+	IPResolver: nil,
+}
+
+// IdempotencyMiddlewareWithConfig returns an IdempotencyMiddleware with the
+// given config.
+//
+// Example:
+//
+//	mux.Middleware(srv.IdempotencyMiddlewareWithConfig(store, srv.IdempotencyConfig{
+//		Scope: func(ctx srv.Context) string {
+//			userID, _ := srv.ContextValue[string](ctx, "user-id")
+//			return userID
+//		},
+//	}))
+func IdempotencyMiddlewareWithConfig(store IdempotencyStore, config IdempotencyConfig) HandlerFuncMiddleware {
+	return skippable(config.Skipper, func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			req := ctx.Request()
+			key := req.Header.Get(HeaderIdempotencyKey)
+			if key == "" || isSafeMethod(req.Method) {
+				return next(ctx)
+			}
+			scope := config.IPResolver.ClientIP(req)
+			if config.Scope != nil {
+				scope = config.Scope(ctx)
+			}
+			key = scope + " " + req.Method + " " + req.URL.Path + " " + key
+
+			cached, err := store.Reserve(key)
+			if errors.Is(err, ErrIdempotencyInFlight) {
+				return erm.Conflict("request with this idempotency key is in progress", err)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to reserve idempotency key: %w", err)
+			}
+			if cached != nil {
+				return replayIdempotentResponse(ctx.Response(), cached)
+			}
+
+			original := ctx.Response()
+			// Headers set by outer middleware are already in place; only
+			// those added by the handler belong to the stored response.
+			before := original.Header().Clone()
+			buf := newBufferedResponseWriter(original)
+			ctx.SetResponse(buf)
+
+			completed := false
+			defer func() {
+				ctx.SetResponse(original)
+				// Release the reservation on errors and panics so that the
+				// client can retry.
+				if !completed {
+					_ = store.Release(key)
+				}
+			}()
+
+			err = next(ctx)
+
+			if err != nil || buf.Streaming() || buf.Status() >= http.StatusInternalServerError {
+				if flushErr := buf.flushBuffer(); err == nil {
+					err = flushErr
+				}
+				return err
+			}
+
+			response := &IdempotentResponse{
+				Status: buf.Status(),
+				Header: addedHeaders(before, original.Header()),
+				Body:   append([]byte(nil), buf.Body()...),
+			}
+			if saveErr := store.Save(key, response); saveErr != nil {
+				// The response is still sent; only the replay is lost.
+				ctx.Logger().With(
+					slog.String("name", "srv.Idempotency"),
+					slog.Any("error", saveErr),
+				).Error("failed to save idempotent response")
+			} else {
+				completed = true
+			}
+
+			return buf.flushBuffer()
+		}
+	})
+}
+
+// replayIdempotentResponse writes a stored response to w.
+func replayIdempotentResponse(w http.ResponseWriter, response *IdempotentResponse) error {
+	header := w.Header()
+	for name, values := range response.Header {
+		header[name] = append([]string(nil), values...)
+	}
+	header.Set(HeaderIdempotentReplayed, "true")
+	w.WriteHeader(response.Status)
+	_, err := w.Write(response.Body)
+	return err
+}
+
+// addedHeaders returns the headers of after that are new or changed
+// compared to before.
+func addedHeaders(before, after http.Header) http.Header {
+	added := make(http.Header)
+	for name, values := range after {
+		if !slices.Equal(before[name], values) {
+			added[name] = append([]string(nil), values...)
+		}
+	}
+	return added
+}
+
+// isSafeMethod reports whether method is a safe HTTP method (RFC 9110).
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// =============================================================================
+// In-Memory Idempotency Store
+// =============================================================================
+
+type idempotencyEntry struct {
+	response  *IdempotentResponse // nil while the request is in flight
+	expiresAt time.Time
+}
+
+// InMemoryIdempotencyStore is an IdempotencyStore that keeps responses in
+// memory for a fixed TTL. It is thread-safe and suitable for single-instance
+// deployments; use a shared store when running multiple instances.
+type InMemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	entries map[string]*idempotencyEntry
+	ttl     time.Duration
+	cleanup *time.Ticker
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewInMemoryIdempotencyStore creates an in-memory idempotency store that
+// keeps responses for ttl. Reservations of in-flight requests expire after the
+// same ttl. It starts a cleanup routine that removes expired entries; call
+// Close to stop it.
+func NewInMemoryIdempotencyStore(ttl time.Duration) *InMemoryIdempotencyStore {
+	store := &InMemoryIdempotencyStore{
+		entries: make(map[string]*idempotencyEntry),
+		ttl:     ttl,
+		cleanup: time.NewTicker(time.Minute),
+		done:    make(chan struct{}),
+	}
+
+	go store.cleanupExpired()
+
+	return store
+}
+
+// Reserve implements IdempotencyStore.
+func (s *InMemoryIdempotencyStore) Reserve(key string) (*IdempotentResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if entry, exists := s.entries[key]; exists && now.Before(entry.expiresAt) {
+		if entry.response == nil {
+			return nil, ErrIdempotencyInFlight
+		}
+		return entry.response, nil
+	}
+
+	s.entries[key] = &idempotencyEntry{expiresAt: now.Add(s.ttl)}
+	return nil, nil
+}
+
+// Save implements IdempotencyStore.
+func (s *InMemoryIdempotencyStore) Save(key string, response *IdempotentResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = &idempotencyEntry{
+		response:  response,
+		expiresAt: time.Now().Add(s.ttl),
+	}
+	return nil
+}
+
+// Release implements IdempotencyStore.
+func (s *InMemoryIdempotencyStore) Release(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry, exists := s.entries[key]; exists && entry.response == nil {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+// Close stops the cleanup routine and clears all entries.
+// This should be called when the store is no longer needed.
+func (s *InMemoryIdempotencyStore) Close() {
+	s.once.Do(func() {
+		s.cleanup.Stop()
+		close(s.done)
+	})
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = make(map[string]*idempotencyEntry)
+}
+
+// cleanupExpired removes expired entries from the store.
+func (s *InMemoryIdempotencyStore) cleanupExpired() {
+	for {
+		select {
+		case <-s.done:
+			return
+		case now := <-s.cleanup.C:
+			s.mu.Lock()
+			for key, entry := range s.entries {
+				if now.After(entry.expiresAt) {
+					delete(s.entries, key)
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package srv
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	store := NewInMemoryIdempotencyStore(time.Hour)
+	defer store.Close()
+
+	var calls int32
+	mux := NewMux()
+	mux.ErrorHandler(func(ctx Context, err error) {
+		_ = ctx.String(http.StatusInternalServerError, err.Error())
+	})
+	mux.Middleware(IdempotencyMiddleware(store))
+	mux.Post("", "/charges", func(ctx Context) error {
+		n := atomic.AddInt32(&calls, 1)
+		ctx.SetHeader("X-Charge", fmt.Sprint(n))
+		return ctx.String(http.StatusCreated, fmt.Sprintf("charge %d", n))
+	})
+	mux.Post("", "/flaky", func(ctx Context) error {
+		if atomic.AddInt32(&calls, 1)%2 == 1 {
+			return errors.New("temporary failure")
+		}
+		return ctx.String(http.StatusCreated, "ok")
+	})
+	mux.Get("", "/charges", func(ctx Context) error {
+		return ctx.String(http.StatusOK, fmt.Sprint(atomic.AddInt32(&calls, 1)))
+	})
+
+	do := func(method, path, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if key != "" {
+			req.Header.Set(HeaderIdempotencyKey, key)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("replays stored response", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		first := do("POST", "/charges", "key-1")
+		second := do("POST", "/charges", "key-1")
+
+		if calls != 1 {
+			t.Errorf("Expected handler to run once, ran %d times", calls)
+		}
+		if first.Code != http.StatusCreated || second.Code != http.StatusCreated {
+			t.Errorf("Expected status 201 twice, got %d and %d", first.Code, second.Code)
+		}
+		if second.Body.String() != "charge 1" || second.Header().Get("X-Charge") != "1" {
+			t.Errorf("Expected replayed body and headers, got %q and %q", second.Body.String(), second.Header().Get("X-Charge"))
+		}
+		if first.Header().Get(HeaderIdempotentReplayed) != "" {
+			t.Error("Expected first response not to be marked as replayed")
+		}
+		if second.Header().Get(HeaderIdempotentReplayed) != "true" {
+			t.Error("Expected second response to be marked as replayed")
+		}
+	})
+
+	t.Run("different keys execute separately", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		do("POST", "/charges", "key-a")
+		do("POST", "/charges", "key-b")
+		do("POST", "/charges", "")
+
+		if calls != 3 {
+			t.Errorf("Expected handler to run 3 times, ran %d times", calls)
+		}
+	})
+
+	t.Run("errors are not stored", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		first := do("POST", "/flaky", "key-2")
+		second := do("POST", "/flaky", "key-2")
+
+		if first.Code != http.StatusInternalServerError {
+			t.Errorf("Expected first status 500, got %d", first.Code)
+		}
+		if second.Code != http.StatusCreated {
+			t.Errorf("Expected retry to succeed with 201, got %d", second.Code)
+		}
+	})
+
+	t.Run("safe methods are not cached", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		do("GET", "/charges", "key-3")
+		do("GET", "/charges", "key-3")
+
+		if calls != 2 {
+			t.Errorf("Expected handler to run twice, ran %d times", calls)
+		}
+	})
+
+	t.Run("keys are scoped to the client IP", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		first := do("POST", "/charges", "key-5")
+
+		req := httptest.NewRequest("POST", "/charges", nil)
+		req.Header.Set(HeaderIdempotencyKey, "key-5")
+		req.RemoteAddr = "198.51.100.7:1234"
+		other := httptest.NewRecorder()
+		mux.ServeHTTP(other, req)
+
+		if calls != 2 {
+			t.Errorf("Expected handler to run once per client, ran %d times", calls)
+		}
+		if first.Body.String() != "charge 1" || other.Body.String() != "charge 2" {
+			t.Errorf("Expected separate responses per client, got %q and %q", first.Body.String(), other.Body.String())
+		}
+		if other.Header().Get(HeaderIdempotentReplayed) != "" {
+			t.Error("Expected another client's request not to be replayed")
+		}
+	})
+
+	t.Run("in-flight key conflicts", func(t *testing.T) {
+		if _, err := store.Reserve("192.0.2.1 POST /charges key-4"); err != nil {
+			t.Fatalf("Failed to reserve key: %v", err)
+		}
+		conflictMux := NewMux()
+		conflictMux.ErrorHandler(func(ctx Context, err error) {
+			ctx.WriteHeader(http.StatusConflict)
+		})
+		conflictMux.Middleware(IdempotencyMiddleware(store))
+		conflictMux.Post("", "/charges", func(ctx Context) error { return nil })
+
+		req := httptest.NewRequest("POST", "/charges", nil)
+		req.Header.Set(HeaderIdempotencyKey, "key-4")
+		rec := httptest.NewRecorder()
+		conflictMux.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusConflict {
+			t.Errorf("Expected status 409, got %d", rec.Code)
+		}
+	})
+}
+
+func TestInMemoryIdempotencyStore(t *testing.T) {
+	store := NewInMemoryIdempotencyStore(50 * time.Millisecond)
+	defer store.Close()
+
+	if cached, err := store.Reserve("k"); cached != nil || err != nil {
+		t.Fatalf("Expected successful reservation, got %v, %v", cached, err)
+	}
+	if _, err := store.Reserve("k"); !errors.Is(err, ErrIdempotencyInFlight) {
+		t.Errorf("Expected ErrIdempotencyInFlight, got %v", err)
+	}
+
+	if err := store.Release("k"); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if _, err := store.Reserve("k"); err != nil {
+		t.Errorf("Expected key to be reservable after release, got %v", err)
+	}
+
+	response := &IdempotentResponse{Status: http.StatusCreated, Body: []byte("done")}
+	if err := store.Save("k", response); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if cached, err := store.Reserve("k"); err != nil || cached != response {
+		t.Errorf("Expected cached response, got %v, %v", cached, err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if cached, err := store.Reserve("k"); cached != nil || err != nil {
+		t.Errorf("Expected expired entry to be replaced, got %v, %v", cached, err)
+	}
+}
+
+func TestIdempotencyMiddlewareWithConfig(t *testing.T) {
+	store := NewInMemoryIdempotencyStore(time.Hour)
+	defer store.Close()
+
+	var calls int32
+	mux := NewMux()
+	mux.Middleware(func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			ctx.SetHeader("X-Request-Id", ctx.Request().Header.Get("X-Request-Id"))
+			return next(ctx)
+		}
+	})
+	mux.Middleware(IdempotencyMiddlewareWithConfig(store, IdempotencyConfig{
+		Scope: func(ctx Context) string {
+			return ctx.Request().Header.Get("X-User")
+		},
+	}))
+	mux.Post("", "/charges", func(ctx Context) error {
+		n := atomic.AddInt32(&calls, 1)
+		ctx.SetHeader("X-Charge", fmt.Sprint(n))
+		return ctx.String(http.StatusCreated, fmt.Sprintf("charge %d", n))
+	})
+
+	do := func(user, requestID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/charges", nil)
+		req.Header.Set(HeaderIdempotencyKey, "key-1")
+		req.Header.Set("X-User", user)
+		req.Header.Set("X-Request-Id", requestID)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	alice := do("alice", "r1")
+	bob := do("bob", "r2")
+	replay := do("alice", "r3")
+
+	if calls != 2 {
+		t.Errorf("Expected handler to run once per scope, ran %d times", calls)
+	}
+	if alice.Body.String() != "charge 1" || bob.Body.String() != "charge 2" {
+		t.Errorf("Expected separate responses per scope, got %q and %q", alice.Body.String(), bob.Body.String())
+	}
+	if replay.Body.String() != "charge 1" || replay.Header().Get(HeaderIdempotentReplayed) != "true" {
+		t.Errorf("Expected replay of first response, got %q", replay.Body.String())
+	}
+	if got := replay.Header().Get("X-Request-Id"); got != "r3" {
+		t.Errorf("Expected outer middleware header of the retry, got %q", got)
+	}
+	if got := replay.Header().Get("X-Charge"); got != "1" {
+		t.Errorf("Expected stored handler header, got %q", got)
+	}
+}
+
+// failingIdempotencyStore is an IdempotencyStore whose Save always fails.
+type failingIdempotencyStore struct {
+	*InMemoryIdempotencyStore
+}
+
+func (s failingIdempotencyStore) Save(string, *IdempotentResponse) error {
+	return errors.New("store unavailable")
+}
+
+func TestIdempotencyMiddleware_SaveError(t *testing.T) {
+	store := failingIdempotencyStore{NewInMemoryIdempotencyStore(time.Hour)}
+	defer store.Close()
+
+	var calls int32
+	mux := NewMux()
+	mux.Middleware(IdempotencyMiddleware(store))
+	mux.Post("", "/charges", func(ctx Context) error {
+		atomic.AddInt32(&calls, 1)
+		return ctx.String(http.StatusCreated, "charged")
+	})
+
+	do := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/charges", nil)
+		req.Header.Set(HeaderIdempotencyKey, "key-1")
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	var first *httptest.ResponseRecorder
+	logs := captureLogs(t, func() { first = do() })
+
+	if first.Code != http.StatusCreated || first.Body.String() != "charged" {
+		t.Errorf("Expected response to be sent, got %d %q", first.Code, first.Body.String())
+	}
+	if !strings.Contains(logs, "failed to save idempotent response") || !strings.Contains(logs, "store unavailable") {
+		t.Errorf("Expected save error to be logged, got %q", logs)
+	}
+
+	// The reservation is released, so a retry runs the handler again.
+	if second := do(); second.Code != http.StatusCreated || calls != 2 {
+		t.Errorf("Expected retry to execute the handler, got %d after %d calls", second.Code, calls)
+	}
+}
+
+func TestAddedHeaders(t *testing.T) {
+	before := http.Header{"X-Outer": {"a"}, "X-Changed": {"old"}}
+	after := http.Header{"X-Outer": {"a"}, "X-Changed": {"new"}, "X-New": {"b"}}
+
+	added := addedHeaders(before, after)
+	if len(added) != 2 || added.Get("X-Changed") != "new" || added.Get("X-New") != "b" {
+		t.Errorf("Expected only new and changed headers, got %v", added)
+	}
+}
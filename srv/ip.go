@@ -0,0 +1,136 @@
+package srv
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// IPResolver determines the originating client IP address of a request that
+// may have passed through one or more reverse proxies or load balancers.
+//
+// Forwarding headers are trivially spoofable by clients, so they are only
+// honored when the direct peer (RemoteAddr) is a configured trusted proxy.
+// X-Forwarded-For is walked from right to left, skipping trusted proxies, and
+// the first untrusted address is taken as the client IP. The leftmost entry is
+// never trusted blindly because any client can prepend arbitrary values to it.
+// X-Real-IP is used as a fallback when X-Forwarded-For is absent.
+//
+// A nil *IPResolver is valid and trusts no proxies, always returning the host
+// part of RemoteAddr.
+//
+// The same resolver can be shared by several middleware, for example request
+// logging and rate limiting, so that they agree on who the client is.
+type IPResolver struct {
+	trusted []*net.IPNet
+}
+
+// NewIPResolver creates an IPResolver that trusts the given proxies. Each
+// entry may be a single IP address ("10.0.0.1", "::1") or a CIDR range
+// ("10.0.0.0/8"). An error is returned if any entry cannot be parsed.
+//
+// Example:
+//
+//	resolver, err := srv.NewIPResolver("10.0.0.0/8", "127.0.0.1")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	mux.Middleware(srv.LoggingMiddlewareWithConfig(srv.LoggingConfig{IPResolver: resolver}))
+func NewIPResolver(trustedProxies ...string) (*IPResolver, error) {
+	r := &IPResolver{trusted: make([]*net.IPNet, 0, len(trustedProxies))}
+	for _, proxy := range trustedProxies {
+		proxy = strings.TrimSpace(proxy)
+		if strings.Contains(proxy, "/") {
+			_, network, err := net.ParseCIDR(proxy)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy CIDR %q: %w", proxy, err)
+			}
+			r.trusted = append(r.trusted, network)
+			continue
+		}
+
+		ip := net.ParseIP(proxy)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid trusted proxy IP %q", proxy)
+		}
+		bits := 8 * net.IPv6len
+		if ip4 := ip.To4(); ip4 != nil {
+			ip, bits = ip4, 8*net.IPv4len
+		}
+		r.trusted = append(r.trusted, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+	}
+	return r, nil
+}
+
+// ClientIP returns the client IP address for the request. See IPResolver for
+// the resolution rules.
+func (r *IPResolver) ClientIP(req *http.Request) string {
+	remote := remoteHost(req.RemoteAddr)
+	if !r.isTrusted(remote) {
+		return remote
+	}
+
+	if forwarded := forwardedFor(req.Header); len(forwarded) > 0 {
+		client := remote
+		for i := len(forwarded) - 1; i >= 0; i-- {
+			if net.ParseIP(forwarded[i]) == nil {
+				// A malformed hop means the chain cannot be trusted any
+				// further; stop at the last address we could verify.
+				break
+			}
+			client = forwarded[i]
+			if !r.isTrusted(client) {
+				break
+			}
+		}
+		return client
+	}
+
+	if realIP := strings.TrimSpace(req.Header.Get(HeaderXRealIP)); net.ParseIP(realIP) != nil {
+		return realIP
+	}
+
+	return remote
+}
+
+// isTrusted reports whether ip belongs to one of the trusted proxy ranges.
+func (r *IPResolver) isTrusted(ip string) bool {
+	if r == nil {
+		return false
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range r.trusted {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardedFor returns all X-Forwarded-For entries in order, merging repeated
+// header lines as required by RFC 9110.
+func forwardedFor(header http.Header) []string {
+	var hops []string
+	for _, value := range header.Values(HeaderXForwardedFor) {
+		for _, hop := range strings.Split(value, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+	return hops
+}
+
+// remoteHost strips the port from a RemoteAddr value. Addresses without a
+// port are returned unchanged.
+func remoteHost(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
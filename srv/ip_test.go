@@ -0,0 +1,178 @@
+package srv
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewIPResolver(t *testing.T) {
+	tests := []struct {
+		name    string
+		proxies []string
+		wantErr bool
+	}{
+		{"no proxies", nil, false},
+		{"ipv4 address", []string{"10.0.0.1"}, false},
+		{"ipv6 address", []string{"::1"}, false},
+		{"cidr ranges", []string{"10.0.0.0/8", "fd00::/8"}, false},
+		{"invalid address", []string{"not-an-ip"}, true},
+		{"invalid cidr", []string{"10.0.0.0/99"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver, err := NewIPResolver(tt.proxies...)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if resolver == nil {
+				t.Error("Expected resolver, got nil")
+			}
+		})
+	}
+}
+
+func TestIPResolver_ClientIP(t *testing.T) {
+	resolver, err := NewIPResolver("10.0.0.0/8", "192.168.1.1")
+	if err != nil {
+		t.Fatalf("Failed to create resolver: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		resolver      *IPResolver
+		remoteAddr    string
+		xForwardedFor []string
+		xRealIP       string
+		expected      string
+	}{
+		{
+			name:       "direct connection",
+			resolver:   resolver,
+			remoteAddr: "203.0.113.7:4321",
+			expected:   "203.0.113.7",
+		},
+		{
+			name:          "untrusted peer cannot spoof headers",
+			resolver:      resolver,
+			remoteAddr:    "203.0.113.7:4321",
+			xForwardedFor: []string{"1.2.3.4"},
+			xRealIP:       "1.2.3.4",
+			expected:      "203.0.113.7",
+		},
+		{
+			name:          "single trusted proxy",
+			resolver:      resolver,
+			remoteAddr:    "10.1.2.3:80",
+			xForwardedFor: []string{"198.51.100.2"},
+			expected:      "198.51.100.2",
+		},
+		{
+			name:          "rightmost untrusted hop wins over spoofed leftmost",
+			resolver:      resolver,
+			remoteAddr:    "10.1.2.3:80",
+			xForwardedFor: []string{"1.2.3.4, 198.51.100.2, 192.168.1.1"},
+			expected:      "198.51.100.2",
+		},
+		{
+			name:          "multiple header lines are merged",
+			resolver:      resolver,
+			remoteAddr:    "10.1.2.3:80",
+			xForwardedFor: []string{"198.51.100.2", "10.0.0.5"},
+			expected:      "198.51.100.2",
+		},
+		{
+			name:          "all hops trusted",
+			resolver:      resolver,
+			remoteAddr:    "10.1.2.3:80",
+			xForwardedFor: []string{"10.0.0.9, 10.0.0.5"},
+			expected:      "10.0.0.9",
+		},
+		{
+			name:          "malformed hop stops the walk",
+			resolver:      resolver,
+			remoteAddr:    "10.1.2.3:80",
+			xForwardedFor: []string{"198.51.100.2, garbage, 10.0.0.5"},
+			expected:      "10.0.0.5",
+		},
+		{
+			name:       "x-real-ip fallback",
+			resolver:   resolver,
+			remoteAddr: "10.1.2.3:80",
+			xRealIP:    "198.51.100.9",
+			expected:   "198.51.100.9",
+		},
+		{
+			name:       "invalid x-real-ip ignored",
+			resolver:   resolver,
+			remoteAddr: "10.1.2.3:80",
+			xRealIP:    "nope",
+			expected:   "10.1.2.3",
+		},
+		{
+			name:          "nil resolver trusts nobody",
+			resolver:      nil,
+			remoteAddr:    "10.1.2.3:80",
+			xForwardedFor: []string{"198.51.100.2"},
+			expected:      "10.1.2.3",
+		},
+		{
+			name:       "remote addr without port",
+			resolver:   resolver,
+			remoteAddr: "203.0.113.7",
+			expected:   "203.0.113.7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, v := range tt.xForwardedFor {
+				req.Header.Add(HeaderXForwardedFor, v)
+			}
+			if tt.xRealIP != "" {
+				req.Header.Set(HeaderXRealIP, tt.xRealIP)
+			}
+
+			if got := tt.resolver.ClientIP(req); got != tt.expected {
+				t.Errorf("Expected client IP %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestLoggingMiddlewareWithConfig_ClientIP(t *testing.T) {
+	resolver, err := NewIPResolver("10.0.0.0/8")
+	if err != nil {
+		t.Fatalf("Failed to create resolver: %v", err)
+	}
+
+	mux := NewMux()
+	mux.Middleware(LoggingMiddlewareWithConfig(LoggingConfig{IPResolver: resolver}))
+	mux.Get("", "/test", func(ctx Context) error {
+		return ctx.String(200, "ok")
+	})
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.RemoteAddr = "10.0.0.1:12345"
+	req.Header.Set(HeaderXForwardedFor, "198.51.100.2")
+	rec := httptest.NewRecorder()
+
+	logOutput := captureLogs(t, func() {
+		mux.ServeHTTP(rec, req)
+	})
+
+	for _, part := range []string{"client-ip=198.51.100.2", "remote-addr=10.0.0.1:12345"} {
+		if !strings.Contains(logOutput, part) {
+			t.Errorf("Expected log output to contain '%s', but it didn't. Log output: %s", part, logOutput)
+		}
+	}
+}
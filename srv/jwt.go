@@ -0,0 +1,316 @@
+package srv
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	_ "crypto/sha256" // registers crypto.SHA256
+	_ "crypto/sha512" // registers crypto.SHA384 and crypto.SHA512
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/c3p0-box/utils/erm"
+)
+
+// =============================================================================
+// JWT Authentication Middleware
+// =============================================================================
+//
+// The JWT support is implemented with the standard library only, so that the
+// srv package does not pull in a third-party JWT dependency. It covers
+// verification of compact JWS tokens signed with HMAC (HS*), RSA PKCS#1 v1.5
+// (RS*) and ECDSA (ES*); token issuance is out of scope.
+
+// JWTClaims holds the decoded claims of a verified token. Numeric claims are
+// decoded as json.Number to preserve precision.
+type JWTClaims map[string]interface{}
+
+// JWTKeyFunc returns the key used to verify a token signed with the given
+// algorithm and key ID ("kid" header, empty if absent). The returned key must
+// be a []byte for HS256/HS384/HS512, an *rsa.PublicKey for RS256/RS384/RS512
+// and an *ecdsa.PublicKey for ES256/ES384/ES512. Returning a key of the wrong
+// type for the algorithm causes verification to fail.
+type JWTKeyFunc func(alg, kid string) (interface{}, error)
+
+// JWTConfig defines the configuration for JWT middleware.
+type JWTConfig struct {
+	// Skipper bypasses the middleware for requests for which it returns
+	// true, e.g. to exempt some paths.
+	//
+	// Optional. Default value nil (never skip).
+	Skipper Skipper
+
+	// KeyFunc provides the verification key for a token. It allows key
+	// rotation (by "kid") and mixing HMAC and public-key algorithms.
+	//
+	// Required.
+	KeyFunc JWTKeyFunc
+
+	// Algorithms lists the accepted signing algorithms. Tokens signed with any
+	// other algorithm (including "none") are rejected.
+	//
+	// Optional. Default value []string{"HS256", "HS384", "HS512", "RS256",
+	// "RS384", "RS512", "ES256", "ES384", "ES512"}.
+	Algorithms []string
+
+	// Leeway is the clock skew tolerated when checking the "exp" and "nbf"
+	// claims.
+	//
+	// Optional. Default value 0.
+	Leeway time.Duration
+
+	// ContextKey is the key under which the verified JWTClaims are stored
+	// with ctx.Set.
+	//
+	// Optional. Default value "claims".
+	ContextKey string
+}
+
+// DefaultJWTConfig is the default JWT middleware config. KeyFunc must still
+// be provided.
+var DefaultJWTConfig = JWTConfig{
+	Skipper: nil,
+	Algorithms: []string{
+		"HS256", "HS384", "HS512",
+		"RS256", "RS384", "RS512",
+		"ES256", "ES384", "ES512",
+	},
+	Leeway:     0,
+	ContextKey: "claims",
+}
+
+var (
+	// ErrJWTMissing is returned when the request carries no bearer token.
+	ErrJWTMissing = errors.New("missing bearer token")
+	// ErrJWTMalformed is returned when the token cannot be decoded.
+	ErrJWTMalformed = errors.New("malformed token")
+	// ErrJWTAlgorithm is returned when the token uses an algorithm that is not allowed.
+	ErrJWTAlgorithm = errors.New("unsupported signing algorithm")
+	// ErrJWTSignature is returned when the token signature is invalid.
+	ErrJWTSignature = errors.New("invalid token signature")
+	// ErrJWTExpired is returned when the token's "exp" claim is in the past.
+	ErrJWTExpired = errors.New("token is expired")
+	// ErrJWTNotValidYet is returned when the token's "nbf" claim is in the future.
+	ErrJWTNotValidYet = errors.New("token is not valid yet")
+)
+
+// JWTMiddleware returns a HandlerFunc-based middleware that authenticates
+// requests with a JSON Web Token passed as "Authorization: Bearer <token>".
+//
+// The middleware verifies the token signature with the key returned by
+// config.KeyFunc, checks the "exp" and "nbf" claims, and stores the claims on
+// the Context under config.ContextKey. Any failure is returned as a 401
+// Unauthorized erm.Error wrapping one of the ErrJWT* sentinel errors, which
+// is then rendered by the Mux error handler.
+//
+// JWTMiddleware panics if config.KeyFunc is nil.
+//
+// Example:
+//
+//	secret := []byte(os.Getenv("JWT_SECRET"))
+//	mux.Middleware(srv.JWTMiddleware(srv.JWTConfig{
+//		KeyFunc: func(alg, kid string) (interface{}, error) {
+//			return secret, nil
+//		},
+//		Algorithms: []string{"HS256"},
+//	}))
+//
+//	mux.Get("me", "/me", func(ctx srv.Context) error {
+//		claims := ctx.Get("claims").(srv.JWTClaims)
+//		return ctx.JSON(200, map[string]interface{}{"sub": claims["sub"]})
+//	})
+func JWTMiddleware(config JWTConfig) HandlerFuncMiddleware {
+	if config.KeyFunc == nil {
+		panic("srv: JWTMiddleware requires a KeyFunc")
+	}
+	if len(config.Algorithms) == 0 {
+		config.Algorithms = DefaultJWTConfig.Algorithms
+	}
+	if config.ContextKey == "" {
+		config.ContextKey = DefaultJWTConfig.ContextKey
+	}
+
+	return skippable(config.Skipper, func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			token, ok := bearerToken(ctx.GetHeader(HeaderAuthorization))
+			if !ok {
+				return erm.Unauthorized("unauthorized", ErrJWTMissing)
+			}
+
+			claims, err := parseJWT(token, config, time.Now())
+			if err != nil {
+				return erm.Unauthorized("unauthorized", err)
+			}
+
+			ctx.Set(config.ContextKey, claims)
+			return next(ctx)
+		}
+	})
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer" header value.
+// The scheme is matched case-insensitively.
+func bearerToken(authorization string) (string, bool) {
+	const prefix = "bearer "
+	if len(authorization) <= len(prefix) || !strings.EqualFold(authorization[:len(prefix)], prefix) {
+		return "", false
+	}
+	token := strings.TrimSpace(authorization[len(prefix):])
+	return token, token != ""
+}
+
+// parseJWT decodes and verifies a compact JWS token and validates its
+// time-based claims against now.
+func parseJWT(token string, config JWTConfig, now time.Time) (JWTClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrJWTMalformed
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeJWTSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+	if !slices.Contains(config.Algorithms, header.Alg) {
+		return nil, ErrJWTAlgorithm
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrJWTMalformed
+	}
+
+	key, err := config.KeyFunc(header.Alg, header.Kid)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrJWTSignature, err)
+	}
+	if err := verifyJWTSignature(header.Alg, parts[0]+"."+parts[1], signature, key); err != nil {
+		return nil, err
+	}
+
+	var claims JWTClaims
+	if err := decodeJWTSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+
+	if exp, ok, err := numericDateClaim(claims, "exp"); err != nil {
+		return nil, err
+	} else if ok && !now.Before(exp.Add(config.Leeway)) {
+		return nil, ErrJWTExpired
+	}
+	if nbf, ok, err := numericDateClaim(claims, "nbf"); err != nil {
+		return nil, err
+	} else if ok && now.Add(config.Leeway).Before(nbf) {
+		return nil, ErrJWTNotValidYet
+	}
+
+	return claims, nil
+}
+
+// decodeJWTSegment base64url-decodes a token segment and unmarshals the JSON
+// into v, using json.Number for numbers.
+func decodeJWTSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return ErrJWTMalformed
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return ErrJWTMalformed
+	}
+	return nil
+}
+
+// numericDateClaim reads a NumericDate claim (seconds since the epoch). The
+// second return value reports whether the claim is present.
+func numericDateClaim(claims JWTClaims, name string) (time.Time, bool, error) {
+	raw, ok := claims[name]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	number, ok := raw.(json.Number)
+	if !ok {
+		return time.Time{}, false, ErrJWTMalformed
+	}
+	seconds, err := number.Float64()
+	if err != nil {
+		return time.Time{}, false, ErrJWTMalformed
+	}
+	return time.Unix(0, int64(seconds*float64(time.Second))), true, nil
+}
+
+// verifyJWTSignature checks signature over signingInput for the given
+// algorithm. The key type must match the algorithm family, which prevents
+// algorithm confusion attacks (e.g. an RSA public key used as an HMAC secret).
+func verifyJWTSignature(alg, signingInput string, signature []byte, key interface{}) error {
+	hash, err := jwtHash(alg)
+	if err != nil {
+		return err
+	}
+	hasher := hash.New()
+	hasher.Write([]byte(signingInput))
+	digest := hasher.Sum(nil)
+
+	switch alg[:2] {
+	case "HS":
+		secret, ok := key.([]byte)
+		if !ok || len(secret) == 0 {
+			return ErrJWTSignature
+		}
+		mac := hmac.New(hash.New, secret)
+		mac.Write([]byte(signingInput))
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return ErrJWTSignature
+		}
+	case "RS":
+		publicKey, ok := key.(*rsa.PublicKey)
+		if !ok || rsa.VerifyPKCS1v15(publicKey, hash, digest, signature) != nil {
+			return ErrJWTSignature
+		}
+	case "ES":
+		publicKey, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return ErrJWTSignature
+		}
+		size := (publicKey.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return ErrJWTSignature
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(publicKey, digest, r, s) {
+			return ErrJWTSignature
+		}
+	default:
+		return ErrJWTAlgorithm
+	}
+	return nil
+}
+
+// jwtHash maps a JWS algorithm name to its hash function.
+func jwtHash(alg string) (crypto.Hash, error) {
+	if len(alg) != 5 {
+		return 0, ErrJWTAlgorithm
+	}
+	switch alg[2:] {
+	case "256":
+		return crypto.SHA256, nil
+	case "384":
+		return crypto.SHA384, nil
+	case "512":
+		return crypto.SHA512, nil
+	}
+	return 0, ErrJWTAlgorithm
+}
@@ -0,0 +1,172 @@
+package srv
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/c3p0-box/utils/erm"
+)
+
+// signTestJWT builds a compact JWS token for the given algorithm and key.
+func signTestJWT(t *testing.T, alg string, key interface{}, claims map[string]interface{}) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	digest := sha256.Sum256([]byte(input))
+	var signature []byte
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write([]byte(input))
+		signature = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		sig, err := rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatalf("Failed to sign token: %v", err)
+		}
+		signature = sig
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		if err != nil {
+			t.Fatalf("Failed to sign token: %v", err)
+		}
+		signature = make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+	default:
+		signature = []byte("invalid")
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestJWTMiddleware(t *testing.T) {
+	secret := []byte("test-secret")
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate ECDSA key: %v", err)
+	}
+
+	keyFunc := func(alg, kid string) (interface{}, error) {
+		switch alg {
+		case "HS256":
+			return secret, nil
+		case "RS256":
+			return &rsaKey.PublicKey, nil
+		case "ES256":
+			return &ecKey.PublicKey, nil
+		}
+		return nil, errors.New("unknown algorithm")
+	}
+
+	var capturedError error
+	mux := NewMux()
+	mux.ErrorHandler(func(ctx Context, err error) {
+		capturedError = err
+		_ = ctx.String(erm.Status(err), err.Error())
+	})
+	mux.Middleware(JWTMiddleware(JWTConfig{KeyFunc: keyFunc, Leeway: time.Second}))
+	mux.Get("", "/me", func(ctx Context) error {
+		claims := ctx.Get("claims").(JWTClaims)
+		return ctx.String(http.StatusOK, claims["sub"].(string))
+	})
+
+	now := time.Now().Unix()
+	valid := map[string]interface{}{"sub": "user-1", "exp": now + 60}
+
+	tests := []struct {
+		name          string
+		authorization string
+		expectedErr   error
+	}{
+		{"valid HS256", "Bearer " + signTestJWT(t, "HS256", secret, valid), nil},
+		{"valid RS256", "Bearer " + signTestJWT(t, "RS256", rsaKey, valid), nil},
+		{"valid ES256", "Bearer " + signTestJWT(t, "ES256", ecKey, valid), nil},
+		{"lowercase scheme", "bearer " + signTestJWT(t, "HS256", secret, valid), nil},
+		{"missing header", "", ErrJWTMissing},
+		{"wrong scheme", "Basic dXNlcjpwYXNz", ErrJWTMissing},
+		{"malformed token", "Bearer not.a-token", ErrJWTMalformed},
+		{"wrong secret", "Bearer " + signTestJWT(t, "HS256", []byte("other"), valid), ErrJWTSignature},
+		{"alg none", "Bearer " + signTestJWT(t, "none", nil, valid), ErrJWTAlgorithm},
+		{"expired", "Bearer " + signTestJWT(t, "HS256", secret, map[string]interface{}{"sub": "user-1", "exp": now - 60}), ErrJWTExpired},
+		{"not valid yet", "Bearer " + signTestJWT(t, "HS256", secret, map[string]interface{}{"sub": "user-1", "nbf": now + 60}), ErrJWTNotValidYet},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capturedError = nil
+			req := httptest.NewRequest("GET", "/me", nil)
+			if tt.authorization != "" {
+				req.Header.Set(HeaderAuthorization, tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if tt.expectedErr == nil {
+				if rec.Code != http.StatusOK || rec.Body.String() != "user-1" {
+					t.Errorf("Expected 200 'user-1', got %d %q", rec.Code, rec.Body.String())
+				}
+				return
+			}
+
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("Expected status 401, got %d", rec.Code)
+			}
+			if capturedError == nil {
+				t.Fatal("Expected error to be captured")
+			}
+			if e, ok := capturedError.(erm.Error); !ok || !errors.Is(e.Unwrap(), tt.expectedErr) {
+				t.Errorf("Expected error wrapping %v, got %v", tt.expectedErr, capturedError)
+			}
+		})
+	}
+
+	t.Run("rejects algorithm not in allow list", func(t *testing.T) {
+		strict := NewMux()
+		var err error
+		strict.ErrorHandler(func(ctx Context, e error) {
+			err = e
+			ctx.WriteHeader(erm.Status(e))
+		})
+		strict.Middleware(JWTMiddleware(JWTConfig{KeyFunc: keyFunc, Algorithms: []string{"RS256"}}))
+		strict.Get("", "/me", func(ctx Context) error { return nil })
+
+		req := httptest.NewRequest("GET", "/me", nil)
+		req.Header.Set(HeaderAuthorization, "Bearer "+signTestJWT(t, "HS256", secret, valid))
+		rec := httptest.NewRecorder()
+		strict.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", rec.Code)
+		}
+		if e, ok := err.(erm.Error); !ok || e.Unwrap() != ErrJWTAlgorithm {
+			t.Errorf("Expected ErrJWTAlgorithm, got %v", err)
+		}
+	})
+
+	t.Run("panics without key func", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected panic without KeyFunc")
+			}
+		}()
+		JWTMiddleware(JWTConfig{})
+	})
+}
@@ -2,20 +2,31 @@ package srv
 
 import (
 	"bytes"
+	"compress/gzip"
+	"container/list"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"mime"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/c3p0-box/utils/erm"
+	"golang.org/x/text/language"
 )
 
 // Register common types for gob encoding/decoding in cookie store
@@ -35,18 +46,66 @@ func init() {
 // HandlerFunc-based Middleware for Context-Aware Operations
 // =============================================================================
 
+// Skipper reports whether a middleware should be bypassed for a request.
+// The config of each built-in middleware has a Skipper field.
+//
+// Example:
+//
+//	config := srv.DefaultTrailingSlashConfig
+//	config.Skipper = func(ctx srv.Context) bool {
+//		return strings.HasPrefix(ctx.Path(), "/api/")
+//	}
+//	mux.Middleware(srv.AddTrailingSlashMiddleware(config))
+type Skipper func(ctx Context) bool
+
+// skippable bypasses middleware for requests for which skipper returns true.
+func skippable(skipper Skipper, middleware HandlerFuncMiddleware) HandlerFuncMiddleware {
+	if skipper == nil {
+		return middleware
+	}
+	return When(func(ctx Context) bool { return !skipper(ctx) }, middleware)
+}
+
+// LoggingConfig defines the configuration for LoggingMiddlewareWithConfig.
+type LoggingConfig struct {
+	// Skipper bypasses the middleware for requests for which it returns
+	// true, e.g. to exempt some paths.
+	//
+	// Optional. Default value nil (never skip).
+	Skipper Skipper
+
+	// IPResolver resolves the client IP logged as "client-ip". Configure it
+	// with the addresses of your load balancers or reverse proxies so that
+	// X-Forwarded-For and X-Real-IP are honored when they send them.
+	//
+	// Optional. Default value nil (no trusted proxies; the host part of
+	// RemoteAddr is logged).
+	IPResolver *IPResolver
+}
+
+// DefaultLoggingConfig is the default Logging middleware config.
+var DefaultLoggingConfig = LoggingConfig{
+	Skipper:    nil,
+	IPResolver: nil,
+}
+
 // LoggingMiddleware is a HandlerFunc-based middleware that logs HTTP requests
 // with structured logging using slog. It works directly with the Context interface
 // and maintains the elegant error handling pattern.
 //
-// The logged information includes:
+// The request is logged through ctx.Logger(), so fields added by other
+// middleware (such as "request-id") are included. The logged information
+// includes:
 //   - name: "srv.Logging" (logger identifier)
-//   - method: HTTP method (GET, POST, etc.)
-//   - path: Request URL path
+//   - method, path and route: from ctx.Logger()
 //   - user-agent: Client user agent string
 //   - remote-addr: Client remote address
+//   - client-ip: Client IP resolved through the configured IPResolver
 //   - duration: Request processing time
 //
+// LoggingMiddleware uses DefaultLoggingConfig, which trusts no proxies. Use
+// LoggingMiddlewareWithConfig when running behind a load balancer.
+//
 // Note: This middleware cannot capture the exact status code since it works at the
 // HandlerFunc level, but it provides comprehensive logging of request information.
 //
@@ -54,26 +113,40 @@ func init() {
 //
 //	mux.Middleware(srv.LoggingMiddleware)
 func LoggingMiddleware(next HandlerFunc) HandlerFunc {
-	return func(ctx Context) error {
-		start := time.Now()
+	return LoggingMiddlewareWithConfig(DefaultLoggingConfig)(next)
+}
 
-		// Execute the next handler
-		err := next(ctx)
+// LoggingMiddlewareWithConfig returns a LoggingMiddleware with the given config.
+//
+// Example:
+//
+//	resolver, err := srv.NewIPResolver("10.0.0.0/8")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	mux.Middleware(srv.LoggingMiddlewareWithConfig(srv.LoggingConfig{IPResolver: resolver}))
+func LoggingMiddlewareWithConfig(config LoggingConfig) HandlerFuncMiddleware {
+	return skippable(config.Skipper, func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			start := time.Now()
 
-		// Log the request
-		duration := time.Since(start)
-		req := ctx.Request()
-		slog.With(
-			slog.String("name", "srv.Logging"),
-			slog.String("method", req.Method),
-			slog.String("path", req.URL.Path),
-			slog.String("user-agent", req.UserAgent()),
-			slog.String("remote-addr", req.RemoteAddr),
-			slog.Duration("duration", duration),
-		).Info("request completed")
+			// Execute the next handler
+			err := next(ctx)
 
-		return err
-	}
+			// Log the request
+			duration := time.Since(start)
+			req := ctx.Request()
+			ctx.Logger().With(
+				slog.String("name", "srv.Logging"),
+				slog.String("user-agent", req.UserAgent()),
+				slog.String("remote-addr", req.RemoteAddr),
+				slog.String("client-ip", config.IPResolver.ClientIP(req)),
+				slog.Duration("duration", duration),
+			).Info("request completed")
+
+			return err
+		}
+	})
 }
 
 // RecoverMiddleware is a HandlerFunc-based middleware that recovers from panics
@@ -81,13 +154,23 @@ func LoggingMiddleware(next HandlerFunc) HandlerFunc {
 // and maintains the elegant error handling pattern.
 //
 // When a panic occurs, it logs the error using structured logging and converts
-// the panic to an error that can be handled by the error handler.
+// the panic to an error that can be handled by the error handler. The stack
+// trace is captured at recovery time, so it includes the frame that panicked.
+//
+// The returned error is an erm 500 Internal Server Error with the message
+// "panic recovered" (safe to show to clients, see erm.Message). It wraps the
+// panic value, or the value itself if it is an error, and its Stack method
+// exposes the captured stack trace.
+//
+// Panics with http.ErrAbortHandler, which net/http and httputil.ReverseProxy
+// use to abort a response on purpose, are re-raised unchanged and not logged.
 //
 // The panic is logged with:
 //   - name: "srv.Recover" (logger identifier)
 //   - error: The recovered panic value
 //   - path: Request URL path
 //   - method: HTTP method
+//   - stack: The formatted stack trace (see erm.FormatStack)
 //
 // Example:
 //
@@ -96,19 +179,30 @@ func RecoverMiddleware(next HandlerFunc) HandlerFunc {
 	return func(ctx Context) (err error) {
 		defer func() {
 			if r := recover(); r != nil {
+				// http.ErrAbortHandler deliberately aborts the response
+				// (e.g. httputil.ReverseProxy on a failed copy); net/http
+				// handles it without logging, so let it through.
+				if r == http.ErrAbortHandler {
+					panic(r)
+				}
+				// Convert panic to error
+				panicErr, ok := r.(error)
+				if !ok {
+					panicErr = fmt.Errorf("panic: %v", r)
+				}
+				// erm captures the stack for 500 errors; since we are still
+				// on the panicking goroutine, it includes the panic site.
+				stackErr := erm.Internal("panic recovered", panicErr)
+
 				slog.With(
 					slog.String("name", "srv.Recover"),
 					slog.Any("error", r),
 					slog.String("path", ctx.Request().URL.Path),
 					slog.String("method", ctx.Request().Method),
+					slog.String("stack", erm.FormatStack(stackErr)),
 				).Error("recovered from panic")
 
-				// Convert panic to error
-				if e, ok := r.(error); ok {
-					err = e
-				} else {
-					err = fmt.Errorf("panic: %v", r)
-				}
+				err = stackErr
 			}
 		}()
 
@@ -116,6 +210,54 @@ func RecoverMiddleware(next HandlerFunc) HandlerFunc {
 	}
 }
 
+// maxRequestIDLength bounds request IDs accepted from clients.
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware is a HandlerFunc-based middleware that assigns every
+// request an ID for correlating logs. It reuses the X-Request-Id header sent
+// by a client or proxy when it is at most 128 printable ASCII characters and
+// generates a random ID otherwise. The ID is echoed in the X-Request-Id
+// response header, stored in the context under "request-id" and added as
+// the "request-id" field of ctx.Logger().
+//
+// Register it before LoggingMiddleware so that the request log includes the
+// ID.
+//
+// Example:
+//
+//	mux.Middleware(srv.RequestIDMiddleware)
+//	mux.Middleware(srv.LoggingMiddleware)
+func RequestIDMiddleware(next HandlerFunc) HandlerFunc {
+	return func(ctx Context) error {
+		id := ctx.GetHeader(HeaderXRequestID)
+		if !isValidRequestID(id) {
+			b := make([]byte, 16)
+			if _, err := rand.Read(b); err != nil {
+				return erm.Internal("failed to generate request ID", err)
+			}
+			id = hex.EncodeToString(b)
+		}
+
+		ctx.Set("request-id", id)
+		ctx.SetHeader(HeaderXRequestID, id)
+		ctx.SetLogger(ctx.Logger().With(slog.String("request-id", id)))
+		return next(ctx)
+	}
+}
+
+// isValidRequestID reports whether id is safe to reuse in headers and logs.
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
 // CORSMiddleware returns a HandlerFunc-based CORS middleware that handles Cross-Origin Resource
 // Sharing (CORS) according to the W3C specification. It supports both simple and preflight
 // requests with comprehensive configuration options for security and compatibility.
@@ -175,7 +317,7 @@ func CORSMiddleware(config CORSConfig) HandlerFuncMiddleware {
 		maxAge = strconv.Itoa(config.MaxAge)
 	}
 
-	return func(next HandlerFunc) HandlerFunc {
+	return skippable(config.Skipper, func(next HandlerFunc) HandlerFunc {
 		return func(ctx Context) error {
 			req := ctx.Request()
 			origin := req.Header.Get("Origin")
@@ -248,7 +390,7 @@ func CORSMiddleware(config CORSConfig) HandlerFuncMiddleware {
 			ctx.WriteHeader(http.StatusNoContent)
 			return nil
 		}
-	}
+	})
 }
 
 // =============================================================================
@@ -257,6 +399,12 @@ func CORSMiddleware(config CORSConfig) HandlerFuncMiddleware {
 
 // CORSConfig defines the configuration for CORS middleware.
 type CORSConfig struct {
+	// Skipper bypasses the middleware for requests for which it returns
+	// true, e.g. to exempt some paths.
+	//
+	// Optional. Default value nil (never skip).
+	Skipper Skipper
+
 	// AllowOrigins determines the value of the Access-Control-Allow-Origin
 	// response header. This header defines a list of origins that may access the
 	// resource. The wildcard characters '*' and '?' are supported and are
@@ -306,6 +454,7 @@ type CORSConfig struct {
 
 // DefaultCORSConfig is the default CORS middleware config.
 var DefaultCORSConfig = CORSConfig{
+	Skipper:      nil,
 	AllowOrigins: []string{"*"},
 	AllowMethods: []string{
 		http.MethodGet,
@@ -328,6 +477,12 @@ var DefaultCORSConfig = CORSConfig{
 
 // TrailingSlashConfig defines the configuration for AddTrailingSlash middleware.
 type TrailingSlashConfig struct {
+	// Skipper bypasses the middleware for requests for which it returns
+	// true, e.g. to exempt some paths.
+	//
+	// Optional. Default value nil (never skip).
+	Skipper Skipper
+
 	// RedirectCode is the HTTP status code used when redirecting the request.
 	// If set to 0, the request is forwarded internally without a redirect.
 	// If set to a redirect code (e.g., 301, 302), an HTTP redirect is performed.
@@ -338,6 +493,7 @@ type TrailingSlashConfig struct {
 
 // DefaultTrailingSlashConfig is the default AddTrailingSlash middleware config.
 var DefaultTrailingSlashConfig = TrailingSlashConfig{
+	Skipper:      nil,
 	RedirectCode: 0, // Forward internally by default
 }
 
@@ -360,7 +516,7 @@ var DefaultTrailingSlashConfig = TrailingSlashConfig{
 //	config := srv.TrailingSlashConfig{RedirectCode: 301}
 //	mux.Middleware(srv.AddTrailingSlashMiddleware(config))
 func AddTrailingSlashMiddleware(config TrailingSlashConfig) HandlerFuncMiddleware {
-	return func(next HandlerFunc) HandlerFunc {
+	return skippable(config.Skipper, func(next HandlerFunc) HandlerFunc {
 		return func(ctx Context) error {
 			req := ctx.Request()
 			path := req.URL.Path
@@ -394,7 +550,583 @@ func AddTrailingSlashMiddleware(config TrailingSlashConfig) HandlerFuncMiddlewar
 
 			return next(ctx)
 		}
+	})
+}
+
+// =============================================================================
+// Real IP Middleware
+// =============================================================================
+
+// RealIPMiddleware returns a HandlerFunc-based middleware that rewrites
+// req.RemoteAddr to the client IP when the request arrives through one of the
+// trusted proxies. Downstream middleware and handlers then see the real client
+// address without re-parsing forwarding headers.
+//
+// The client IP is resolved with an IPResolver: X-Forwarded-For is walked from
+// right to left and the first hop that is not a trusted proxy is used, falling
+// back to X-Real-IP. Note that it is the rightmost untrusted hop, not the
+// leftmost one: clients control everything to the left of the entry added by
+// the first trusted proxy, so taking the leftmost value would let anyone spoof
+// their address. Requests from untrusted peers are left unchanged.
+//
+// The rewritten RemoteAddr holds the bare IP address without a port, since the
+// peer's port belongs to the proxy connection.
+//
+// RealIPMiddleware panics if any trusted proxy entry is not a valid IP address
+// or CIDR range, as this is a configuration error detected at startup.
+//
+// Example:
+//
+//	mux.Middleware(srv.RealIPMiddleware([]string{"10.0.0.0/8"}))
+//	mux.Middleware(srv.LoggingMiddleware) // logs the real client in remote-addr
+func RealIPMiddleware(trustedProxies []string) HandlerFuncMiddleware {
+	resolver, err := NewIPResolver(trustedProxies...)
+	if err != nil {
+		panic(fmt.Sprintf("srv: RealIPMiddleware: %v", err))
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			req := ctx.Request()
+			if resolver.isTrusted(remoteHost(req.RemoteAddr)) {
+				req.RemoteAddr = resolver.ClientIP(req)
+			}
+			return next(ctx)
+		}
+	}
+}
+
+// =============================================================================
+// ETag Middleware
+// =============================================================================
+
+// ETagMiddleware is a HandlerFunc-based middleware that adds an ETag header to
+// successful GET responses and answers conditional requests with
+// 304 Not Modified when the client's cached copy is still current.
+//
+// The handler's response is buffered, and the ETag is a strong validator
+// derived from a SHA-256 hash of the body. If the handler already set an ETag
+// header, that value is kept and used for the comparison instead. When the
+// request's If-None-Match header matches the ETag (including "*" and lists of
+// tags, compared weakly as required by RFC 9110), the body is dropped and a 304
+// is sent with the ETag header.
+//
+// The middleware does not touch:
+//   - requests other than GET
+//   - responses with a status other than 200 OK or with an empty body
+//   - streaming responses, i.e. handlers that flush the response writer
+//   - responses of handlers that return an error
+//
+// Example:
+//
+//	mux.Middleware(srv.ETagMiddleware)
+func ETagMiddleware(next HandlerFunc) HandlerFunc {
+	return func(ctx Context) error {
+		if ctx.Request().Method != http.MethodGet {
+			return next(ctx)
+		}
+
+		original := ctx.Response()
+		buf := newBufferedResponseWriter(original)
+		ctx.SetResponse(buf)
+		defer ctx.SetResponse(original)
+
+		err := next(ctx)
+
+		if buf.Streaming() {
+			return err
+		}
+		if err != nil || buf.Status() != http.StatusOK || len(buf.Body()) == 0 {
+			if flushErr := buf.flushBuffer(); err == nil {
+				err = flushErr
+			}
+			return err
+		}
+
+		header := original.Header()
+		etag := header.Get(HeaderETag)
+		if etag == "" {
+			sum := sha256.Sum256(buf.Body())
+			etag = `"` + hex.EncodeToString(sum[:16]) + `"`
+			header.Set(HeaderETag, etag)
+		}
+
+		if etagMatches(ctx.Request().Header.Get(HeaderIfNoneMatch), etag) {
+			header.Del(HeaderContentLength)
+			original.WriteHeader(http.StatusNotModified)
+			return nil
+		}
+
+		return buf.flushBuffer()
+	}
+}
+
+// etagMatches reports whether an If-None-Match header value matches etag
+// using the weak comparison function: the W/ prefix is ignored on both sides.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// =============================================================================
+// Response Time Middleware
+// =============================================================================
+
+// ResponseTimeMiddleware is a HandlerFunc-based middleware that reports how
+// long the handler took in an X-Response-Time header, formatted in
+// milliseconds (e.g. "12.345ms").
+//
+// Headers cannot be changed once the status code has been written, so the
+// duration is measured when the handler commits the response (its first
+// WriteHeader, Write or Flush) rather than when it returns; nothing is
+// buffered and streaming responses are unaffected. If the handler returns
+// without writing anything, e.g. because it returned an error, the header is
+// set when it returns so that it is included in the error response.
+//
+// Register it before other middleware to include their time as well.
+//
+// Example:
+//
+//	mux.Middleware(srv.ResponseTimeMiddleware)
+func ResponseTimeMiddleware(next HandlerFunc) HandlerFunc {
+	return func(ctx Context) error {
+		start := time.Now()
+		original := ctx.Response()
+		setHeader := func() {
+			original.Header().Set(HeaderXResponseTime, formatResponseTime(time.Since(start)))
+		}
+
+		hooked := newHookResponseWriter(original, setHeader)
+		ctx.SetResponse(hooked)
+		defer ctx.SetResponse(original)
+
+		err := next(ctx)
+		if !hooked.Fired() {
+			setHeader()
+		}
+		return err
+	}
+}
+
+// formatResponseTime formats d as milliseconds with microsecond precision.
+func formatResponseTime(d time.Duration) string {
+	return strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', 3, 64) + "ms"
+}
+
+// =============================================================================
+// Locale Middleware
+// =============================================================================
+
+// LocaleMiddleware returns a HandlerFunc-based middleware that negotiates the
+// response language from the Accept-Language header and stores it on the
+// Context under "locale" as a language.Tag.
+//
+// The tag is the best match among the languages supported by matcher, or its
+// first (default) language if the header is missing, invalid or matches
+// nothing. Match extensions (such as "-u-rg-") are stripped so the tag can be
+// passed directly to erm and vix, which look up translations by exact tag.
+// "Accept-Language" is added to the Vary response header.
+//
+// Example:
+//
+//	matcher := language.NewMatcher([]language.Tag{language.English, language.French})
+//	mux.Middleware(srv.LocaleMiddleware(matcher))
+//
+//	mux.Post("", "/signup", func(ctx srv.Context) error {
+//		locale, _ := srv.ContextValue[language.Tag](ctx, "locale")
+//		err := vix.String(ctx.FormValue("email"), "email").Locale(locale).Required().Email().Validate()
+//		if err != nil {
+//			return srv.WriteValidationError(ctx, err)
+//		}
+//		// ...
+//	})
+func LocaleMiddleware(matcher language.Matcher) HandlerFuncMiddleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			// An invalid header yields no tags, selecting the default language
+			tags, _, _ := language.ParseAcceptLanguage(ctx.GetHeader(HeaderAcceptLanguage))
+			tag, _, _ := matcher.Match(tags...)
+
+			ctx.AddHeader(HeaderVary, HeaderAcceptLanguage)
+			ctx.Set("locale", stripMatchExtensions(tag))
+			return next(ctx)
+		}
+	}
+}
+
+// stripMatchExtensions returns tag without the extensions a language.Matcher
+// adds to the supported tag it returns, e.g. "en-u-rg-gbzzzz" becomes "en".
+func stripMatchExtensions(tag language.Tag) language.Tag {
+	base, script, region := tag.Raw()
+	stripped, err := language.Compose(base, script, region)
+	if err != nil {
+		return tag
+	}
+	return stripped
+}
+
+// =============================================================================
+// API Key Middleware
+// =============================================================================
+
+// ErrAPIKeyMissing is returned by APIKeyMiddleware when the request carries no API key.
+var ErrAPIKeyMissing = errors.New("missing API key")
+
+// APIKeyMiddleware returns a HandlerFunc-based middleware that authenticates
+// requests with a static API key, typically for service-to-service calls.
+//
+// The key is read from the request header named by lookup (e.g. "X-API-Key").
+// To read it from a query parameter instead, prefix the name with "query:"
+// (e.g. "query:api_key"). Query parameters end up in access logs and browser
+// history, so prefer headers where possible.
+//
+// The key is passed to validator, which returns the authenticated principal
+// (a service name, account struct, ...) or an error. On success the principal
+// is stored on the Context under "principal". A missing key or a validator
+// error is returned as a 401 Unauthorized erm.Error and handled by the Mux
+// error handler.
+//
+// Validators should compare keys in constant time, for example with
+// crypto/subtle.ConstantTimeCompare.
+//
+// Example:
+//
+//	mux.Middleware(srv.APIKeyMiddleware("X-API-Key", func(key string) (interface{}, error) {
+//		service, ok := lookupService(key)
+//		if !ok {
+//			return nil, errors.New("unknown API key")
+//		}
+//		return service, nil
+//	}))
+//
+//	mux.Get("", "/internal/stats", func(ctx srv.Context) error {
+//		service := ctx.Get("principal").(*Service)
+//		return ctx.JSON(200, statsFor(service))
+//	})
+func APIKeyMiddleware(lookup string, validator func(key string) (principal interface{}, err error)) HandlerFuncMiddleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			var key string
+			if param, ok := strings.CutPrefix(lookup, "query:"); ok {
+				key = ctx.QueryParam(param)
+			} else {
+				key = ctx.GetHeader(lookup)
+			}
+
+			if key == "" {
+				return erm.Unauthorized("unauthorized", ErrAPIKeyMissing)
+			}
+
+			principal, err := validator(key)
+			if err != nil {
+				return erm.Unauthorized("unauthorized", err)
+			}
+
+			ctx.Set("principal", principal)
+			return next(ctx)
+		}
+	}
+}
+
+// =============================================================================
+// Content Negotiation Middleware
+// =============================================================================
+
+// ErrUnsupportedMediaType is wrapped by the error RequireContentTypeMiddleware
+// returns for a request body of a type that is not accepted.
+var ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+// ErrNotAcceptable is wrapped by the error RequireAcceptMiddleware returns
+// when the client accepts none of the response types.
+var ErrNotAcceptable = errors.New("not acceptable")
+
+// RequireContentTypeMiddleware returns a HandlerFunc-based middleware that
+// rejects requests whose Content-Type is not one of types with a 415
+// Unsupported Media Type erm.Error (wrapping ErrUnsupportedMediaType),
+// handled by the Mux error handler. Types are media types such as
+// "application/json" and may end in "/*" to accept a whole family;
+// parameters such as charset are ignored.
+//
+// Only POST, PUT and PATCH requests and requests with a body are checked, so
+// the middleware can guard a whole API without rejecting GET or DELETE
+// requests that have no Content-Type.
+//
+// Example:
+//
+//	mux.Middleware(srv.RequireContentTypeMiddleware(srv.MIMEApplicationJSON))
+func RequireContentTypeMiddleware(types ...string) HandlerFuncMiddleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			req := ctx.Request()
+			switch {
+			case req.Method == http.MethodPost, req.Method == http.MethodPut, req.Method == http.MethodPatch:
+			case req.ContentLength == 0:
+				return next(ctx) // No body to check
+			}
+
+			mediaType, _, err := mime.ParseMediaType(ctx.GetHeader(HeaderContentType))
+			if err != nil || !slices.ContainsFunc(types, func(t string) bool { return mediaTypeMatches(t, mediaType) }) {
+				return erm.New(http.StatusUnsupportedMediaType, "unsupported media type", ErrUnsupportedMediaType)
+			}
+			return next(ctx)
+		}
+	}
+}
+
+// RequireAcceptMiddleware returns a HandlerFunc-based middleware that
+// rejects requests whose Accept header allows none of types with a 406 Not
+// Acceptable erm.Error (wrapping ErrNotAcceptable), handled by the Mux error
+// handler. Accept ranges such as "*/*" and "application/*" match, and ranges
+// with q=0 are excluded. A request without an Accept header accepts any
+// type, as specified by RFC 9110.
+//
+// Example:
+//
+//	mux.Middleware(srv.RequireAcceptMiddleware(srv.MIMEApplicationJSON))
+func RequireAcceptMiddleware(types ...string) HandlerFuncMiddleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			accept := ctx.GetHeader(HeaderAccept)
+			if accept == "" || acceptsAny(accept, types) {
+				return next(ctx)
+			}
+			return erm.New(http.StatusNotAcceptable, "not acceptable", ErrNotAcceptable)
+		}
+	}
+}
+
+// acceptsAny reports whether the Accept header value allows any of types.
+func acceptsAny(accept string, types []string) bool {
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaRange, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+		if q, err := strconv.ParseFloat(params["q"], 64); err == nil && q <= 0 {
+			continue
+		}
+		for _, t := range types {
+			if mediaTypeMatches(mediaRange, t) || mediaTypeMatches(t, mediaRange) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mediaTypeMatches reports whether mediaType is matched by pattern, a media
+// type that may be "*/*" or end in "/*". mime.ParseMediaType lowercases its
+// result; pattern is compared case-insensitively.
+func mediaTypeMatches(pattern, mediaType string) bool {
+	pattern = strings.ToLower(pattern)
+	if pattern == "*/*" || pattern == mediaType {
+		return true
+	}
+	family, ok := strings.CutSuffix(pattern, "/*")
+	return ok && strings.HasPrefix(mediaType, family+"/")
+}
+
+// =============================================================================
+// Body Logging Middleware
+// =============================================================================
+
+// redactedValue replaces the values of redacted fields in logged bodies.
+const redactedValue = "[REDACTED]"
+
+// BodyLoggingConfig defines the configuration for BodyLoggingMiddleware.
+type BodyLoggingConfig struct {
+	// Skipper bypasses the middleware for requests for which it returns
+	// true, e.g. to exempt some paths.
+	//
+	// Optional. Default value nil (never skip).
+	Skipper Skipper
+
+	// RedactKeys lists the JSON object keys and form field names whose values
+	// are replaced with "[REDACTED]" before logging. Matching is
+	// case-insensitive and applies at any nesting depth.
+	//
+	// Optional. Default value []string{"password", "token", "access_token",
+	// "refresh_token", "secret", "api_key", "authorization"}.
+	RedactKeys []string
+
+	// MaxBodySize is the maximum number of bytes of each body that is logged.
+	// JSON and form bodies larger than this cannot be redacted reliably, so
+	// only their size is logged.
+	//
+	// Optional. Default value 4096.
+	MaxBodySize int
+
+	// Level is the slog level used for the log entries.
+	//
+	// Optional. Default value slog.LevelDebug.
+	Level slog.Level
+}
+
+// DefaultBodyLoggingConfig is the default BodyLogging middleware config.
+var DefaultBodyLoggingConfig = BodyLoggingConfig{
+	Skipper: nil,
+	RedactKeys: []string{
+		"password", "token", "access_token", "refresh_token",
+		"secret", "api_key", "authorization",
+	},
+	MaxBodySize: 4096,
+	Level:       slog.LevelDebug,
+}
+
+// BodyLoggingMiddleware returns a HandlerFunc-based middleware that logs the
+// request and response bodies of each request. It is meant for debugging and
+// reproducing client issues and should not be enabled in production.
+//
+// The request body is read up to MaxBodySize and then restored, so handlers
+// still see the complete body. The response is captured through a writer that
+// passes everything through unchanged, so streaming responses keep working.
+//
+// JSON (application/json and +json types) and URL-encoded form bodies are
+// redacted by key according to RedactKeys. Other content types are logged
+// as-is, truncated to MaxBodySize.
+//
+// The entry is logged with:
+//   - name: "srv.BodyLogging" (logger identifier)
+//   - method, path: request method and URL path
+//   - status: response status code
+//   - request-body, response-body: the (redacted) bodies
+//
+// Example:
+//
+//	if cfg.Debug {
+//		mux.Middleware(srv.BodyLoggingMiddleware(srv.DefaultBodyLoggingConfig))
+//	}
+func BodyLoggingMiddleware(config BodyLoggingConfig) HandlerFuncMiddleware {
+	if config.RedactKeys == nil {
+		config.RedactKeys = DefaultBodyLoggingConfig.RedactKeys
+	}
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = DefaultBodyLoggingConfig.MaxBodySize
+	}
+
+	redact := make(map[string]struct{}, len(config.RedactKeys))
+	for _, key := range config.RedactKeys {
+		redact[strings.ToLower(key)] = struct{}{}
+	}
+
+	return skippable(config.Skipper, func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			req := ctx.Request()
+
+			var requestBody []byte
+			requestTruncated := false
+			if req.Body != nil && req.Body != http.NoBody {
+				prefix, err := io.ReadAll(io.LimitReader(req.Body, int64(config.MaxBodySize)+1))
+				if err != nil {
+					return fmt.Errorf("failed to read request body: %w", err)
+				}
+				// Restore the body: the bytes already read followed by the rest.
+				req.Body = struct {
+					io.Reader
+					io.Closer
+				}{io.MultiReader(bytes.NewReader(prefix), req.Body), req.Body}
+
+				requestTruncated = len(prefix) > config.MaxBodySize
+				if requestTruncated {
+					prefix = prefix[:config.MaxBodySize]
+				}
+				requestBody = prefix
+			}
+
+			original := ctx.Response()
+			tee := newTeeResponseWriter(original, config.MaxBodySize)
+			ctx.SetResponse(tee)
+			defer ctx.SetResponse(original)
+
+			err := next(ctx)
+
+			slog.With(
+				slog.String("name", "srv.BodyLogging"),
+				slog.String("method", req.Method),
+				slog.String("path", req.URL.Path),
+				slog.Int("status", tee.Status()),
+				slog.String("request-body", formatLoggedBody(requestBody, requestTruncated, req.Header.Get(HeaderContentType), redact)),
+				slog.String("response-body", formatLoggedBody(tee.body.Bytes(), tee.truncated, original.Header().Get(HeaderContentType), redact)),
+			).Log(req.Context(), config.Level, "request body")
+
+			return err
+		}
+	})
+}
+
+// formatLoggedBody renders a captured body for logging, redacting JSON and
+// form bodies by key.
+func formatLoggedBody(body []byte, truncated bool, contentType string, redact map[string]struct{}) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	isJSON := mediaType == MIMEApplicationJSON || strings.HasSuffix(mediaType, "+json")
+	isForm := mediaType == MIMEApplicationForm
+
+	if (isJSON || isForm) && truncated {
+		return fmt.Sprintf("[%d+ bytes, too large to redact]", len(body))
+	}
+
+	switch {
+	case isJSON:
+		var data interface{}
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		if err := decoder.Decode(&data); err != nil {
+			return "[invalid JSON body]"
+		}
+		redacted, err := json.Marshal(redactJSON(data, redact))
+		if err != nil {
+			return "[invalid JSON body]"
+		}
+		return string(redacted)
+	case isForm:
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return "[invalid form body]"
+		}
+		for key := range values {
+			if _, ok := redact[strings.ToLower(key)]; ok {
+				values[key] = []string{redactedValue}
+			}
+		}
+		return values.Encode()
+	}
+
+	if truncated {
+		return string(body) + "...[truncated]"
 	}
+	return string(body)
+}
+
+// redactJSON replaces the values of redacted keys in decoded JSON data.
+func redactJSON(data interface{}, redact map[string]struct{}) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if _, ok := redact[strings.ToLower(key)]; ok {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactJSON(value, redact)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactJSON(value, redact)
+		}
+	}
+	return data
 }
 
 // =============================================================================
@@ -406,6 +1138,10 @@ func AddTrailingSlashMiddleware(config TrailingSlashConfig) HandlerFuncMiddlewar
 // creates new ones as needed, makes the session available through the Context,
 // and automatically saves the session after the request completes.
 //
+// Existing sessions are only saved if they were modified (see
+// Session.Modified), so requests that merely read the session do not send a
+// Set-Cookie header. Note that this also means their expiry is not extended.
+//
 // The middleware integrates seamlessly with the srv package's Context interface,
 // allowing easy session access via ctx.Get("session") or helper methods.
 //
@@ -427,7 +1163,60 @@ func AddTrailingSlashMiddleware(config TrailingSlashConfig) HandlerFuncMiddlewar
 //		return ctx.JSON(200, map[string]interface{}{"userID": userID})
 //	})
 func SessionMiddleware(store Store, sessionName string) HandlerFuncMiddleware {
-	return func(next HandlerFunc) HandlerFunc {
+	return SessionMiddlewareWithConfig(store, sessionName, DefaultSessionConfig)
+}
+
+// SessionConfig defines the configuration for SessionMiddlewareWithConfig.
+type SessionConfig struct {
+	// Skipper bypasses the middleware for requests for which it returns
+	// true, e.g. to exempt static assets. Skipped requests have no
+	// "session" in the context.
+	//
+	// Optional. Default value nil (never skip).
+	Skipper Skipper
+
+	// BeforeSave runs before the session is saved and can update it, e.g.
+	// to record a "last seen" timestamp. Returning false skips the save,
+	// e.g. to avoid setting a cookie for anonymous requests. It also runs
+	// for unmodified sessions, which are then only saved if BeforeSave
+	// modified them.
+	//
+	// The session is saved right before the response is committed and again
+	// after the handler returns, so BeforeSave may run twice per request.
+	//
+	// Optional. Default value nil (always save).
+	BeforeSave func(ctx Context, session *Session) bool
+
+	// AfterSave runs after each save with its result. Failed saves are
+	// logged either way.
+	//
+	// Optional. Default value nil.
+	AfterSave func(ctx Context, session *Session, err error)
+}
+
+// DefaultSessionConfig is the default Session middleware config.
+var DefaultSessionConfig = SessionConfig{
+	Skipper:    nil,
+	BeforeSave: nil,
+	AfterSave:  nil,
+}
+
+// SessionMiddlewareWithConfig returns a SessionMiddleware with the given
+// config.
+//
+// Example (only save sessions of signed-in users):
+//
+//	mux.Middleware(srv.SessionMiddlewareWithConfig(store, "myapp-session", srv.SessionConfig{
+//		BeforeSave: func(ctx srv.Context, session *srv.Session) bool {
+//			if session.Get("userID") == "" {
+//				return false
+//			}
+//			session.Set("lastSeen", time.Now().Format(time.RFC3339))
+//			return true
+//		},
+//	}))
+func SessionMiddlewareWithConfig(store Store, sessionName string, config SessionConfig) HandlerFuncMiddleware {
+	return skippable(config.Skipper, func(next HandlerFunc) HandlerFunc {
 		return func(ctx Context) error {
 			req := ctx.Request()
 
@@ -444,21 +1233,56 @@ func SessionMiddleware(store Store, sessionName string) HandlerFuncMiddleware {
 			// Store session in context for handler access
 			ctx.Set("session", session)
 
+			save := func(w http.ResponseWriter) {
+				if config.BeforeSave != nil && !config.BeforeSave(ctx, session) {
+					return
+				}
+				if !session.IsNew && !session.Modified() {
+					return
+				}
+				err := session.Save(req, w)
+				if config.AfterSave != nil {
+					config.AfterSave(ctx, session, err)
+				}
+				logSessionSaveError(err)
+			}
+
+			// Save the session right before the response is committed, while
+			// the session cookie can still be set.
+			original := ctx.Response()
+			hooked := newHookResponseWriter(original, func() {
+				save(original)
+			})
+			ctx.SetResponse(hooked)
+			defer ctx.SetResponse(original)
+
 			// Execute the handler
 			err = next(ctx)
 
-			// Save session after request (regardless of handler error)
-			if saveErr := session.Save(req, ctx.Response()); saveErr != nil {
-				// Log save error but don't override handler error
-				slog.With(
-					slog.String("name", "srv.SessionMiddleware"),
-					slog.String("error", saveErr.Error()),
-				).Error("failed to save session")
+			// Save session after request (regardless of handler error). If
+			// the response was already committed, the cookie was set by the
+			// hook and this only persists values changed afterwards.
+			w := original
+			if hooked.Fired() {
+				w = discardResponseWriter{}
 			}
+			save(w)
 
 			return err
 		}
+	})
+}
+
+// logSessionSaveError logs a failed session save without overriding the
+// handler's error.
+func logSessionSaveError(err error) {
+	if err == nil {
+		return
 	}
+	slog.With(
+		slog.String("name", "srv.SessionMiddleware"),
+		slog.String("error", err.Error()),
+	).Error("failed to save session")
 }
 
 // sanitizeURI prevents open redirect attacks by sanitizing URIs that start with
@@ -492,7 +1316,81 @@ type Options struct {
 	// This prevents access via JavaScript, mitigating XSS attacks.
 	HttpOnly bool
 	// SameSite controls when cookies are sent with cross-site requests.
+	//
+	// Use http.SameSiteNoneMode when the session must survive cross-site
+	// requests, for example when the application is embedded as a
+	// third-party widget in an iframe on another site. Browsers reject
+	// SameSite=None cookies that are not also Secure, so Validate (run by the
+	// stores on Save) refuses that combination.
 	SameSite http.SameSite
+	// MaxCookieSize limits the length of the cookie value written by
+	// CookieStore, which fails Save for larger sessions. Browsers commonly
+	// cap a whole cookie (name, value and attributes) at 4096 bytes; raise
+	// the limit only if all clients accept larger cookies. It has no effect
+	// on InMemoryStore, whose cookie only carries the session ID.
+	//
+	// Optional. Default value 4000 (DefaultMaxCookieSize) when <= 0.
+	MaxCookieSize int
+}
+
+// DefaultMaxCookieSize is the cookie value limit CookieStore applies when
+// Options.MaxCookieSize is not set.
+const DefaultMaxCookieSize = 4000
+
+// ErrSameSiteNoneInsecure is returned when Options use SameSite=None without
+// Secure. Browsers silently reject such cookies.
+var ErrSameSiteNoneInsecure = errors.New("session cookie with SameSite=None must also be Secure")
+
+// Validate checks the options for combinations that browsers reject.
+// It returns ErrSameSiteNoneInsecure if SameSite is http.SameSiteNoneMode and
+// Secure is false.
+//
+// Example (cross-site embedding):
+//
+//	options := srv.NewOptions()
+//	options.SameSite = http.SameSiteNoneMode // sent in third-party iframes
+//	options.Secure = true                    // required by browsers for None
+//	if err := options.Validate(); err != nil {
+//		log.Fatal(err)
+//	}
+func (o *Options) Validate() error {
+	if o.SameSite == http.SameSiteNoneMode && !o.Secure {
+		return ErrSameSiteNoneInsecure
+	}
+	return nil
+}
+
+// ErrCookiePrefix is returned when a cookie name uses the __Secure- or
+// __Host- prefix but the options do not meet the prefix requirements.
+var ErrCookiePrefix = errors.New("session cookie options do not satisfy the cookie name prefix")
+
+// ValidateName checks that the options satisfy the requirements browsers
+// enforce for cookie name prefixes (RFC 6265bis), and then runs Validate:
+//   - "__Secure-" requires Secure.
+//   - "__Host-" requires Secure, Path "/" and no Domain, which pins the
+//     cookie to the exact host that set it.
+//
+// Prefixes are matched case-insensitively, as browsers do. Names without a
+// prefix only go through Validate. The stores call ValidateName with the
+// session name on Save, and NewCookieStore checks it up front.
+//
+// Example:
+//
+//	options := srv.NewOptions() // Secure, Path "/" and no Domain by default
+//	store, err := srv.NewCookieStore("__Host-session", key, options)
+func (o *Options) ValidateName(name string) error {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasPrefix(lower, "__host-"):
+		if !o.Secure || o.Path != "/" || o.Domain != "" {
+			return fmt.Errorf("%w: __Host- requires Secure, Path \"/\" and no Domain", ErrCookiePrefix)
+		}
+	case strings.HasPrefix(lower, "__secure-"):
+		if !o.Secure {
+			return fmt.Errorf("%w: __Secure- requires Secure", ErrCookiePrefix)
+		}
+	}
+	return o.Validate()
 }
 
 // NewOptions returns Options with secure defaults.
@@ -518,6 +1416,18 @@ type Session struct {
 	IsNew   bool
 	store   Store
 	name    string
+	// destroyed is set by Destroy so that later saves (e.g. by
+	// SessionMiddleware) do not resurrect the session.
+	destroyed bool
+	// rotated is set once a store has issued a new ID for the session, so
+	// that saving it again in the same request does not rotate again.
+	rotated bool
+	// modified is set by Set, Delete, Clear and MarkModified and reset by a
+	// successful Save.
+	modified bool
+	// version is the store's version of the session when it was loaded or
+	// last saved, used by InMemoryStore.CheckVersion.
+	version uint64
 }
 
 // Get retrieves a value from the session by key.
@@ -528,12 +1438,43 @@ func (s *Session) Get(key string) string {
 	return s.Values.Get(key)
 }
 
+// GetInt returns the value for key parsed as an int. It returns 0 and false
+// if the key is missing or the value is not a valid integer.
+func (s *Session) GetInt(key string) (int, bool) {
+	value, err := strconv.Atoi(s.Get(key))
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// GetInt64 returns the value for key parsed as an int64. It returns 0 and
+// false if the key is missing or the value is not a valid integer.
+func (s *Session) GetInt64(key string) (int64, bool) {
+	value, err := strconv.ParseInt(s.Get(key), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// GetBool returns the value for key parsed with strconv.ParseBool. It returns
+// false and false if the key is missing or the value is not a valid boolean.
+func (s *Session) GetBool(key string) (bool, bool) {
+	value, err := strconv.ParseBool(s.Get(key))
+	if err != nil {
+		return false, false
+	}
+	return value, true
+}
+
 // Set stores a value in the session with the given key.
 func (s *Session) Set(key, value string) {
 	if s.Values == nil {
 		s.Values = url.Values{}
 	}
 	s.Values.Set(key, value)
+	s.modified = true
 }
 
 // Delete removes a key from the session.
@@ -542,19 +1483,67 @@ func (s *Session) Delete(key string) {
 		return
 	}
 	s.Values.Del(key)
+	s.modified = true
 }
 
 // Clear removes all values from the session.
 func (s *Session) Clear() {
 	s.Values = url.Values{}
+	s.modified = true
 }
 
-// Save persists the session to the underlying store.
+// MarkModified flags the session as changed, e.g. after editing Values
+// directly, so that SessionMiddleware saves it.
+func (s *Session) MarkModified() {
+	s.modified = true
+}
+
+// Modified reports whether the session changed since it was loaded or last
+// saved.
+func (s *Session) Modified() bool {
+	return s.modified
+}
+
+// Save persists the session to the underlying store and resets Modified.
+// Saving a destroyed session is a no-op.
 func (s *Session) Save(r *http.Request, w http.ResponseWriter) error {
 	if s.store == nil {
 		return fmt.Errorf("no store configured for session")
 	}
-	return s.store.Save(r, w, s)
+	if s.destroyed {
+		return nil
+	}
+	if err := s.store.Save(r, w, s); err != nil {
+		return err
+	}
+	s.modified = false
+	return nil
+}
+
+// Destroy removes the session from its store and expires the session cookie,
+// e.g. on logout. The session's values are cleared, and subsequent calls to
+// Save (including the one made by SessionMiddleware after the handler
+// returns) do nothing.
+//
+// Example:
+//
+//	mux.Post("logout", "/logout", func(ctx srv.Context) error {
+//		session := ctx.Get("session").(*srv.Session)
+//		if err := session.Destroy(ctx.Request(), ctx.Response()); err != nil {
+//			return err
+//		}
+//		return ctx.Redirect(302, "/")
+//	})
+func (s *Session) Destroy(r *http.Request, w http.ResponseWriter) error {
+	if s.store == nil {
+		return fmt.Errorf("no store configured for session")
+	}
+	if err := s.store.Delete(r, w, s); err != nil {
+		return err
+	}
+	s.Clear()
+	s.destroyed = true
+	return nil
 }
 
 // Store defines the interface for session storage backends.
@@ -571,6 +1560,26 @@ type Store interface {
 
 	// Save should persist session to the underlying store implementation.
 	Save(r *http.Request, w http.ResponseWriter, s *Session) error
+
+	// Delete should remove the session from the underlying store
+	// implementation, if it keeps server-side state, and instruct the client
+	// to drop the session cookie.
+	Delete(r *http.Request, w http.ResponseWriter, s *Session) error
+}
+
+// expireSessionCookie sets an already expired cookie for the session so that
+// the browser removes it.
+func expireSessionCookie(w http.ResponseWriter, session *Session) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     session.name,
+		Value:    "",
+		Path:     session.Options.Path,
+		Domain:   session.Options.Domain,
+		MaxAge:   -1, // Delete cookie
+		Secure:   session.Options.Secure,
+		HttpOnly: session.Options.HttpOnly,
+		SameSite: session.Options.SameSite,
+	})
 }
 
 // =============================================================================
@@ -579,22 +1588,69 @@ type Store interface {
 
 // sessionData holds session information with expiration.
 type sessionData struct {
-	Values    url.Values
-	CreatedAt time.Time
-	ExpiresAt time.Time
+	Values     url.Values
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	LastAccess time.Time
+	Version    uint64        // incremented by every Save
+	element    *list.Element // position in the LRU list; Value is the session ID
 }
 
 // InMemoryStore provides an in-memory session store implementation.
 // It is thread-safe and suitable for development and single-instance deployments.
 // For production with multiple instances, consider a distributed store.
 type InMemoryStore struct {
-	mu       sync.RWMutex
+	// MaxSessions bounds the number of stored sessions. When saving a new
+	// session would exceed it, the least recently used sessions (by Get or
+	// Save) are evicted. This keeps memory bounded even when an attacker
+	// creates sessions faster than they expire.
+	//
+	// Optional. Default value 0 (unlimited). Set it before the store is used.
+	MaxSessions int
+
+	// RotateOnSave issues a new session ID once per request in which an
+	// existing session is saved: the data moves to the new ID, the old ID is
+	// invalidated and the cookie is updated. Further saves of the same
+	// Session keep the new ID. This shortens the window in which a stolen
+	// session ID is usable, at the cost of breaking concurrent requests that
+	// still carry the previous ID.
+	//
+	// Optional. Default value false. Set it before the store is used.
+	RotateOnSave bool
+
+	// CheckVersion enables optimistic concurrency control: Save fails with
+	// ErrSessionConflict if the session was saved by another request (or
+	// removed, e.g. by Delete or expiry) since it was loaded, instead of silently overwriting those
+	// changes. Load the session again with Get, reapply the change and save
+	// it to retry. Sessions that are not modified are not saved by
+	// SessionMiddleware, so concurrent read-only requests never conflict.
+	//
+	// Optional. Default value false (last write wins). Set it before the
+	// store is used.
+	CheckVersion bool
+
+	mu       sync.Mutex
 	sessions map[string]*sessionData
+	lru      *list.List // front is the most recently used session
 	options  *Options
 	name     string
 	cleanup  *time.Ticker
 }
 
+// ErrSessionConflict is returned by InMemoryStore.Save when CheckVersion is
+// enabled and the session was changed by another request since it was
+// loaded.
+var ErrSessionConflict = errors.New("session was modified concurrently")
+
+// cloneValues returns a deep copy of values.
+func cloneValues(values url.Values) url.Values {
+	clone := make(url.Values, len(values))
+	for key, value := range values {
+		clone[key] = slices.Clone(value)
+	}
+	return clone
+}
+
 // NewInMemoryStore creates a new in-memory session store with the specified options.
 // It automatically starts a cleanup routine to remove expired sessions.
 func NewInMemoryStore(name string, options *Options) *InMemoryStore {
@@ -604,6 +1660,7 @@ func NewInMemoryStore(name string, options *Options) *InMemoryStore {
 
 	store := &InMemoryStore{
 		sessions: make(map[string]*sessionData),
+		lru:      list.New(),
 		options:  options,
 		name:     name,
 		cleanup:  time.NewTicker(time.Hour), // Cleanup expired sessions every hour
@@ -622,21 +1679,28 @@ func (s *InMemoryStore) Get(r *http.Request, name string) (*Session, error) {
 		return nil, err
 	}
 
-	s.mu.RLock()
+	now := time.Now()
+	s.mu.Lock()
 	data, exists := s.sessions[cookie.Value]
-	s.mu.RUnlock()
-
-	if !exists || time.Now().After(data.ExpiresAt) {
+	if !exists || now.After(data.ExpiresAt) {
+		s.mu.Unlock()
 		return nil, http.ErrNoCookie
 	}
+	data.LastAccess = now
+	s.lru.MoveToFront(data.element)
+	// Copy the values so that concurrent requests for the same session do
+	// not share (and race on) one map
+	values, version := cloneValues(data.Values), data.Version
+	s.mu.Unlock()
 
 	session := &Session{
 		ID:      cookie.Value,
-		Values:  data.Values,
+		Values:  values,
 		Options: s.options,
 		IsNew:   false,
 		store:   s,
 		name:    name,
+		version: version,
 	}
 
 	return session, nil
@@ -662,10 +1726,15 @@ func (s *InMemoryStore) New(_ *http.Request, name string) (*Session, error) {
 }
 
 // Save persists the session to the in-memory store and sets the session cookie.
+// It returns an error without storing anything if the session options are
+// invalid (see Options.ValidateName).
 func (s *InMemoryStore) Save(_ *http.Request, w http.ResponseWriter, session *Session) error {
 	if session.ID == "" {
 		return fmt.Errorf("session ID is empty")
 	}
+	if err := session.Options.ValidateName(session.name); err != nil {
+		return err
+	}
 
 	// Calculate expiration time
 	now := time.Now()
@@ -677,12 +1746,45 @@ func (s *InMemoryStore) Save(_ *http.Request, w http.ResponseWriter, session *Se
 		expiresAt = now.Add(24 * time.Hour) // Default to 24 hours for cleanup
 	}
 
+	// Generate the replacement ID before taking the lock
+	var rotatedID string
+	if s.RotateOnSave && !session.IsNew && !session.rotated {
+		id, err := generateSessionID()
+		if err != nil {
+			return fmt.Errorf("failed to generate session ID: %w", err)
+		}
+		rotatedID = id
+	}
+
 	// Store session data
 	s.mu.Lock()
+	existing, exists := s.sessions[session.ID]
+	if s.CheckVersion && !session.IsNew && (!exists || existing.Version != session.version) {
+		s.mu.Unlock()
+		return ErrSessionConflict
+	}
+	if exists {
+		session.version = existing.Version
+	}
+	session.version++
+	if rotatedID != "" {
+		// Drop the old entry so the previous ID can no longer be used
+		s.removeLocked(session.ID)
+		session.ID = rotatedID
+		session.rotated = true
+	} else if exists {
+		s.lru.Remove(existing.element)
+	}
 	s.sessions[session.ID] = &sessionData{
-		Values:    session.Values,
-		CreatedAt: now,
-		ExpiresAt: expiresAt,
+		Values:     cloneValues(session.Values),
+		CreatedAt:  now,
+		ExpiresAt:  expiresAt,
+		LastAccess: now,
+		Version:    session.version,
+		element:    s.lru.PushFront(session.ID),
+	}
+	for s.MaxSessions > 0 && len(s.sessions) > s.MaxSessions {
+		s.removeLocked(s.lru.Back().Value.(string))
 	}
 	s.mu.Unlock()
 
@@ -706,6 +1808,17 @@ func (s *InMemoryStore) Save(_ *http.Request, w http.ResponseWriter, session *Se
 	return nil
 }
 
+// Delete removes the session from the in-memory store and expires the
+// session cookie.
+func (s *InMemoryStore) Delete(_ *http.Request, w http.ResponseWriter, session *Session) error {
+	s.mu.Lock()
+	s.removeLocked(session.ID)
+	s.mu.Unlock()
+
+	expireSessionCookie(w, session)
+	return nil
+}
+
 // Close stops the cleanup routine and clears all sessions.
 // This should be called when the store is no longer needed.
 func (s *InMemoryStore) Close() {
@@ -715,6 +1828,16 @@ func (s *InMemoryStore) Close() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.sessions = make(map[string]*sessionData)
+	s.lru.Init()
+}
+
+// removeLocked deletes a session from the map and the LRU list.
+// The caller must hold s.mu.
+func (s *InMemoryStore) removeLocked(id string) {
+	if data, exists := s.sessions[id]; exists {
+		s.lru.Remove(data.element)
+		delete(s.sessions, id)
+	}
 }
 
 // cleanupExpiredSessions removes expired sessions from the store.
@@ -724,7 +1847,7 @@ func (s *InMemoryStore) cleanupExpiredSessions() {
 		s.mu.Lock()
 		for id, data := range s.sessions {
 			if now.After(data.ExpiresAt) {
-				delete(s.sessions, id)
+				s.removeLocked(id)
 			}
 		}
 		s.mu.Unlock()
@@ -754,8 +1877,48 @@ func generateSessionID() (string, error) {
 //
 // This implementation is suitable for stateless applications or when you want
 // to avoid server-side session storage. However, be aware of cookie size limits
-// (typically ~4KB) and ensure your session data fits within these constraints.
+// (typically ~4KB) and ensure your session data fits within these constraints;
+// the limit enforced on Save is set by Options.MaxCookieSize.
 type CookieStore struct {
+	// Compress gzips the gob-encoded session values before encryption,
+	// which lets repetitive data such as long keys or JSON fit more
+	// comfortably under the cookie size limit. The size limit still applies
+	// to the final cookie value. Cookies are decoded correctly whether or
+	// not they were compressed, so the option can be toggled without
+	// invalidating existing sessions.
+	//
+	// Optional. Default value false. Set it before the store is used.
+	Compress bool
+
+	// Version is the format version of the session data, stored in every
+	// cookie the store writes. Increment it when the meaning of session
+	// values changes, and upgrade older sessions in Migrate. Cookies written
+	// before versioning was introduced have version 0.
+	//
+	// Optional. Default value 0. Set it before the store is used.
+	Version uint8
+
+	// Migrate upgrades the values of a session whose cookie has a version
+	// other than Version, so that format changes do not log users out.
+	// Returning an error discards the session as if there were no cookie.
+	// The migrated values are written with the current Version on the next
+	// Save.
+	//
+	// Optional. Default value nil (values of any version are used as is).
+	// Set it before the store is used.
+	//
+	// Example:
+	//
+	//	store.Version = 2
+	//	store.Migrate = func(version uint8, values url.Values) (url.Values, error) {
+	//		if version < 2 {
+	//			values.Set("user_id", values.Get("uid")) // renamed in version 2
+	//			values.Del("uid")
+	//		}
+	//		return values, nil
+	//	}
+	Migrate func(version uint8, values url.Values) (url.Values, error)
+
 	cipher  cipher.AEAD
 	options *Options
 	name    string
@@ -768,6 +1931,9 @@ type CookieStore struct {
 // The store uses AES-GCM for authenticated encryption, providing both
 // confidentiality and integrity protection for session data.
 //
+// An error is also returned if the options are invalid for the cookie name
+// (see Options.ValidateName).
+//
 // Example usage:
 //
 //	// Generate a 32-byte key for AES-256
@@ -792,6 +1958,9 @@ func NewCookieStore(name string, key []byte, options *Options) (*CookieStore, er
 	if options == nil {
 		options = NewOptions()
 	}
+	if err := options.ValidateName(name); err != nil {
+		return nil, err
+	}
 
 	// Create AES cipher
 	block, err := aes.NewCipher(key)
@@ -828,19 +1997,33 @@ func (c *CookieStore) Get(r *http.Request, name string) (*Session, error) {
 	}
 
 	// Decrypt and deserialize session data
-	values, err := c.decryptSessionData(encryptedData)
+	values, version, err := c.decryptSessionData(encryptedData)
 	if err != nil {
 		return nil, http.ErrNoCookie
 	}
 
-	// Create session with decrypted data
+	// Upgrade sessions written in another format version
+	migrated := false
+	if version != c.Version && c.Migrate != nil {
+		if values, err = c.Migrate(version, values); err != nil {
+			return nil, http.ErrNoCookie
+		}
+		if values == nil {
+			values = url.Values{}
+		}
+		migrated = true
+	}
+
+	// Create session with decrypted data. Migrated sessions are marked as
+	// modified so that the cookie is rewritten in the current version.
 	session := &Session{
-		ID:      "", // Not used for cookie store
-		Values:  values,
-		Options: c.options,
-		IsNew:   false,
-		store:   c,
-		name:    name,
+		ID:       "", // Not used for cookie store
+		Values:   values,
+		Options:  c.options,
+		IsNew:    false,
+		store:    c,
+		name:     name,
+		modified: migrated,
 	}
 
 	return session, nil
@@ -865,21 +2048,16 @@ func (c *CookieStore) New(_ *http.Request, name string) (*Session, error) {
 // Save encrypts the session data and stores it as a cookie.
 // The session values are serialized using gob encoding and then
 // encrypted using AES-GCM before being base64 encoded and stored
-// in the cookie.
+// in the cookie. It returns an error without setting the cookie if the
+// session options are invalid (see Options.ValidateName).
 func (c *CookieStore) Save(_ *http.Request, w http.ResponseWriter, session *Session) error {
+	if err := session.Options.ValidateName(session.name); err != nil {
+		return err
+	}
+
 	if len(session.Values) == 0 {
 		// Clear cookie if session is empty
-		cookie := &http.Cookie{
-			Name:     session.name,
-			Value:    "",
-			Path:     session.Options.Path,
-			Domain:   session.Options.Domain,
-			MaxAge:   -1, // Delete cookie
-			Secure:   session.Options.Secure,
-			HttpOnly: session.Options.HttpOnly,
-			SameSite: session.Options.SameSite,
-		}
-		http.SetCookie(w, cookie)
+		expireSessionCookie(w, session)
 		return nil
 	}
 
@@ -893,8 +2071,12 @@ func (c *CookieStore) Save(_ *http.Request, w http.ResponseWriter, session *Sess
 	cookieValue := base64.URLEncoding.EncodeToString(encryptedData)
 
 	// Check cookie size limit (browsers typically limit to ~4KB)
-	if len(cookieValue) > 4000 {
-		return errors.New("session data too large for cookie storage (>4KB)")
+	maxSize := session.Options.MaxCookieSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxCookieSize
+	}
+	if len(cookieValue) > maxSize {
+		return fmt.Errorf("session data too large for cookie storage (%d > %d bytes)", len(cookieValue), maxSize)
 	}
 
 	// Set cookie
@@ -917,7 +2099,27 @@ func (c *CookieStore) Save(_ *http.Request, w http.ResponseWriter, session *Sess
 	return nil
 }
 
-// encryptSessionData serializes and encrypts session values using AES-GCM.
+// Delete expires the session cookie. The cookie store keeps no server-side
+// state, so there is nothing else to remove.
+func (c *CookieStore) Delete(_ *http.Request, w http.ResponseWriter, session *Session) error {
+	expireSessionCookie(w, session)
+	return nil
+}
+
+// gzipMagic starts every gzip stream. A gob stream never starts with it: the
+// leading message length is followed by a negative type ID, whose encoding
+// begins with a byte count (>= 0xF8), so compressed and uncompressed session
+// data can be told apart without a marker.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// sessionVersionMarker precedes the version byte of session data. Neither
+// gob nor gzip streams start with it, so session data written before
+// versioning (version 0) is still recognized.
+const sessionVersionMarker = 0x00
+
+// encryptSessionData serializes, optionally compresses and encrypts session
+// values using AES-GCM. The plaintext starts with sessionVersionMarker and
+// the store's Version.
 func (c *CookieStore) encryptSessionData(values url.Values) ([]byte, error) {
 	// Serialize session values using gob
 	var buf bytes.Buffer
@@ -927,6 +2129,18 @@ func (c *CookieStore) encryptSessionData(values url.Values) ([]byte, error) {
 	}
 
 	plaintext := buf.Bytes()
+	if c.Compress {
+		var compressed bytes.Buffer
+		zw := gzip.NewWriter(&compressed)
+		if _, err := zw.Write(plaintext); err != nil {
+			return nil, fmt.Errorf("failed to compress session data: %w", err)
+		}
+		if err := zw.Close(); err != nil {
+			return nil, fmt.Errorf("failed to compress session data: %w", err)
+		}
+		plaintext = compressed.Bytes()
+	}
+	plaintext = append([]byte{sessionVersionMarker, c.Version}, plaintext...)
 
 	// Generate random nonce
 	nonce := make([]byte, c.cipher.NonceSize())
@@ -939,10 +2153,11 @@ func (c *CookieStore) encryptSessionData(values url.Values) ([]byte, error) {
 	return ciphertext, nil
 }
 
-// decryptSessionData decrypts and deserializes session values.
-func (c *CookieStore) decryptSessionData(encryptedData []byte) (url.Values, error) {
+// decryptSessionData decrypts, decompresses if needed and deserializes
+// session values. It also returns the format version of the data.
+func (c *CookieStore) decryptSessionData(encryptedData []byte) (url.Values, uint8, error) {
 	if len(encryptedData) < c.cipher.NonceSize() {
-		return nil, errors.New("encrypted data too short")
+		return nil, 0, errors.New("encrypted data too short")
 	}
 
 	// Extract nonce and ciphertext
@@ -952,15 +2167,30 @@ func (c *CookieStore) decryptSessionData(encryptedData []byte) (url.Values, erro
 	// Decrypt with AES-GCM (includes authentication verification)
 	plaintext, err := c.cipher.Open(nil, nonce, ciphertext, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decrypt session data: %w", err)
+		return nil, 0, fmt.Errorf("failed to decrypt session data: %w", err)
+	}
+
+	var version uint8
+	if len(plaintext) >= 2 && plaintext[0] == sessionVersionMarker {
+		version, plaintext = plaintext[1], plaintext[2:]
+	}
+
+	var data io.Reader = bytes.NewReader(plaintext)
+	if bytes.HasPrefix(plaintext, gzipMagic) {
+		zr, err := gzip.NewReader(data)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to decompress session data: %w", err)
+		}
+		defer zr.Close()
+		data = zr
 	}
 
 	// Deserialize using gob
 	values := url.Values{}
-	decoder := gob.NewDecoder(bytes.NewReader(plaintext))
+	decoder := gob.NewDecoder(data)
 	if err = decoder.Decode(&values); err != nil {
-		return nil, fmt.Errorf("failed to decode session data: %w", err)
+		return nil, 0, fmt.Errorf("failed to decode session data: %w", err)
 	}
 
-	return values, nil
+	return values, version, nil
 }
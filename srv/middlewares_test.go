@@ -3,10 +3,15 @@ package srv
 import (
 	"bytes"
 	"crypto/rand"
+	"encoding/base64"
+	"encoding/gob"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
+	"net/http/cookiejar"
 	"net/http/httptest"
 	"net/url"
 	"strconv"
@@ -14,6 +19,9 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/c3p0-box/utils/erm"
+	"golang.org/x/text/language"
 )
 
 // Test helper to capture slog output
@@ -60,6 +68,7 @@ func TestLoggingMiddleware(t *testing.T) {
 		"path=/test",
 		"user-agent=test-agent/1.0",
 		"remote-addr=192.168.1.1:12345",
+		"client-ip=192.168.1.1",
 		"duration=",
 		"request completed",
 	}
@@ -71,6 +80,59 @@ func TestLoggingMiddleware(t *testing.T) {
 	}
 }
 
+func TestRequestIDMiddleware(t *testing.T) {
+	mux := NewMux()
+	mux.Middleware(RequestIDMiddleware)
+	mux.Middleware(LoggingMiddleware)
+	mux.Get("", "/users/{id}", func(ctx Context) error {
+		ctx.Logger().Info("handler log")
+		return ctx.String(http.StatusOK, ctx.Get("request-id").(string))
+	})
+
+	tests := []struct {
+		name     string
+		incoming string
+		reused   bool
+	}{
+		{"reuses incoming ID", "abc-123", true},
+		{"generates missing ID", "", false},
+		{"replaces ID with spaces", "abc 123", false},
+		{"replaces overlong ID", strings.Repeat("a", 129), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/users/1", nil)
+			if tt.incoming != "" {
+				req.Header.Set(HeaderXRequestID, tt.incoming)
+			}
+			rec := httptest.NewRecorder()
+
+			logOutput := captureLogs(t, func() {
+				mux.ServeHTTP(rec, req)
+			})
+
+			id := rec.Header().Get(HeaderXRequestID)
+			if tt.reused && id != tt.incoming {
+				t.Errorf("Expected request ID %q, got %q", tt.incoming, id)
+			}
+			if !tt.reused && (len(id) != 32 || id == tt.incoming) {
+				t.Errorf("Expected generated 32-char request ID, got %q", id)
+			}
+			if rec.Body.String() != id {
+				t.Errorf("Expected context value %q, got %q", id, rec.Body.String())
+			}
+			// Both the handler log and the request log carry the request fields
+			if n := strings.Count(logOutput, "request-id="+id); n != 2 {
+				t.Errorf("Expected 2 log lines with request-id, got %d. Log output: %s", n, logOutput)
+			}
+			if n := strings.Count(logOutput, "route=/users/{id}"); n != 2 {
+				t.Errorf("Expected 2 log lines with route, got %d. Log output: %s", n, logOutput)
+			}
+		})
+	}
+}
+
 func TestRecoverMiddleware(t *testing.T) {
 	mux := NewMux()
 
@@ -111,11 +173,24 @@ func TestRecoverMiddleware(t *testing.T) {
 		}
 	}
 
+	// Check that the stack trace points at the panicking handler
+	if !strings.Contains(logOutput, "stack=") || !strings.Contains(logOutput, "TestRecoverMiddleware") {
+		t.Errorf("Expected log output to contain the panic stack trace. Log output: %s", logOutput)
+	}
+
 	// Check that error was captured by error handler
 	if capturedError == nil {
 		t.Error("Expected panic to be converted to error")
 	} else if !strings.Contains(capturedError.Error(), "panic") {
 		t.Errorf("Expected error to contain 'panic', got '%s'", capturedError.Error())
+	} else if len(erm.Stack(capturedError)) == 0 {
+		t.Error("Expected error to carry a stack trace")
+	}
+	if erm.Status(capturedError) != http.StatusInternalServerError {
+		t.Errorf("Expected erm status 500, got %d", erm.Status(capturedError))
+	}
+	if erm.Message(capturedError) != "panic recovered" {
+		t.Errorf("Expected erm message 'panic recovered', got '%s'", erm.Message(capturedError))
 	}
 
 	if rec.Code != 500 {
@@ -568,6 +643,591 @@ func TestAddTrailingSlashMiddleware_DefaultConfig(t *testing.T) {
 	})
 }
 
+func TestMiddlewareSkipper(t *testing.T) {
+	skipAPI := func(ctx Context) bool { return strings.HasPrefix(ctx.Path(), "/api/") }
+
+	slashConfig := DefaultTrailingSlashConfig
+	slashConfig.RedirectCode = http.StatusMovedPermanently
+	slashConfig.Skipper = skipAPI
+
+	corsConfig := DefaultCORSConfig
+	corsConfig.Skipper = skipAPI
+
+	jwtConfig := JWTConfig{
+		KeyFunc: func(alg, kid string) (interface{}, error) { return []byte("secret"), nil },
+		Skipper: skipAPI,
+	}
+
+	tests := []struct {
+		name       string
+		middleware HandlerFuncMiddleware
+		path       string
+		check      func(t *testing.T, rec *httptest.ResponseRecorder)
+	}{
+		{"trailing slash skipped", AddTrailingSlashMiddleware(slashConfig), "/api/users", func(t *testing.T, rec *httptest.ResponseRecorder) {
+			if rec.Code != http.StatusOK {
+				t.Errorf("Expected status 200, got %d", rec.Code)
+			}
+		}},
+		{"trailing slash applied", AddTrailingSlashMiddleware(slashConfig), "/users", func(t *testing.T, rec *httptest.ResponseRecorder) {
+			if rec.Code != http.StatusMovedPermanently {
+				t.Errorf("Expected status 301, got %d", rec.Code)
+			}
+		}},
+		{"cors skipped", CORSMiddleware(corsConfig), "/api/users", func(t *testing.T, rec *httptest.ResponseRecorder) {
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+				t.Errorf("Expected no CORS header, got %q", got)
+			}
+		}},
+		{"cors applied", CORSMiddleware(corsConfig), "/users", func(t *testing.T, rec *httptest.ResponseRecorder) {
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got == "" {
+				t.Error("Expected CORS header")
+			}
+		}},
+		{"jwt skipped", JWTMiddleware(jwtConfig), "/api/users", func(t *testing.T, rec *httptest.ResponseRecorder) {
+			if rec.Code != http.StatusOK {
+				t.Errorf("Expected status 200, got %d", rec.Code)
+			}
+		}},
+		{"jwt applied", JWTMiddleware(jwtConfig), "/users", func(t *testing.T, rec *httptest.ResponseRecorder) {
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("Expected status 401, got %d", rec.Code)
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := NewMux()
+			mux.ErrorHandler(func(ctx Context, err error) {
+				_ = ctx.String(erm.Status(err), err.Error())
+			})
+			mux.Middleware(tt.middleware)
+			handler := func(ctx Context) error { return ctx.String(http.StatusOK, "ok") }
+			mux.Get("", "/users", handler)
+			mux.Get("", "/api/users", handler)
+
+			req := httptest.NewRequest("GET", tt.path, nil)
+			req.Header.Set("Origin", "https://example.com")
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+			tt.check(t, rec)
+		})
+	}
+}
+
+func TestRealIPMiddleware(t *testing.T) {
+	mux := NewMux()
+	mux.Middleware(RealIPMiddleware([]string{"10.0.0.0/8"}))
+	mux.Get("", "/ip", func(ctx Context) error {
+		return ctx.String(http.StatusOK, ctx.Request().RemoteAddr)
+	})
+
+	tests := []struct {
+		name          string
+		remoteAddr    string
+		xForwardedFor string
+		xRealIP       string
+		expected      string
+	}{
+		{"untrusted peer unchanged", "203.0.113.7:4321", "1.2.3.4", "", "203.0.113.7:4321"},
+		{"trusted peer with forwarded for", "10.0.0.1:80", "1.2.3.4, 198.51.100.2", "", "198.51.100.2"},
+		{"trusted peer with real ip", "10.0.0.1:80", "", "198.51.100.9", "198.51.100.9"},
+		{"trusted peer without headers", "10.0.0.1:80", "", "", "10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/ip", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.xForwardedFor != "" {
+				req.Header.Set(HeaderXForwardedFor, tt.xForwardedFor)
+			}
+			if tt.xRealIP != "" {
+				req.Header.Set(HeaderXRealIP, tt.xRealIP)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Body.String() != tt.expected {
+				t.Errorf("Expected RemoteAddr %q, got %q", tt.expected, rec.Body.String())
+			}
+		})
+	}
+
+	t.Run("panics on invalid proxy", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected panic for invalid trusted proxy")
+			}
+		}()
+		RealIPMiddleware([]string{"invalid"})
+	})
+}
+
+func TestBodyLoggingMiddleware(t *testing.T) {
+	config := DefaultBodyLoggingConfig
+	config.Level = slog.LevelInfo
+	config.MaxBodySize = 128
+
+	mux := NewMux()
+	mux.Middleware(BodyLoggingMiddleware(config))
+	mux.Post("", "/echo", func(ctx Context) error {
+		body, err := io.ReadAll(ctx.Request().Body)
+		if err != nil {
+			return err
+		}
+		ctx.SetHeader(HeaderContentType, ctx.GetHeader(HeaderContentType))
+		ctx.WriteHeader(http.StatusCreated)
+		_, err = ctx.Response().Write(body)
+		return err
+	})
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:        "redacts json keys at any depth",
+			contentType: MIMEApplicationJSON,
+			body:        `{"user":"bob","password":"hunter2","nested":{"Token":"abc"},"items":[{"secret":"s"}]}`,
+			contains:    []string{"bob", "[REDACTED]", "status=201"},
+			notContains: []string{"hunter2", `"abc"`, `"s"`},
+		},
+		{
+			name:        "redacts form fields",
+			contentType: MIMEApplicationForm,
+			body:        "user=bob&password=hunter2",
+			contains:    []string{"user=bob", "password=%5BREDACTED%5D"},
+			notContains: []string{"hunter2"},
+		},
+		{
+			name:        "large json is not logged",
+			contentType: MIMEApplicationJSON,
+			body:        `{"password":"hunter2","padding":"` + strings.Repeat("x", 200) + `"}`,
+			contains:    []string{"too large to redact"},
+			notContains: []string{"hunter2"},
+		},
+		{
+			name:        "plain text is truncated",
+			contentType: MIMETextPlain,
+			body:        strings.Repeat("a", 200),
+			contains:    []string{strings.Repeat("a", 128) + "...[truncated]"},
+			notContains: []string{strings.Repeat("a", 129)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/echo", strings.NewReader(tt.body))
+			req.Header.Set(HeaderContentType, tt.contentType)
+			rec := httptest.NewRecorder()
+
+			logOutput := captureLogs(t, func() {
+				mux.ServeHTTP(rec, req)
+			})
+
+			if rec.Body.String() != tt.body {
+				t.Errorf("Expected handler to read full body, echoed %q", rec.Body.String())
+			}
+			if !strings.Contains(logOutput, "srv.BodyLogging") {
+				t.Errorf("Expected body log entry, got: %s", logOutput)
+			}
+			for _, part := range tt.contains {
+				if !strings.Contains(logOutput, part) {
+					t.Errorf("Expected log output to contain %q. Log output: %s", part, logOutput)
+				}
+			}
+			for _, part := range tt.notContains {
+				if strings.Contains(logOutput, part) {
+					t.Errorf("Expected log output not to contain %q. Log output: %s", part, logOutput)
+				}
+			}
+		})
+	}
+
+	t.Run("default level is debug", func(t *testing.T) {
+		quiet := NewMux()
+		quiet.Middleware(BodyLoggingMiddleware(DefaultBodyLoggingConfig))
+		quiet.Post("", "/echo", func(ctx Context) error { return nil })
+
+		logOutput := captureLogs(t, func() {
+			quiet.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/echo", strings.NewReader("x")))
+		})
+		if strings.Contains(logOutput, "srv.BodyLogging") {
+			t.Errorf("Expected debug entry to be filtered at info level, got: %s", logOutput)
+		}
+	})
+}
+
+func TestResponseTimeMiddleware(t *testing.T) {
+	mux := NewMux()
+	mux.Middleware(ResponseTimeMiddleware)
+
+	mux.Get("", "/slow", func(ctx Context) error {
+		time.Sleep(5 * time.Millisecond)
+		return ctx.String(http.StatusOK, "done")
+	})
+	mux.Get("", "/empty", func(ctx Context) error {
+		return nil
+	})
+	mux.Get("", "/error", func(ctx Context) error {
+		return errors.New("boom")
+	})
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		minElapsed time.Duration
+	}{
+		{name: "set before body is written", path: "/slow", wantStatus: http.StatusOK, minElapsed: 5 * time.Millisecond},
+		{name: "handler writes nothing", path: "/empty", wantStatus: http.StatusOK},
+		{name: "error response", path: "/error", wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			// Result reports the headers as they were when the status was written.
+			result := rec.Result()
+			if result.StatusCode != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, result.StatusCode)
+			}
+			value := result.Header.Get(HeaderXResponseTime)
+			if !strings.HasSuffix(value, "ms") {
+				t.Fatalf("Expected X-Response-Time in milliseconds, got %q", value)
+			}
+			ms, err := strconv.ParseFloat(strings.TrimSuffix(value, "ms"), 64)
+			if err != nil {
+				t.Fatalf("Invalid X-Response-Time %q: %v", value, err)
+			}
+			if elapsed := time.Duration(ms * float64(time.Millisecond)); elapsed < tt.minElapsed {
+				t.Errorf("Expected at least %v, got %v", tt.minElapsed, elapsed)
+			}
+		})
+	}
+}
+
+func TestLocaleMiddleware(t *testing.T) {
+	ptBR := language.MustParse("pt-BR")
+	matcher := language.NewMatcher([]language.Tag{language.English, language.French, ptBR})
+
+	mux := NewMux()
+	mux.Middleware(LocaleMiddleware(matcher))
+	mux.Get("", "/", func(ctx Context) error {
+		locale, ok := ContextValue[language.Tag](ctx, "locale")
+		if !ok {
+			return errors.New("locale not set")
+		}
+		return ctx.String(http.StatusOK, locale.String())
+	})
+
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{"exact match", "fr", "fr"},
+		{"regional variant", "fr-BE, fr;q=0.9", "fr"},
+		{"quality order", "de;q=0.5, pt-BR;q=0.8", "pt-BR"},
+		{"close match without extensions", "en-GB", "en"},
+		{"no header uses default", "", "en"},
+		{"unsupported uses default", "zh", "en"},
+		{"invalid header uses default", "fr;q=abc", "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				req.Header.Set(HeaderAcceptLanguage, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+			if rec.Body.String() != tt.want {
+				t.Errorf("Expected locale %q, got %q", tt.want, rec.Body.String())
+			}
+			if rec.Header().Get(HeaderVary) != HeaderAcceptLanguage {
+				t.Errorf("Expected Vary: Accept-Language, got %q", rec.Header().Get(HeaderVary))
+			}
+		})
+	}
+}
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	validator := func(key string) (interface{}, error) {
+		if key == "secret-key" {
+			return "billing-service", nil
+		}
+		return nil, errors.New("unknown API key")
+	}
+
+	newMux := func(lookup string, capturedError *error) *Mux {
+		mux := NewMux()
+		mux.ErrorHandler(func(ctx Context, err error) {
+			*capturedError = err
+			_ = ctx.String(erm.Status(err), err.Error())
+		})
+		mux.Middleware(APIKeyMiddleware(lookup, validator))
+		mux.Get("", "/internal", func(ctx Context) error {
+			return ctx.String(http.StatusOK, ctx.Get("principal").(string))
+		})
+		return mux
+	}
+
+	tests := []struct {
+		name           string
+		lookup         string
+		header         string
+		query          string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"valid header key", "X-API-Key", "secret-key", "", http.StatusOK, "billing-service"},
+		{"missing header key", "X-API-Key", "", "", http.StatusUnauthorized, "missing API key"},
+		{"invalid header key", "X-API-Key", "wrong", "", http.StatusUnauthorized, "unknown API key"},
+		{"valid query key", "query:api_key", "", "secret-key", http.StatusOK, "billing-service"},
+		{"query lookup ignores header", "query:api_key", "secret-key", "", http.StatusUnauthorized, "missing API key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var capturedError error
+			mux := newMux(tt.lookup, &capturedError)
+
+			target := "/internal"
+			if tt.query != "" {
+				target += "?api_key=" + url.QueryEscape(tt.query)
+			}
+			req := httptest.NewRequest("GET", target, nil)
+			if tt.header != "" {
+				req.Header.Set("X-API-Key", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if rec.Body.String() != tt.expectedBody {
+				t.Errorf("Expected body %q, got %q", tt.expectedBody, rec.Body.String())
+			}
+			if tt.expectedStatus == http.StatusUnauthorized && capturedError == nil {
+				t.Error("Expected error to be captured")
+			}
+		})
+	}
+}
+
+func TestContentNegotiationMiddleware(t *testing.T) {
+	newMux := func(middleware HandlerFuncMiddleware) *Mux {
+		mux := NewMux()
+		mux.ErrorHandler(func(ctx Context, err error) {
+			_ = ctx.String(erm.Status(err), err.Error())
+		})
+		mux.Middleware(middleware)
+		ok := func(ctx Context) error {
+			return ctx.String(http.StatusOK, "ok")
+		}
+		mux.Get("", "/items", ok)
+		mux.Post("", "/items", ok)
+		mux.Delete("", "/items", ok)
+		return mux
+	}
+	contentTypeMux := newMux(RequireContentTypeMiddleware(MIMEApplicationJSON, "text/*"))
+	acceptMux := newMux(RequireAcceptMiddleware(MIMEApplicationJSON))
+
+	tests := []struct {
+		name           string
+		mux            *Mux
+		method         string
+		body           string
+		header         string
+		value          string
+		expectedStatus int
+	}{
+		{"json body", contentTypeMux, "POST", `{}`, HeaderContentType, "application/json; charset=utf-8", http.StatusOK},
+		{"content type is case-insensitive", contentTypeMux, "POST", `{}`, HeaderContentType, "Application/JSON", http.StatusOK},
+		{"wildcard content type", contentTypeMux, "POST", "hi", HeaderContentType, "text/plain", http.StatusOK},
+		{"form body", contentTypeMux, "POST", "a=1", HeaderContentType, MIMEApplicationForm, http.StatusUnsupportedMediaType},
+		{"missing content type on write", contentTypeMux, "POST", "", "", "", http.StatusUnsupportedMediaType},
+		{"bodyless GET", contentTypeMux, "GET", "", "", "", http.StatusOK},
+		{"bodyless DELETE", contentTypeMux, "DELETE", "", "", "", http.StatusOK},
+		{"DELETE with form body", contentTypeMux, "DELETE", "a=1", HeaderContentType, MIMEApplicationForm, http.StatusUnsupportedMediaType},
+		{"no accept header", acceptMux, "GET", "", "", "", http.StatusOK},
+		{"accept json", acceptMux, "GET", "", HeaderAccept, "application/json", http.StatusOK},
+		{"accept any", acceptMux, "GET", "", HeaderAccept, "text/html, */*;q=0.1", http.StatusOK},
+		{"accept family", acceptMux, "GET", "", HeaderAccept, "application/*", http.StatusOK},
+		{"accept html only", acceptMux, "GET", "", HeaderAccept, "text/html", http.StatusNotAcceptable},
+		{"json excluded by q=0", acceptMux, "GET", "", HeaderAccept, "application/json;q=0, text/html", http.StatusNotAcceptable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/items", strings.NewReader(tt.body))
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			rec := httptest.NewRecorder()
+			tt.mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d (%s)", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	var captured error
+	mux := newMux(RequireAcceptMiddleware(MIMEApplicationJSON))
+	mux.ErrorHandler(func(ctx Context, err error) { captured = err })
+	req := httptest.NewRequest("GET", "/items", nil)
+	req.Header.Set(HeaderAccept, "text/html")
+	mux.ServeHTTP(httptest.NewRecorder(), req)
+	if !errors.Is(captured, ErrNotAcceptable) {
+		t.Errorf("Expected error wrapping ErrNotAcceptable, got %v", captured)
+	}
+}
+
+func TestETagMiddleware(t *testing.T) {
+	mux := NewMux()
+	mux.Middleware(ETagMiddleware)
+
+	mux.Get("", "/data", func(ctx Context) error {
+		return ctx.String(http.StatusOK, "payload")
+	})
+	mux.Post("", "/data", func(ctx Context) error {
+		return ctx.String(http.StatusOK, "payload")
+	})
+	mux.Get("", "/custom", func(ctx Context) error {
+		ctx.SetHeader(HeaderETag, `"v1"`)
+		return ctx.String(http.StatusOK, "custom")
+	})
+	mux.Get("", "/missing", func(ctx Context) error {
+		return ctx.String(http.StatusNotFound, "not found")
+	})
+	mux.Get("", "/stream", func(ctx Context) error {
+		ctx.WriteHeader(http.StatusOK)
+		_, _ = ctx.Response().Write([]byte("chunk1"))
+		if err := http.NewResponseController(ctx.Response()).Flush(); err != nil {
+			return err
+		}
+		_, err := ctx.Response().Write([]byte("chunk2"))
+		return err
+	})
+
+	get := func(path, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set(HeaderIfNoneMatch, ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := get("/data", "")
+	etag := first.Header().Get(HeaderETag)
+
+	t.Run("sets etag on successful GET", func(t *testing.T) {
+		if first.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", first.Code)
+		}
+		if etag == "" || !strings.HasPrefix(etag, `"`) || !strings.HasSuffix(etag, `"`) {
+			t.Errorf("Expected quoted ETag header, got %q", etag)
+		}
+		if first.Body.String() != "payload" {
+			t.Errorf("Expected body 'payload', got %q", first.Body.String())
+		}
+		if again := get("/data", "").Header().Get(HeaderETag); again != etag {
+			t.Errorf("Expected stable ETag %q, got %q", etag, again)
+		}
+	})
+
+	t.Run("conditional requests", func(t *testing.T) {
+		tests := []struct {
+			name           string
+			ifNoneMatch    string
+			expectedStatus int
+		}{
+			{"exact match", etag, http.StatusNotModified},
+			{"weak match", "W/" + etag, http.StatusNotModified},
+			{"match in list", `"other", ` + etag, http.StatusNotModified},
+			{"wildcard", "*", http.StatusNotModified},
+			{"no match", `"other"`, http.StatusOK},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				rec := get("/data", tt.ifNoneMatch)
+				if rec.Code != tt.expectedStatus {
+					t.Errorf("Expected status %d, got %d", tt.expectedStatus, rec.Code)
+				}
+				if tt.expectedStatus == http.StatusNotModified && rec.Body.Len() != 0 {
+					t.Errorf("Expected empty body for 304, got %q", rec.Body.String())
+				}
+				if rec.Header().Get(HeaderETag) != etag {
+					t.Errorf("Expected ETag %q, got %q", etag, rec.Header().Get(HeaderETag))
+				}
+			})
+		}
+	})
+
+	t.Run("keeps handler etag", func(t *testing.T) {
+		rec := get("/custom", `"v1"`)
+		if rec.Code != http.StatusNotModified {
+			t.Errorf("Expected status 304, got %d", rec.Code)
+		}
+		if rec.Header().Get(HeaderETag) != `"v1"` {
+			t.Errorf("Expected ETag '\"v1\"', got %q", rec.Header().Get(HeaderETag))
+		}
+	})
+
+	t.Run("skips non-GET requests", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/data", nil)
+		req.Header.Set(HeaderIfNoneMatch, "*")
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK || rec.Header().Get(HeaderETag) != "" {
+			t.Errorf("Expected untouched 200 response, got %d with ETag %q", rec.Code, rec.Header().Get(HeaderETag))
+		}
+	})
+
+	t.Run("skips non-200 responses", func(t *testing.T) {
+		rec := get("/missing", "*")
+		if rec.Code != http.StatusNotFound || rec.Header().Get(HeaderETag) != "" {
+			t.Errorf("Expected untouched 404 response, got %d with ETag %q", rec.Code, rec.Header().Get(HeaderETag))
+		}
+		if rec.Body.String() != "not found" {
+			t.Errorf("Expected body 'not found', got %q", rec.Body.String())
+		}
+	})
+
+	t.Run("skips streaming responses", func(t *testing.T) {
+		rec := get("/stream", "*")
+		if rec.Code != http.StatusOK || rec.Header().Get(HeaderETag) != "" {
+			t.Errorf("Expected untouched 200 response, got %d with ETag %q", rec.Code, rec.Header().Get(HeaderETag))
+		}
+		if !rec.Flushed {
+			t.Error("Expected response to be flushed")
+		}
+		if rec.Body.String() != "chunk1chunk2" {
+			t.Errorf("Expected body 'chunk1chunk2', got %q", rec.Body.String())
+		}
+	})
+}
+
 func TestHandlerFuncMiddleware_Integration(t *testing.T) {
 	mux := NewMux()
 
@@ -639,6 +1299,119 @@ func TestNewOptions(t *testing.T) {
 	}
 }
 
+func TestOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		sameSite http.SameSite
+		secure   bool
+		wantErr  error
+	}{
+		{"strict insecure", http.SameSiteStrictMode, false, nil},
+		{"none secure", http.SameSiteNoneMode, true, nil},
+		{"none insecure", http.SameSiteNoneMode, false, ErrSameSiteNoneInsecure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := NewOptions()
+			options.SameSite = tt.sameSite
+			options.Secure = tt.secure
+
+			if err := options.Validate(); err != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	t.Run("stores refuse invalid options on save", func(t *testing.T) {
+		options := NewOptions()
+		options.SameSite = http.SameSiteNoneMode
+		options.Secure = false
+
+		memStore := NewInMemoryStore("test-session", NewOptions())
+		defer memStore.Close()
+		cookieStore, err := NewCookieStore("test-session", make([]byte, 32), NewOptions())
+		if err != nil {
+			t.Fatalf("Failed to create cookie store: %v", err)
+		}
+
+		for name, store := range map[string]Store{"in-memory": memStore, "cookie": cookieStore} {
+			req := httptest.NewRequest("GET", "/", nil)
+			session, err := store.New(req, "test-session")
+			if err != nil {
+				t.Fatalf("%s: failed to create session: %v", name, err)
+			}
+			session.Options = options
+			session.Set("user", "bob")
+
+			rec := httptest.NewRecorder()
+			if err := session.Save(req, rec); err != ErrSameSiteNoneInsecure {
+				t.Errorf("%s: expected ErrSameSiteNoneInsecure, got %v", name, err)
+			}
+			if len(rec.Result().Cookies()) != 0 {
+				t.Errorf("%s: expected no cookie to be set", name)
+			}
+		}
+	})
+}
+
+func TestOptions_ValidateName(t *testing.T) {
+	tests := []struct {
+		name       string
+		cookieName string
+		modify     func(o *Options)
+		wantErr    error
+	}{
+		{"no prefix", "session", func(o *Options) { o.Secure = false }, nil},
+		{"secure prefix ok", "__Secure-session", nil, nil},
+		{"secure prefix insecure", "__Secure-session", func(o *Options) { o.Secure = false }, ErrCookiePrefix},
+		{"host prefix ok", "__Host-session", nil, nil},
+		{"host prefix case-insensitive", "__host-session", func(o *Options) { o.Secure = false }, ErrCookiePrefix},
+		{"host prefix insecure", "__Host-session", func(o *Options) { o.Secure = false }, ErrCookiePrefix},
+		{"host prefix with domain", "__Host-session", func(o *Options) { o.Domain = "example.com" }, ErrCookiePrefix},
+		{"host prefix with path", "__Host-session", func(o *Options) { o.Path = "/api" }, ErrCookiePrefix},
+		{"also runs validate", "session", func(o *Options) {
+			o.Secure = false
+			o.SameSite = http.SameSiteNoneMode
+		}, ErrSameSiteNoneInsecure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := NewOptions()
+			if tt.modify != nil {
+				tt.modify(options)
+			}
+
+			err := options.ValidateName(tt.cookieName)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	t.Run("cookie store rejects misconfiguration up front", func(t *testing.T) {
+		options := NewOptions()
+		options.Domain = "example.com"
+		if _, err := NewCookieStore("__Host-session", make([]byte, 32), options); !errors.Is(err, ErrCookiePrefix) {
+			t.Errorf("Expected ErrCookiePrefix, got %v", err)
+		}
+	})
+
+	t.Run("in-memory store rejects misconfiguration on save", func(t *testing.T) {
+		options := NewOptions()
+		options.Path = "/api"
+		store := NewInMemoryStore("__Host-session", options)
+		defer store.Close()
+
+		req := httptest.NewRequest("GET", "/", nil)
+		session, _ := store.New(req, "__Host-session")
+		if err := session.Save(req, httptest.NewRecorder()); !errors.Is(err, ErrCookiePrefix) {
+			t.Errorf("Expected ErrCookiePrefix, got %v", err)
+		}
+	})
+}
+
 func TestSession_BasicOperations(t *testing.T) {
 	session := &Session{
 		ID:     "test-session-id",
@@ -665,19 +1438,79 @@ func TestSession_BasicOperations(t *testing.T) {
 		t.Errorf("Expected nonexistent key to return nil, got %v", session.Get("nonexistent"))
 	}
 
-	// Test Delete
-	session.Delete("username")
-	if session.Get("username") != "" {
-		t.Errorf("Expected deleted key to return nil, got %v", session.Get("username"))
+	// Test Delete
+	session.Delete("username")
+	if session.Get("username") != "" {
+		t.Errorf("Expected deleted key to return nil, got %v", session.Get("username"))
+	}
+
+	// Test Clear
+	session.Clear()
+	if session.Get("userID") != "" {
+		t.Error("Expected all keys to be cleared")
+	}
+	if session.Get("isAdmin") != "" {
+		t.Error("Expected all keys to be cleared")
+	}
+}
+
+func TestSession_Modified(t *testing.T) {
+	store := NewInMemoryStore("test-session", &Options{Path: "/", HttpOnly: true})
+	defer store.Close()
+	session, _ := store.New(nil, "test-session")
+
+	if session.Modified() {
+		t.Error("Expected new session to be unmodified")
+	}
+	session.Get("userID")
+	if session.Modified() {
+		t.Error("Expected Get not to modify the session")
+	}
+
+	mutations := map[string]func(){
+		"Set":          func() { session.Set("userID", "1") },
+		"Delete":       func() { session.Delete("userID") },
+		"Clear":        func() { session.Clear() },
+		"MarkModified": func() { session.MarkModified() },
+	}
+	for name, mutate := range mutations {
+		mutate()
+		if !session.Modified() {
+			t.Errorf("Expected %s to mark the session as modified", name)
+		}
+		if err := session.Save(nil, httptest.NewRecorder()); err != nil {
+			t.Fatalf("Expected no error saving session, got: %v", err)
+		}
+		if session.Modified() {
+			t.Errorf("Expected Save after %s to reset Modified", name)
+		}
 	}
+}
 
-	// Test Clear
-	session.Clear()
-	if session.Get("userID") != "" {
-		t.Error("Expected all keys to be cleared")
+func TestSession_TypedGetters(t *testing.T) {
+	session := &Session{}
+	session.Set("int", "42")
+	session.Set("big", "9007199254740993")
+	session.Set("bool", "true")
+	session.Set("text", "hello")
+
+	if v, ok := session.GetInt("int"); !ok || v != 42 {
+		t.Errorf("GetInt: expected 42, true; got %v, %v", v, ok)
 	}
-	if session.Get("isAdmin") != "" {
-		t.Error("Expected all keys to be cleared")
+	if v, ok := session.GetInt64("big"); !ok || v != 9007199254740993 {
+		t.Errorf("GetInt64: expected 9007199254740993, true; got %v, %v", v, ok)
+	}
+	if v, ok := session.GetBool("bool"); !ok || !v {
+		t.Errorf("GetBool: expected true, true; got %v, %v", v, ok)
+	}
+	if v, ok := session.GetInt("text"); ok || v != 0 {
+		t.Errorf("GetInt: expected 0, false for non-numeric value; got %v, %v", v, ok)
+	}
+	if v, ok := session.GetBool("missing"); ok || v {
+		t.Errorf("GetBool: expected false, false for missing key; got %v, %v", v, ok)
+	}
+	if _, ok := (&Session{}).GetInt64("missing"); ok {
+		t.Error("GetInt64: expected false on empty session")
 	}
 }
 
@@ -704,6 +1537,71 @@ func TestSession_NilValues(t *testing.T) {
 	session2.Clear()       // Should not panic
 }
 
+func TestSession_Destroy(t *testing.T) {
+	memStore := NewInMemoryStore("test-session", NewOptions())
+	defer memStore.Close()
+	cookieStore, err := NewCookieStore("test-session", make([]byte, 32), NewOptions())
+	if err != nil {
+		t.Fatalf("Failed to create cookie store: %v", err)
+	}
+
+	for name, store := range map[string]Store{"in-memory": memStore, "cookie": cookieStore} {
+		t.Run(name, func(t *testing.T) {
+			mux := NewMux()
+			mux.Middleware(SessionMiddleware(store, "test-session"))
+			mux.Post("", "/login", func(ctx Context) error {
+				ctx.Get("session").(*Session).Set("user", "bob")
+				return ctx.String(http.StatusOK, "logged in")
+			})
+			mux.Post("", "/logout", func(ctx Context) error {
+				session := ctx.Get("session").(*Session)
+				if err := session.Destroy(ctx.Request(), ctx.Response()); err != nil {
+					return err
+				}
+				return ctx.String(http.StatusOK, "logged out")
+			})
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest("POST", "/login", nil))
+			// SessionMiddleware saves after the handler has written the
+			// response, so read the cookie from the live header map.
+			loginCookies := (&http.Response{Header: rec.Header()}).Cookies()
+			if len(loginCookies) != 1 {
+				t.Fatalf("Expected session cookie after login, got %d cookies", len(loginCookies))
+			}
+			sessionCookie := loginCookies[0]
+
+			req := httptest.NewRequest("POST", "/logout", nil)
+			req.AddCookie(sessionCookie)
+			rec = httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			cookies := rec.Result().Cookies()
+			if len(cookies) != 1 {
+				t.Fatalf("Expected exactly one (expiring) cookie, got %d", len(cookies))
+			}
+			if cookies[0].MaxAge >= 0 || cookies[0].Value != "" {
+				t.Errorf("Expected expired cookie, got MaxAge=%d Value=%q", cookies[0].MaxAge, cookies[0].Value)
+			}
+
+			if name == "in-memory" {
+				req = httptest.NewRequest("GET", "/", nil)
+				req.AddCookie(sessionCookie)
+				if _, err := store.Get(req, "test-session"); err == nil {
+					t.Error("Expected server-side session to be removed")
+				}
+			}
+		})
+	}
+
+	t.Run("without store", func(t *testing.T) {
+		session := &Session{}
+		if err := session.Destroy(httptest.NewRequest("GET", "/", nil), httptest.NewRecorder()); err == nil {
+			t.Error("Expected error when no store is configured")
+		}
+	})
+}
+
 func TestGenerateSessionID(t *testing.T) {
 	id1, err := generateSessionID()
 	if err != nil {
@@ -938,6 +1836,163 @@ func TestInMemoryStore_SessionExpiration(t *testing.T) {
 	}
 }
 
+func TestInMemoryStore_MaxSessions(t *testing.T) {
+	store := NewInMemoryStore("test-session", NewOptions())
+	store.MaxSessions = 2
+	defer store.Close()
+
+	save := func(value string) *http.Cookie {
+		req := httptest.NewRequest("GET", "/", nil)
+		session, err := store.New(req, "test-session")
+		if err != nil {
+			t.Fatalf("Failed to create session: %v", err)
+		}
+		session.Set("data", value)
+		rec := httptest.NewRecorder()
+		if err := store.Save(req, rec, session); err != nil {
+			t.Fatalf("Failed to save session: %v", err)
+		}
+		return rec.Result().Cookies()[0]
+	}
+	get := func(cookie *http.Cookie) error {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.AddCookie(cookie)
+		_, err := store.Get(req, "test-session")
+		return err
+	}
+
+	first := save("first")
+	second := save("second")
+
+	// Touch the first session so the second becomes least recently used
+	if err := get(first); err != nil {
+		t.Fatalf("Expected first session to exist, got: %v", err)
+	}
+
+	third := save("third")
+
+	if err := get(second); err != http.ErrNoCookie {
+		t.Errorf("Expected least recently used session to be evicted, got: %v", err)
+	}
+	if err := get(first); err != nil {
+		t.Errorf("Expected recently used session to be kept, got: %v", err)
+	}
+	if err := get(third); err != nil {
+		t.Errorf("Expected newest session to be kept, got: %v", err)
+	}
+	if len(store.sessions) != 2 || store.lru.Len() != 2 {
+		t.Errorf("Expected 2 stored sessions, got %d (lru %d)", len(store.sessions), store.lru.Len())
+	}
+}
+
+func TestInMemoryStore_RotateOnSave(t *testing.T) {
+	store := NewInMemoryStore("test-session", NewOptions())
+	store.RotateOnSave = true
+	defer store.Close()
+
+	req := httptest.NewRequest("GET", "/", nil)
+	session, err := store.New(req, "test-session")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	session.Set("user", "admin")
+	rec := httptest.NewRecorder()
+	if err := store.Save(req, rec, session); err != nil {
+		t.Fatalf("Failed to save session: %v", err)
+	}
+	firstCookie := rec.Result().Cookies()[0]
+	if firstCookie.Value != session.ID {
+		t.Error("Expected new session to keep its ID on first save")
+	}
+
+	req = httptest.NewRequest("GET", "/", nil)
+	req.AddCookie(firstCookie)
+	loaded, err := store.Get(req, "test-session")
+	if err != nil {
+		t.Fatalf("Failed to load session: %v", err)
+	}
+	rec = httptest.NewRecorder()
+	if err := store.Save(req, rec, loaded); err != nil {
+		t.Fatalf("Failed to save session: %v", err)
+	}
+	secondCookie := rec.Result().Cookies()[0]
+
+	if secondCookie.Value == firstCookie.Value || loaded.ID != secondCookie.Value {
+		t.Errorf("Expected rotated ID in cookie, got %q (old %q, session %q)", secondCookie.Value, firstCookie.Value, loaded.ID)
+	}
+
+	req = httptest.NewRequest("GET", "/", nil)
+	req.AddCookie(firstCookie)
+	if _, err := store.Get(req, "test-session"); err == nil {
+		t.Error("Expected old session ID to be invalidated")
+	}
+
+	req = httptest.NewRequest("GET", "/", nil)
+	req.AddCookie(secondCookie)
+	rotated, err := store.Get(req, "test-session")
+	if err != nil {
+		t.Fatalf("Expected rotated session to exist, got: %v", err)
+	}
+	if rotated.Get("user") != "admin" {
+		t.Errorf("Expected data to be migrated, got %q", rotated.Get("user"))
+	}
+}
+
+func TestSessionMiddleware_RotateOnSave(t *testing.T) {
+	for _, rotate := range []bool{false, true} {
+		t.Run(fmt.Sprintf("rotate=%v", rotate), func(t *testing.T) {
+			options := NewOptions()
+			options.Secure = false // plain-HTTP test server
+			store := NewInMemoryStore("sid", options)
+			store.RotateOnSave = rotate
+			defer store.Close()
+
+			mux := NewMux()
+			mux.Middleware(SessionMiddleware(store, "sid"))
+			mux.Post("", "/login", func(ctx Context) error {
+				session := ctx.Get("session").(*Session)
+				session.Set("user", "bob")
+				// Explicit save in the handler, then the middleware saves again
+				if err := session.Save(ctx.Request(), ctx.Response()); err != nil {
+					return err
+				}
+				return ctx.String(http.StatusOK, "ok")
+			})
+			mux.Get("", "/me", func(ctx Context) error {
+				session := ctx.Get("session").(*Session)
+				return ctx.String(http.StatusOK, session.Get("user"))
+			})
+
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			jar, err := cookiejar.New(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			client := &http.Client{Jar: jar}
+
+			resp, err := client.Post(server.URL+"/login", "text/plain", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			for i := 0; i < 3; i++ {
+				resp, err := client.Get(server.URL + "/me")
+				if err != nil {
+					t.Fatal(err)
+				}
+				body, _ := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				if string(body) != "bob" {
+					t.Fatalf("request %d: expected user bob, got %q", i+1, body)
+				}
+			}
+		})
+	}
+}
+
 func TestInMemoryStore_ConcurrentAccess(t *testing.T) {
 	store := NewInMemoryStore("test-session", NewOptions())
 	defer store.Close()
@@ -995,6 +2050,132 @@ func TestInMemoryStore_ConcurrentAccess(t *testing.T) {
 	wg.Wait()
 }
 
+func TestInMemoryStore_CheckVersion(t *testing.T) {
+	newSession := func(store *InMemoryStore) *http.Request {
+		session, _ := store.New(nil, "test-session")
+		session.Set("counter", "0")
+		rec := httptest.NewRecorder()
+		if err := store.Save(nil, rec, session); err != nil {
+			t.Fatalf("Expected no error saving session, got: %v", err)
+		}
+		req := httptest.NewRequest("GET", "/", nil)
+		req.AddCookie(rec.Result().Cookies()[0])
+		return req
+	}
+
+	t.Run("concurrent write is detected", func(t *testing.T) {
+		store := NewInMemoryStore("test-session", NewOptions())
+		defer store.Close()
+		store.CheckVersion = true
+		req := newSession(store)
+
+		first, _ := store.Get(req, "test-session")
+		second, _ := store.Get(req, "test-session")
+		first.Set("a", "1")
+		second.Set("b", "2")
+
+		if err := store.Save(req, httptest.NewRecorder(), first); err != nil {
+			t.Fatalf("Expected first save to succeed, got: %v", err)
+		}
+		if second.Get("a") != "" {
+			t.Error("Expected sessions loaded separately not to share values")
+		}
+		if err := store.Save(req, httptest.NewRecorder(), second); !errors.Is(err, ErrSessionConflict) {
+			t.Fatalf("Expected ErrSessionConflict, got: %v", err)
+		}
+
+		// Retry on a fresh copy
+		retry, _ := store.Get(req, "test-session")
+		retry.Set("b", "2")
+		if err := store.Save(req, httptest.NewRecorder(), retry); err != nil {
+			t.Fatalf("Expected retried save to succeed, got: %v", err)
+		}
+		// Saving the same session again is not a conflict
+		retry.Set("c", "3")
+		if err := store.Save(req, httptest.NewRecorder(), retry); err != nil {
+			t.Fatalf("Expected repeated save to succeed, got: %v", err)
+		}
+
+		final, _ := store.Get(req, "test-session")
+		if final.Get("a") != "1" || final.Get("b") != "2" || final.Get("c") != "3" {
+			t.Errorf("Expected both writes to be kept, got %v", final.Values)
+		}
+	})
+
+	t.Run("deleted session is not resurrected", func(t *testing.T) {
+		store := NewInMemoryStore("test-session", NewOptions())
+		defer store.Close()
+		store.CheckVersion = true
+		req := newSession(store)
+
+		stale, _ := store.Get(req, "test-session")
+		current, _ := store.Get(req, "test-session")
+		if err := current.Destroy(req, httptest.NewRecorder()); err != nil {
+			t.Fatal(err)
+		}
+		stale.Set("a", "1")
+		if err := store.Save(req, httptest.NewRecorder(), stale); !errors.Is(err, ErrSessionConflict) {
+			t.Errorf("Expected ErrSessionConflict, got: %v", err)
+		}
+	})
+
+	t.Run("last write wins by default", func(t *testing.T) {
+		store := NewInMemoryStore("test-session", NewOptions())
+		defer store.Close()
+		req := newSession(store)
+
+		first, _ := store.Get(req, "test-session")
+		second, _ := store.Get(req, "test-session")
+		first.Set("a", "1")
+		second.Set("b", "2")
+		if err := store.Save(req, httptest.NewRecorder(), first); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Save(req, httptest.NewRecorder(), second); err != nil {
+			t.Errorf("Expected no conflict without CheckVersion, got: %v", err)
+		}
+	})
+
+	t.Run("concurrent increments with retry", func(t *testing.T) {
+		store := NewInMemoryStore("test-session", NewOptions())
+		defer store.Close()
+		store.CheckVersion = true
+		req := newSession(store)
+
+		const numGoroutines = 20
+		var wg sync.WaitGroup
+		for i := 0; i < numGoroutines; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					session, err := store.Get(req, "test-session")
+					if err != nil {
+						t.Errorf("Error retrieving session: %v", err)
+						return
+					}
+					counter, _ := session.GetInt("counter")
+					session.Set("counter", strconv.Itoa(counter+1))
+					err = store.Save(req, httptest.NewRecorder(), session)
+					if err == nil {
+						return
+					}
+					if !errors.Is(err, ErrSessionConflict) {
+						t.Errorf("Error saving session: %v", err)
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		session, _ := store.Get(req, "test-session")
+		if counter, _ := session.GetInt("counter"); counter != numGoroutines {
+			t.Errorf("Expected counter %d, got %d", numGoroutines, counter)
+		}
+	})
+}
+
 // =============================================================================
 // Session Middleware Tests
 // =============================================================================
@@ -1063,6 +2244,164 @@ func TestSessionMiddleware_NewSession(t *testing.T) {
 	}
 }
 
+func TestSessionMiddleware_SaveHooks(t *testing.T) {
+	tests := []struct {
+		name         string
+		signIn       bool
+		expectCookie bool
+	}{
+		{"anonymous request is not saved", false, false},
+		{"signed-in request is saved", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewInMemoryStore("app-session", &Options{Path: "/", MaxAge: 3600, HttpOnly: true})
+			defer store.Close()
+
+			var saved []string
+			mux := NewMux()
+			mux.Middleware(SessionMiddlewareWithConfig(store, "app-session", SessionConfig{
+				BeforeSave: func(ctx Context, session *Session) bool {
+					if session.Get("user") == "" {
+						return false
+					}
+					session.Set("lastSeen", "now")
+					return true
+				},
+				AfterSave: func(ctx Context, session *Session, err error) {
+					if err != nil {
+						t.Errorf("Expected no save error, got: %v", err)
+					}
+					saved = append(saved, session.Get("lastSeen"))
+				},
+			}))
+			mux.Get("", "/page", func(ctx Context) error {
+				if tt.signIn {
+					ctx.Get("session").(*Session).Set("user", "alice")
+				}
+				return ctx.String(http.StatusOK, "ok")
+			})
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest("GET", "/page", nil))
+
+			cookies := rec.Result().Cookies()
+			if got := len(cookies) > 0; got != tt.expectCookie {
+				t.Fatalf("Expected cookie %v, got %v", tt.expectCookie, got)
+			}
+			if !tt.expectCookie {
+				if len(saved) != 0 {
+					t.Errorf("Expected AfterSave not to run, got %d calls", len(saved))
+				}
+				return
+			}
+			if len(saved) == 0 || saved[0] != "now" {
+				t.Errorf("Expected AfterSave to see the value set in BeforeSave, got %v", saved)
+			}
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.AddCookie(cookies[0])
+			session, err := store.Get(req, "app-session")
+			if err != nil {
+				t.Fatalf("Expected stored session, got: %v", err)
+			}
+			if session.Get("lastSeen") != "now" {
+				t.Errorf("Expected lastSeen to be persisted, got %q", session.Get("lastSeen"))
+			}
+		})
+	}
+}
+
+func TestSessionMiddleware_Skipper(t *testing.T) {
+	store := NewInMemoryStore("app-session", &Options{Path: "/", MaxAge: 3600, HttpOnly: true})
+	defer store.Close()
+
+	mux := NewMux()
+	mux.Middleware(SessionMiddlewareWithConfig(store, "app-session", SessionConfig{
+		Skipper: func(ctx Context) bool {
+			return strings.HasPrefix(ctx.Request().URL.Path, "/static/")
+		},
+	}))
+	mux.Get("", "/{path...}", func(ctx Context) error {
+		_, ok := ctx.Get("session").(*Session)
+		return ctx.String(http.StatusOK, fmt.Sprint(ok))
+	})
+
+	tests := []struct {
+		path          string
+		expectSession bool
+	}{
+		{"/static/app.css", false},
+		{"/page", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest("GET", tt.path, nil))
+
+			if got := rec.Body.String(); got != fmt.Sprint(tt.expectSession) {
+				t.Errorf("Expected session in context %v, got %s", tt.expectSession, got)
+			}
+			if got := len(rec.Result().Cookies()) > 0; got != tt.expectSession {
+				t.Errorf("Expected cookie %v, got %v", tt.expectSession, got)
+			}
+		})
+	}
+}
+
+func TestSessionMiddleware_SavesOnlyModified(t *testing.T) {
+	store := NewInMemoryStore("app-session", &Options{Path: "/", MaxAge: 3600, HttpOnly: true})
+	defer store.Close()
+
+	mux := NewMux()
+	mux.Middleware(SessionMiddleware(store, "app-session"))
+	mux.Get("", "/{action}", func(ctx Context) error {
+		session := ctx.Get("session").(*Session)
+		switch ctx.Request().PathValue("action") {
+		case "login":
+			session.Set("user", "alice")
+		case "logout":
+			session.Delete("user")
+		case "direct":
+			session.Values["theme"] = []string{"dark"}
+			session.MarkModified()
+		}
+		return ctx.String(http.StatusOK, session.Get("user"))
+	})
+
+	login := httptest.NewRecorder()
+	mux.ServeHTTP(login, httptest.NewRequest("GET", "/login", nil))
+	cookies := login.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("Expected new session cookie, got %d cookies", len(cookies))
+	}
+
+	tests := []struct {
+		action       string
+		expectCookie bool
+	}{
+		{"read", false},
+		{"login", true},
+		{"direct", true},
+		{"logout", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/"+tt.action, nil)
+			req.AddCookie(cookies[0])
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if got := rec.Header().Get("Set-Cookie") != ""; got != tt.expectCookie {
+				t.Errorf("Expected Set-Cookie %v, got %v", tt.expectCookie, got)
+			}
+		})
+	}
+}
+
 func TestSessionMiddleware_ExistingSession(t *testing.T) {
 	store := NewInMemoryStore("app-session", &Options{
 		Path:     "/",
@@ -1393,6 +2732,10 @@ func (e *mockErrorStore) Save(_ *http.Request, _ http.ResponseWriter, _ *Session
 	return fmt.Errorf("simulated Save error")
 }
 
+func (e *mockErrorStore) Delete(_ *http.Request, _ http.ResponseWriter, _ *Session) error {
+	return fmt.Errorf("simulated Delete error")
+}
+
 // =============================================================================
 // CookieStore Tests
 // =============================================================================
@@ -1676,6 +3019,198 @@ func TestCookieStore_SizeLimit(t *testing.T) {
 	}
 }
 
+func TestCookieStore_MaxCookieSize(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxSize     int
+		valueLength int
+		expectError bool
+	}{
+		{"default allows small session", 0, 100, false},
+		{"default rejects 5KB", 0, 5000, true},
+		{"raised limit allows 5KB", 8000, 5000, false},
+		{"lowered limit rejects small session", 100, 100, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := NewOptions()
+			options.MaxCookieSize = tt.maxSize
+			store, err := NewCookieStore("test-session", make([]byte, 32), options)
+			if err != nil {
+				t.Fatalf("Expected no error creating store, got: %v", err)
+			}
+
+			session, _ := store.New(nil, "test-session")
+			session.Set("data", strings.Repeat("x", tt.valueLength))
+			rec := httptest.NewRecorder()
+			err = store.Save(nil, rec, session)
+
+			if tt.expectError {
+				if err == nil || !strings.Contains(err.Error(), "too large") {
+					t.Errorf("Expected 'too large' error, got: %v", err)
+				}
+				if len(rec.Result().Cookies()) != 0 {
+					t.Error("Expected no cookie to be set")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if len(rec.Result().Cookies()) != 1 {
+				t.Error("Expected session cookie to be set")
+			}
+		})
+	}
+}
+
+func TestCookieStore_Compress(t *testing.T) {
+	key := make([]byte, 32)
+	store, err := NewCookieStore("test-session", key, NewOptions())
+	if err != nil {
+		t.Fatalf("Expected no error creating store, got: %v", err)
+	}
+
+	save := func(values url.Values) (*http.Cookie, error) {
+		session, _ := store.New(httptest.NewRequest("GET", "/", nil), "test-session")
+		session.Values = values
+		rec := httptest.NewRecorder()
+		if err := store.Save(nil, rec, session); err != nil {
+			return nil, err
+		}
+		return rec.Result().Cookies()[0], nil
+	}
+	get := func(cookie *http.Cookie) (*Session, error) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.AddCookie(cookie)
+		return store.Get(req, "test-session")
+	}
+
+	// Written without compression, readable once compression is enabled
+	plain, err := save(url.Values{"user": {"alice"}})
+	if err != nil {
+		t.Fatalf("Expected no error saving session, got: %v", err)
+	}
+
+	store.Compress = true
+
+	// Repetitive data over the limit fits once compressed
+	large := strings.Repeat("x", 5000)
+	compressed, err := save(url.Values{"large": {large}})
+	if err != nil {
+		t.Fatalf("Expected compressed session to fit, got: %v", err)
+	}
+	if len(compressed.Value) > 4000 {
+		t.Errorf("Expected cookie value under 4000 bytes, got %d", len(compressed.Value))
+	}
+	session, err := get(compressed)
+	if err != nil {
+		t.Fatalf("Expected no error reading compressed session, got: %v", err)
+	}
+	if session.Get("large") != large {
+		t.Error("Expected compressed value to round-trip")
+	}
+
+	session, err = get(plain)
+	if err != nil {
+		t.Fatalf("Expected no error reading uncompressed session, got: %v", err)
+	}
+	if session.Get("user") != "alice" {
+		t.Errorf("Expected user 'alice', got %q", session.Get("user"))
+	}
+
+	// The limit still applies to the final cookie value
+	random := make([]byte, 5000)
+	_, _ = rand.Read(random)
+	if _, err := save(url.Values{"random": {base64.StdEncoding.EncodeToString(random)}}); err == nil || !strings.Contains(err.Error(), "too large") {
+		t.Errorf("Expected 'too large' error for incompressible data, got: %v", err)
+	}
+}
+
+func TestCookieStore_Migrate(t *testing.T) {
+	store, err := NewCookieStore("test-session", make([]byte, 32), NewOptions())
+	if err != nil {
+		t.Fatalf("Expected no error creating store, got: %v", err)
+	}
+
+	cookieFor := func(encrypted []byte) *http.Cookie {
+		return &http.Cookie{Name: "test-session", Value: base64.URLEncoding.EncodeToString(encrypted)}
+	}
+	save := func(version uint8, values url.Values) *http.Cookie {
+		store.Version = version
+		encrypted, err := store.encryptSessionData(values)
+		if err != nil {
+			t.Fatalf("Expected no error encrypting session, got: %v", err)
+		}
+		return cookieFor(encrypted)
+	}
+
+	// Session data written before versioning: gob without a version header
+	var legacy bytes.Buffer
+	if err := gob.NewEncoder(&legacy).Encode(url.Values{"uid": {"7"}}); err != nil {
+		t.Fatal(err)
+	}
+	nonce := make([]byte, store.cipher.NonceSize())
+	legacyCookie := cookieFor(store.cipher.Seal(nonce, nonce, legacy.Bytes(), nil))
+
+	tests := []struct {
+		name            string
+		cookie          *http.Cookie
+		migrateErr      error
+		expectedVersion int // -1 if Migrate must not be called
+		expectedUserID  string
+		expectError     bool
+	}{
+		{"current version is not migrated", save(2, url.Values{"user_id": {"1"}}), nil, -1, "1", false},
+		{"older version is migrated", save(1, url.Values{"uid": {"42"}}), nil, 1, "42", false},
+		{"unversioned data is version 0", legacyCookie, nil, 0, "7", false},
+		{"failed migration discards session", save(1, url.Values{"uid": {"42"}}), errors.New("unsupported"), 1, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migratedVersion := -1
+			store.Version = 2
+			store.Migrate = func(version uint8, values url.Values) (url.Values, error) {
+				migratedVersion = int(version)
+				if tt.migrateErr != nil {
+					return nil, tt.migrateErr
+				}
+				values.Set("user_id", values.Get("uid"))
+				values.Del("uid")
+				return values, nil
+			}
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.AddCookie(tt.cookie)
+			session, err := store.Get(req, "test-session")
+
+			if migratedVersion != tt.expectedVersion {
+				t.Errorf("Expected Migrate to see version %d, got %d", tt.expectedVersion, migratedVersion)
+			}
+			if tt.expectError {
+				if !errors.Is(err, http.ErrNoCookie) {
+					t.Errorf("Expected http.ErrNoCookie, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if session.Get("user_id") != tt.expectedUserID {
+				t.Errorf("Expected user_id %q, got %q", tt.expectedUserID, session.Get("user_id"))
+			}
+			if session.Get("uid") != "" {
+				t.Error("Expected uid to be migrated away")
+			}
+			if migrated := migratedVersion != -1; session.Modified() != migrated {
+				t.Errorf("Expected Modified %v so the cookie is rewritten, got %v", migrated, session.Modified())
+			}
+		})
+	}
+}
+
 func TestCookieStore_EncryptionSecurity(t *testing.T) {
 	key1 := make([]byte, 32)
 	key2 := make([]byte, 32)
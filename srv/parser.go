@@ -1,13 +1,19 @@
 package srv
 
 import (
+	"bufio"
 	"encoding"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"log/slog"
+	"maps"
 	"mime"
+	"mime/multipart"
 	"net/http"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -34,6 +40,7 @@ import (
 // - Proper error handling with erm.Error types
 // - Resource leak prevention with automatic cleanup
 // - Combined parsing: both request body and query parameters in single call
+// - Body values win over query values unless ParseConfig.QueryOverridesBody is set
 //
 // Example usage:
 //
@@ -65,15 +72,148 @@ import (
 //	// Use req.Name, req.Email, req.Age (from body)
 //	// Use req.UserID (parsed via UnmarshalText), req.Page, req.Sort, req.FilterBy (from query parameters)
 func ParseRequest(r *http.Request, target interface{}) erm.Error {
+	return ParseRequestWithConfig(r, target, DefaultParseConfig)
+}
+
+// ParseConfig defines the configuration for ParseRequestWithConfig.
+type ParseConfig struct {
+	// RequireBody rejects requests without a body with an erm 400 error
+	// (message key erm.MsgErrorBodyRequired) instead of leaving the target
+	// untouched. Use it for endpoints such as POST and PUT that cannot work
+	// without a payload, so that clients get a clear error rather than
+	// validation errors for every field.
+	//
+	// Optional. Default value false (an empty body is not an error).
+	RequireBody bool
+
+	// MaxFileSize is the maximum size in bytes of each file uploaded in a
+	// multipart/form-data body. A larger file is rejected with an erm 413
+	// error (message key erm.MsgErrorFileTooLarge, field set to the form
+	// field name).
+	//
+	// The limit is checked once the form is parsed, so it bounds what
+	// handlers accept, not what is read; cap the request body with
+	// http.MaxBytesReader to bound memory and disk use as well.
+	//
+	// Optional. Default value 0 (no limit).
+	MaxFileSize int64
+
+	// MaxFiles is the maximum number of files uploaded in a
+	// multipart/form-data body, across all fields. More files are rejected
+	// with an erm 413 error (message key erm.MsgErrorTooManyFiles).
+	//
+	// Optional. Default value 0 (no limit).
+	MaxFiles int
+
+	// Lenient skips form and query fields whose value cannot be converted
+	// to the field type instead of failing the request. Skipped fields are
+	// left at their zero value, the remaining fields are still bound, and
+	// the returned erm 400 error lists each skipped field with message key
+	// erm.MsgInvalid, so callers can log or ignore it. Malformed bodies
+	// (e.g. invalid JSON) still fail the request.
+	//
+	// Optional. Default value false (the first conversion error fails the
+	// request).
+	Lenient bool
+
+	// MaxBodyBytes is the maximum size of the request body. A request whose
+	// Content-Length exceeds it is rejected before the body is read, and a
+	// body of unknown length is cut off at the limit; both yield an erm 413
+	// error (message key erm.MsgErrorBodyTooLarge).
+	//
+	// Optional. Default value 0 (no limit).
+	MaxBodyBytes int64
+
+	// UseNumber decodes JSON numbers stored in interface{} values, e.g. of
+	// a map[string]interface{} target or field, as json.Number instead of
+	// float64, which cannot represent integers above 2^53 exactly (such as
+	// 64-bit snowflake IDs). Numbers bound to typed struct fields are
+	// unaffected.
+	//
+	// Optional. Default value false (float64).
+	UseNumber bool
+
+	// QueryOverridesBody makes query parameters win over body values for
+	// fields set by both, e.g. to let the query string override defaults
+	// sent in the body. By default the body is parsed after the query and
+	// wins. Only values present in a source are bound, so a field missing
+	// from the winning source keeps the value of the other one.
+	//
+	// Optional. Default value false (body values win).
+	QueryOverridesBody bool
+}
+
+// DefaultParseConfig is the default ParseRequest config.
+var DefaultParseConfig = ParseConfig{
+	RequireBody:        false,
+	MaxFileSize:        0,
+	MaxFiles:           0,
+	Lenient:            false,
+	MaxBodyBytes:       0,
+	UseNumber:          false,
+	QueryOverridesBody: false,
+}
+
+// ParseRequestWithConfig is ParseRequest with the given config.
+//
+// Example:
+//
+//	var req CreateUserRequest
+//	if err := srv.ParseRequestWithConfig(r, &req, srv.ParseConfig{RequireBody: true}); err != nil {
+//		return err // 400 "request body is required" for an empty body
+//	}
+func ParseRequestWithConfig(r *http.Request, target interface{}, config ParseConfig) erm.Error {
 	if r == nil {
 		return erm.NewValidationError(erm.MsgErrorInvalidRequest, erm.NonFieldErrors, "", "")
 	}
 
-	// Parse query parameters first (always available regardless of Content-Type)
-	if err := parseQueryParams(r, target); err != nil {
+	if err := limitBody(r, config.MaxBodyBytes); err != nil {
 		return err
 	}
+	if config.RequireBody && bodyMissing(r) {
+		return erm.NewValidationError(erm.MsgErrorBodyRequired, erm.NonFieldErrors, "", "")
+	}
+
+	// Query parameters are always available regardless of Content-Type.
+	// The source parsed last wins for fields set by both.
+	parsers := []func() erm.Error{
+		func() erm.Error { return parseQueryParams(r, target, config.Lenient) },
+		func() erm.Error { return parseBody(r, target, config) },
+	}
+	if config.QueryOverridesBody {
+		slices.Reverse(parsers)
+	}
+
+	var skipped erm.Error
+	for _, parse := range parsers {
+		err := parse()
+		switch {
+		case err == nil:
+		case !config.Lenient || !err.HasErrors():
+			// A conversion error in strict mode, or a malformed body
+			return err
+		case skipped == nil:
+			skipped = err
+		default:
+			// Report the fields skipped from the query and the body together.
+			// Form values include the query, so a field may have been
+			// skipped twice.
+			reported := make(map[string]bool)
+			for _, fieldErr := range skipped.AllErrors() {
+				reported[fieldErr.FieldName()] = true
+			}
+			for _, fieldErr := range err.AllErrors() {
+				if !reported[fieldErr.FieldName()] {
+					skipped.AddError(fieldErr)
+				}
+			}
+		}
+	}
+	return skipped
+}
 
+// parseBody parses the request body according to its Content-Type.
+func parseBody(r *http.Request, target interface{}, config ParseConfig) erm.Error {
 	// Determine content type for body parsing
 	contentType := r.Header.Get(HeaderContentType)
 	mediaType, _, err := mime.ParseMediaType(contentType)
@@ -85,21 +225,170 @@ func ParseRequest(r *http.Request, target interface{}) erm.Error {
 	// Route to appropriate parser based on content type for body parsing
 	switch mediaType {
 	case "application/json":
-		return parseJSONRequest(r, target)
+		return parseJSONRequest(r, target, config.UseNumber)
 	case "application/x-www-form-urlencoded":
-		return parseFormRequest(r, target)
+		return parseFormRequest(r, target, config.Lenient)
 	case "multipart/form-data":
-		return parseMultipartFormRequest(r, target)
+		return parseMultipartFormRequest(r, target, config)
 	case "":
 		// No Content-Type specified, try to detect or default to JSON
-		return parseRequestWithDetection(r, target)
+		return parseRequestWithDetection(r, target, config.UseNumber)
 	default:
 		return erm.NewValidationError(erm.MsgErrorInvalidRequest, erm.NonFieldErrors, "", "")
 	}
 }
 
+// ParseRequestStream decodes a JSON request body incrementally: fn receives
+// a json.Decoder reading the body and consumes it as it goes, e.g. one
+// element of a large array at a time, so that bulk payloads are never held in
+// memory at once. Query parameters are not parsed; use ctx.QueryParam.
+//
+// fn is not called if the body is empty. Errors are converted to erm errors:
+// a body exceeding MaxBodyBytes yields a 413, malformed JSON a 400 invalid
+// request error, erm errors returned by fn are passed through and other
+// errors become a 500. Requests with a Content-Type other than JSON are
+// rejected with a 400.
+//
+// Example:
+//
+//	err := srv.ParseRequestStream(ctx.Request(), func(decoder *json.Decoder) error {
+//		if _, err := decoder.Token(); err != nil { // [
+//			return err
+//		}
+//		for decoder.More() {
+//			var item Item
+//			if err := decoder.Decode(&item); err != nil {
+//				return err
+//			}
+//			if err := store(item); err != nil {
+//				return err
+//			}
+//		}
+//		_, err := decoder.Token() // ]
+//		return err
+//	})
+func ParseRequestStream(r *http.Request, fn func(decoder *json.Decoder) error) erm.Error {
+	return ParseRequestStreamWithConfig(r, DefaultParseConfig, fn)
+}
+
+// ParseRequestStreamWithConfig is ParseRequestStream with the given config.
+// RequireBody, MaxBodyBytes and UseNumber apply; the other options only
+// concern binding into a struct and are ignored.
+//
+// Example:
+//
+//	config := srv.ParseConfig{RequireBody: true, MaxBodyBytes: 100 << 20}
+//	err := srv.ParseRequestStreamWithConfig(ctx.Request(), config, importItems)
+func ParseRequestStreamWithConfig(r *http.Request, config ParseConfig, fn func(decoder *json.Decoder) error) erm.Error {
+	if r == nil {
+		return erm.NewValidationError(erm.MsgErrorInvalidRequest, erm.NonFieldErrors, "", "")
+	}
+
+	if contentType := r.Header.Get(HeaderContentType); contentType != "" {
+		if mediaType, _, err := mime.ParseMediaType(contentType); err != nil || mediaType != MIMEApplicationJSON {
+			return erm.NewValidationError(erm.MsgErrorInvalidRequest, erm.NonFieldErrors, "", "")
+		}
+	}
+	if err := limitBody(r, config.MaxBodyBytes); err != nil {
+		return err
+	}
+	if bodyMissing(r) {
+		if config.RequireBody {
+			return erm.NewValidationError(erm.MsgErrorBodyRequired, erm.NonFieldErrors, "", "")
+		}
+		return nil
+	}
+	defer func() { _ = r.Body.Close() }()
+
+	decoder := json.NewDecoder(r.Body)
+	if config.UseNumber {
+		decoder.UseNumber()
+	}
+	err := fn(decoder)
+	if err == nil {
+		return nil
+	}
+	// Erm errors from fn, possibly wrapped, are returned as is
+	var ermErr erm.Error
+	if errors.As(err, &ermErr) {
+		return ermErr
+	}
+
+	var maxErr *http.MaxBytesError
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &maxErr):
+		return bodyTooLargeError(maxErr.Limit)
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr),
+		errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		slog.With(
+			slog.String("name", "req.ParseRequestStream"),
+			slog.Any("error", err),
+		).Debug("failed to parse JSON request body")
+
+		return erm.NewValidationError(erm.MsgErrorInvalidRequest, erm.NonFieldErrors, "", "")
+	}
+	return erm.Wrap(err)
+}
+
+// limitBody rejects r with an erm 413 error if its Content-Length exceeds
+// maxBytes and otherwise caps the body at maxBytes. A zero maxBytes means
+// no limit.
+func limitBody(r *http.Request, maxBytes int64) erm.Error {
+	if maxBytes <= 0 {
+		return nil
+	}
+	if r.ContentLength > maxBytes {
+		return bodyTooLargeError(maxBytes)
+	}
+	if r.Body != nil && r.Body != http.NoBody {
+		r.Body = http.MaxBytesReader(nil, r.Body, maxBytes)
+	}
+	return nil
+}
+
+// bodyTooLargeError returns the erm 413 error for a body over maxBytes.
+func bodyTooLargeError(maxBytes int64) erm.Error {
+	return erm.New(http.StatusRequestEntityTooLarge, "", nil).
+		WithMessageKey(erm.MsgErrorBodyTooLarge).
+		WithFieldName(erm.NonFieldErrors).
+		WithParam("max", maxBytes)
+}
+
+// bodyReadError converts an error reading or decoding the body into an erm
+// error: 413 if the body exceeded MaxBodyBytes, 400 invalid request otherwise.
+func bodyReadError(err error) erm.Error {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		return bodyTooLargeError(maxErr.Limit)
+	}
+	return erm.NewValidationError(erm.MsgErrorInvalidRequest, erm.NonFieldErrors, "", "")
+}
+
+// bodyMissing reports whether r has no body. For bodies of unknown length
+// (chunked encoding) it peeks at the first byte, keeping it readable.
+func bodyMissing(r *http.Request) bool {
+	if r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0 {
+		return true
+	}
+	if r.ContentLength > 0 {
+		return false
+	}
+
+	reader := bufio.NewReader(r.Body)
+	if _, err := reader.Peek(1); err != nil {
+		return true
+	}
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{reader, r.Body}
+	return false
+}
+
 // parseJSONRequest handles JSON payload parsing
-func parseJSONRequest(r *http.Request, target interface{}) erm.Error {
+func parseJSONRequest(r *http.Request, target interface{}, useNumber bool) erm.Error {
 	if r.Body == nil {
 		return erm.NewValidationError(erm.MsgErrorInvalidRequest, erm.NonFieldErrors, "", "")
 	}
@@ -123,6 +412,9 @@ func parseJSONRequest(r *http.Request, target interface{}) erm.Error {
 	// Create a JSON decoder that reads directly from the request body
 	// This streams the JSON input efficiently like json/v2.UnmarshalRead would
 	decoder := json.NewDecoder(r.Body)
+	if useNumber {
+		decoder.UseNumber()
+	}
 
 	// Decode the JSON body into the target structure
 	if err := decoder.Decode(target); err != nil {
@@ -131,33 +423,64 @@ func parseJSONRequest(r *http.Request, target interface{}) erm.Error {
 			slog.Any("error", err),
 		).Debug("failed to parse JSON request body")
 
-		return erm.NewValidationError(erm.MsgErrorInvalidRequest, erm.NonFieldErrors, "", "")
+		return bodyReadError(err)
 	}
 
 	return nil
 }
 
 // parseFormRequest handles application/x-www-form-urlencoded parsing
-func parseFormRequest(r *http.Request, target interface{}) erm.Error {
+func parseFormRequest(r *http.Request, target interface{}, lenient bool) erm.Error {
 	if err := r.ParseForm(); err != nil {
-		return erm.NewValidationError(erm.MsgErrorInvalidRequest, erm.NonFieldErrors, "", "")
+		return bodyReadError(err)
 	}
 
-	return mapFormToStruct(r.Form, target)
+	return mapFormToStruct(r.Form, target, lenient)
 }
 
 // parseMultipartFormRequest handles multipart/form-data parsing
-func parseMultipartFormRequest(r *http.Request, target interface{}) erm.Error {
+func parseMultipartFormRequest(r *http.Request, target interface{}, config ParseConfig) erm.Error {
 	// Set max memory for multipart parsing (32MB)
 	if err := r.ParseMultipartForm(32 << 20); err != nil {
-		return erm.NewValidationError(erm.MsgErrorInvalidRequest, erm.NonFieldErrors, "", "")
+		return bodyReadError(err)
+	}
+
+	if err := checkFileLimits(r.MultipartForm, config); err != nil {
+		// Remove temporary files of the rejected upload right away
+		_ = r.MultipartForm.RemoveAll()
+		return err
 	}
 
-	return mapFormToStruct(r.MultipartForm.Value, target)
+	return mapFormToStruct(r.MultipartForm.Value, target, config.Lenient)
+}
+
+// checkFileLimits enforces the MaxFiles and MaxFileSize limits of config on
+// the files of form.
+func checkFileLimits(form *multipart.Form, config ParseConfig) erm.Error {
+	count := 0
+	for _, field := range slices.Sorted(maps.Keys(form.File)) {
+		for _, file := range form.File[field] {
+			count++
+			if config.MaxFiles > 0 && count > config.MaxFiles {
+				return erm.New(http.StatusRequestEntityTooLarge, "", nil).
+					WithMessageKey(erm.MsgErrorTooManyFiles).
+					WithFieldName(erm.NonFieldErrors).
+					WithParam("max", config.MaxFiles)
+			}
+			if config.MaxFileSize > 0 && file.Size > config.MaxFileSize {
+				return erm.New(http.StatusRequestEntityTooLarge, "", nil).
+					WithMessageKey(erm.MsgErrorFileTooLarge).
+					WithFieldName(field).
+					WithValue(file.Filename).
+					WithParam("max", config.MaxFileSize)
+			}
+		}
+	}
+	return nil
 }
 
 // parseRequestWithDetection attempts to detect content type when not specified
-func parseRequestWithDetection(r *http.Request, target interface{}) erm.Error {
+func parseRequestWithDetection(r *http.Request, target interface{}, useNumber bool) erm.Error {
 	if r.Body == nil {
 		return nil // No body to parse - this is fine for GET requests
 	}
@@ -169,11 +492,11 @@ func parseRequestWithDetection(r *http.Request, target interface{}) erm.Error {
 
 	// For requests without Content-Type, default to JSON parsing
 	// This maintains backward compatibility with existing code
-	return parseJSONRequest(r, target)
+	return parseJSONRequest(r, target, useNumber)
 }
 
 // parseQueryParams parses URL query parameters and maps them to struct fields with `query` tags
-func parseQueryParams(r *http.Request, target interface{}) erm.Error {
+func parseQueryParams(r *http.Request, target interface{}, lenient bool) erm.Error {
 	if r.URL == nil {
 		return nil // No query parameters to parse
 	}
@@ -183,11 +506,12 @@ func parseQueryParams(r *http.Request, target interface{}) erm.Error {
 		return nil // No query parameters to parse
 	}
 
-	return mapQueryToStruct(queryValues, target)
+	return mapQueryToStruct(queryValues, target, lenient)
 }
 
-// mapQueryToStruct maps query parameters to struct fields using reflection and `query` struct tags
-func mapQueryToStruct(values map[string][]string, target interface{}) erm.Error {
+// mapQueryToStruct maps query parameters to struct fields using reflection and `query` struct tags.
+// In lenient mode, fields that fail conversion are reset and reported together (see skippedFieldsError).
+func mapQueryToStruct(values map[string][]string, target interface{}, lenient bool) erm.Error {
 	rv := reflect.ValueOf(target)
 	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
 		return erm.NewValidationError(erm.MsgErrorInvalidRequest, erm.NonFieldErrors, "", "")
@@ -195,6 +519,7 @@ func mapQueryToStruct(values map[string][]string, target interface{}) erm.Error
 
 	rv = rv.Elem()
 	rt := rv.Type()
+	var skipped []erm.Error
 
 	for i := 0; i < rv.NumField(); i++ {
 		field := rv.Field(i)
@@ -216,6 +541,26 @@ func mapQueryToStruct(values map[string][]string, target interface{}) erm.Error
 			continue
 		}
 
+		// Bind bracketed keys such as meta[color]=red into map fields
+		if field.Kind() == reflect.Map {
+			if err := setMapFieldValue(field, queryTag, values); err != nil {
+				slog.With(
+					slog.String("name", "req.mapQueryToStruct"),
+					slog.String("field", fieldType.Name),
+					slog.String("queryTag", queryTag),
+					slog.Any("error", err),
+				).Debug("failed to set map field value from query parameters")
+
+				if lenient {
+					field.Set(reflect.Zero(field.Type()))
+					skipped = append(skipped, erm.NewValidationError(erm.MsgInvalid, queryTag, ""))
+					continue
+				}
+				return erm.NewValidationError(erm.MsgErrorInvalidRequest, erm.NonFieldErrors, "", "")
+			}
+			continue
+		}
+
 		// Get query values for this field
 		queryVals, exists := values[queryTag]
 		if !exists || len(queryVals) == 0 {
@@ -231,15 +576,21 @@ func mapQueryToStruct(values map[string][]string, target interface{}) erm.Error
 				slog.Any("error", err),
 			).Debug("failed to set field value from query parameter")
 
+			if lenient {
+				field.Set(reflect.Zero(field.Type()))
+				skipped = append(skipped, erm.NewValidationError(erm.MsgInvalid, queryTag, queryVals[0]))
+				continue
+			}
 			return erm.NewValidationError(erm.MsgErrorInvalidRequest, erm.NonFieldErrors, "", "")
 		}
 	}
 
-	return nil
+	return skippedFieldsError(skipped)
 }
 
-// mapFormToStruct maps form values to struct fields using reflection and struct tags
-func mapFormToStruct(values map[string][]string, target interface{}) erm.Error {
+// mapFormToStruct maps form values to struct fields using reflection and struct tags.
+// In lenient mode, fields that fail conversion are reset and reported together (see skippedFieldsError).
+func mapFormToStruct(values map[string][]string, target interface{}, lenient bool) erm.Error {
 	rv := reflect.ValueOf(target)
 	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
 		return erm.NewValidationError(erm.MsgErrorInvalidRequest, erm.NonFieldErrors, "", "")
@@ -247,6 +598,7 @@ func mapFormToStruct(values map[string][]string, target interface{}) erm.Error {
 
 	rv = rv.Elem()
 	rt := rv.Type()
+	var skipped []erm.Error
 
 	for i := 0; i < rv.NumField(); i++ {
 		field := rv.Field(i)
@@ -268,6 +620,26 @@ func mapFormToStruct(values map[string][]string, target interface{}) erm.Error {
 			continue
 		}
 
+		// Bind bracketed keys such as meta[color]=red into map fields
+		if field.Kind() == reflect.Map {
+			if err := setMapFieldValue(field, formTag, values); err != nil {
+				slog.With(
+					slog.String("name", "req.mapFormToStruct"),
+					slog.String("field", fieldType.Name),
+					slog.String("formTag", formTag),
+					slog.Any("error", err),
+				).Debug("failed to set map field value from form data")
+
+				if lenient {
+					field.Set(reflect.Zero(field.Type()))
+					skipped = append(skipped, erm.NewValidationError(erm.MsgInvalid, formTag, ""))
+					continue
+				}
+				return erm.NewValidationError(erm.MsgErrorInvalidRequest, erm.NonFieldErrors, "", "")
+			}
+			continue
+		}
+
 		// Get form values for this field
 		formValues, exists := values[formTag]
 		if !exists || len(formValues) == 0 {
@@ -283,10 +655,63 @@ func mapFormToStruct(values map[string][]string, target interface{}) erm.Error {
 				slog.Any("error", err),
 			).Debug("failed to set field value from form data")
 
+			if lenient {
+				field.Set(reflect.Zero(field.Type()))
+				skipped = append(skipped, erm.NewValidationError(erm.MsgInvalid, formTag, formValues[0]))
+				continue
+			}
 			return erm.NewValidationError(erm.MsgErrorInvalidRequest, erm.NonFieldErrors, "", "")
 		}
 	}
 
+	return skippedFieldsError(skipped)
+}
+
+// skippedFieldsError collects the errors of fields skipped by lenient
+// parsing into one erm 400 error, or returns nil if there are none.
+func skippedFieldsError(skipped []erm.Error) erm.Error {
+	if len(skipped) == 0 {
+		return nil
+	}
+	container := erm.New(http.StatusBadRequest, "", nil)
+	container.AddErrors(skipped)
+	return container
+}
+
+// setMapFieldValue binds values with bracketed keys such as "meta[color]"
+// into a map field tagged "meta", keyed by the text between the brackets.
+// The map must have string keys; values are converted like struct fields
+// (see setFieldValue), and interface{} values are stored as strings. Nested
+// brackets are not supported and such keys are ignored. The map is only
+// allocated if at least one key matches.
+func setMapFieldValue(field reflect.Value, tag string, values map[string][]string) error {
+	mapType := field.Type()
+	if mapType.Key().Kind() != reflect.String {
+		return fmt.Errorf("unsupported map key type: %s", mapType.Key())
+	}
+
+	prefix := tag + "["
+	for key, vals := range values {
+		if len(vals) == 0 || !strings.HasPrefix(key, prefix) || !strings.HasSuffix(key, "]") {
+			continue
+		}
+		name := key[len(prefix) : len(key)-1]
+		if name == "" || strings.ContainsAny(name, "[]") {
+			continue
+		}
+
+		elem := reflect.New(mapType.Elem()).Elem()
+		if elem.Kind() == reflect.Interface && mapType.Elem().NumMethod() == 0 {
+			elem.Set(reflect.ValueOf(vals[0]))
+		} else if err := setFieldValue(elem, vals[0]); err != nil {
+			return err
+		}
+
+		if field.IsNil() {
+			field.Set(reflect.MakeMap(mapType))
+		}
+		field.SetMapIndex(reflect.ValueOf(name).Convert(mapType.Key()), elem)
+	}
 	return nil
 }
 
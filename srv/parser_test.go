@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
@@ -13,6 +14,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/c3p0-box/utils/erm"
 )
 
 // Test structures for various parsing scenarios
@@ -610,6 +613,62 @@ func TestParseRequest_EmptyJSON(t *testing.T) {
 	}
 }
 
+func TestParseRequestWithConfig_RequireBody(t *testing.T) {
+	strict := ParseConfig{RequireBody: true}
+
+	tests := []struct {
+		name        string
+		body        func() io.Reader
+		contentType string
+		chunked     bool
+		config      ParseConfig
+		expectedErr string
+		expected    string
+	}{
+		{"empty JSON body", func() io.Reader { return strings.NewReader("") }, MIMEApplicationJSON, false, strict, "request body is required", ""},
+		{"nil body", func() io.Reader { return nil }, MIMEApplicationJSON, false, strict, "request body is required", ""},
+		{"empty chunked body", func() io.Reader { return strings.NewReader("") }, MIMEApplicationJSON, true, strict, "request body is required", ""},
+		{"empty form body", func() io.Reader { return strings.NewReader("") }, MIMEApplicationForm, false, strict, "request body is required", ""},
+		{"no content type", func() io.Reader { return strings.NewReader("") }, "", false, strict, "request body is required", ""},
+		{"JSON body", func() io.Reader { return strings.NewReader(`{"name":"John"}`) }, MIMEApplicationJSON, false, strict, "", "John"},
+		{"chunked JSON body", func() io.Reader { return strings.NewReader(`{"name":"Jane"}`) }, MIMEApplicationJSON, true, strict, "", "Jane"},
+		{"form body", func() io.Reader { return strings.NewReader("name=Joe") }, MIMEApplicationForm, false, strict, "", "Joe"},
+		{"empty body allowed by default", func() io.Reader { return strings.NewReader("") }, MIMEApplicationJSON, false, DefaultParseConfig, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "http://example.com/test?page=2", tt.body())
+			if tt.contentType != "" {
+				req.Header.Set(HeaderContentType, tt.contentType)
+			}
+			if tt.chunked {
+				req.ContentLength = -1
+				req.Body = io.NopCloser(tt.body())
+			}
+
+			var result UserRequest
+			err := ParseRequestWithConfig(req, &result, tt.config)
+
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Fatalf("Expected error %q, got %v", tt.expectedErr, err)
+				}
+				if err.Code() != http.StatusBadRequest {
+					t.Errorf("Expected status 400, got %d", err.Code())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Not expecting any error, but got %v", err)
+			}
+			if result.Name != tt.expected || result.Page != 2 {
+				t.Errorf("Expected name %q and page 2, got %q and %d", tt.expected, result.Name, result.Page)
+			}
+		})
+	}
+}
+
 func TestParseRequest_InvalidJSON(t *testing.T) {
 	req := httptest.NewRequest("POST", "http://example.com/test", bytes.NewReader([]byte("{invalid json")))
 	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
@@ -639,7 +698,7 @@ func TestMapQueryToStruct_FieldNameFallback(t *testing.T) {
 	}
 
 	var result TestStruct
-	err := mapQueryToStruct(queryParams, &result)
+	err := mapQueryToStruct(queryParams, &result, false)
 
 	if err != nil {
 		t.Fatalf("mapQueryToStruct() error = %v", err)
@@ -670,7 +729,7 @@ func TestMapQueryToStruct_SkipUnexportedFields(t *testing.T) {
 	}
 
 	var result TestStruct
-	err := mapQueryToStruct(queryParams, &result)
+	err := mapQueryToStruct(queryParams, &result, false)
 
 	if err != nil {
 		t.Fatalf("mapQueryToStruct() error = %v", err)
@@ -875,6 +934,396 @@ func TestParseRequest_TextUnmarshaler_FormData(t *testing.T) {
 	}
 }
 
+func TestParseRequestWithConfig_FileLimits(t *testing.T) {
+	newRequest := func(files map[string]int) *http.Request {
+		var buf bytes.Buffer
+		writer := multipart.NewWriter(&buf)
+		_ = writer.WriteField("name", "Bob")
+		for _, field := range []string{"avatar", "docs"} {
+			for i := 0; i < files[field]; i++ {
+				part, _ := writer.CreateFormFile(field, fmt.Sprintf("%s%d.bin", field, i))
+				_, _ = part.Write(bytes.Repeat([]byte("x"), 10*(i+1)))
+			}
+		}
+		_ = writer.Close()
+
+		req := httptest.NewRequest("POST", "http://example.com/upload", &buf)
+		req.Header.Set(HeaderContentType, writer.FormDataContentType())
+		return req
+	}
+
+	tests := []struct {
+		name        string
+		files       map[string]int
+		config      ParseConfig
+		expectedErr string
+	}{
+		{"within limits", map[string]int{"avatar": 1, "docs": 2}, ParseConfig{MaxFileSize: 20, MaxFiles: 3}, ""},
+		{"no limits", map[string]int{"docs": 5}, DefaultParseConfig, ""},
+		{"file too large", map[string]int{"docs": 3}, ParseConfig{MaxFileSize: 20}, "docs must not be larger than 20 bytes"},
+		{"too many files", map[string]int{"avatar": 1, "docs": 2}, ParseConfig{MaxFiles: 2}, "at most 2 files may be uploaded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newRequest(tt.files)
+			var result UserRequest
+			err := ParseRequestWithConfig(req, &result, tt.config)
+
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Fatalf("Not expecting any error, but got %v", err)
+				}
+				if result.Name != "Bob" {
+					t.Errorf("Name = %v, want Bob", result.Name)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.expectedErr {
+				t.Fatalf("Expected error %q, got %v", tt.expectedErr, err)
+			}
+			if err.Code() != http.StatusRequestEntityTooLarge {
+				t.Errorf("Expected status 413, got %d", err.Code())
+			}
+		})
+	}
+}
+
+func TestParseRequest_MapFields(t *testing.T) {
+	type MetaRequest struct {
+		Name    string                 `form:"name"`
+		Meta    map[string]string      `form:"meta" query:"meta"`
+		Extra   map[string]interface{} `form:"extra"`
+		Weights map[string]int         `query:"weights"`
+	}
+
+	t.Run("form", func(t *testing.T) {
+		form := url.Values{}
+		form.Set("name", "shirt")
+		form.Set("meta[color]", "red")
+		form.Set("meta[size]", "L")
+		form.Set("meta[]", "ignored")
+		form.Set("meta[a][b]", "ignored")
+		form.Set("metadata", "ignored")
+		form.Set("extra[note]", "gift")
+
+		req := httptest.NewRequest("POST", "http://example.com/items", strings.NewReader(form.Encode()))
+		req.Header.Set(HeaderContentType, MIMEApplicationForm)
+
+		var result MetaRequest
+		if err := ParseRequest(req, &result); err != nil {
+			t.Fatalf("ParseRequest() error = %v", err)
+		}
+		if len(result.Meta) != 2 || result.Meta["color"] != "red" || result.Meta["size"] != "L" {
+			t.Errorf("Meta = %v, want map[color:red size:L]", result.Meta)
+		}
+		if result.Extra["note"] != "gift" {
+			t.Errorf("Extra = %v, want map[note:gift]", result.Extra)
+		}
+		if result.Weights != nil {
+			t.Errorf("Weights = %v, want nil", result.Weights)
+		}
+	})
+
+	t.Run("query", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "http://example.com/items?meta[color]=blue&weights[a]=3&weights[b]=5", nil)
+
+		var result MetaRequest
+		if err := ParseRequest(req, &result); err != nil {
+			t.Fatalf("ParseRequest() error = %v", err)
+		}
+		if result.Meta["color"] != "blue" {
+			t.Errorf("Meta = %v, want map[color:blue]", result.Meta)
+		}
+		if result.Weights["a"] != 3 || result.Weights["b"] != 5 {
+			t.Errorf("Weights = %v, want map[a:3 b:5]", result.Weights)
+		}
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "http://example.com/items?weights[a]=heavy", nil)
+
+		var result MetaRequest
+		if err := ParseRequest(req, &result); err == nil {
+			t.Fatal("Expected error for non-integer map value")
+		}
+	})
+}
+
+func TestParseRequestWithConfig_Lenient(t *testing.T) {
+	lenient := ParseConfig{Lenient: true}
+
+	t.Run("skips malformed query and form fields", func(t *testing.T) {
+		form := url.Values{"name": {"John"}, "age": {"old"}, "active": {"true"}}
+		req := httptest.NewRequest("POST", "http://example.com/search?page=abc&sort=name", strings.NewReader(form.Encode()))
+		req.Header.Set(HeaderContentType, MIMEApplicationForm)
+
+		result := UserRequest{Age: 99, Page: 7}
+		err := ParseRequestWithConfig(req, &result, lenient)
+		if err == nil {
+			t.Fatal("Expected error listing skipped fields")
+		}
+		if err.Code() != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", err.Code())
+		}
+		errMap := err.ErrMap()
+		if len(errMap) != 2 || len(errMap["page"]) != 1 || len(errMap["age"]) != 1 {
+			t.Errorf("Expected errors for page and age, got %v", errMap)
+		}
+
+		// Valid fields are bound, skipped ones are zeroed
+		if result.Name != "John" || !result.Active || result.Sort != "name" {
+			t.Errorf("Expected valid fields to be bound, got %+v", result)
+		}
+		if result.Age != 0 || result.Page != 0 {
+			t.Errorf("Expected skipped fields to be zero, got age=%d page=%d", result.Age, result.Page)
+		}
+	})
+
+	t.Run("no error when everything converts", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "http://example.com/search?page=2", nil)
+
+		var result UserRequest
+		if err := ParseRequestWithConfig(req, &result, lenient); err != nil {
+			t.Fatalf("Not expecting any error, but got %v", err)
+		}
+		if result.Page != 2 {
+			t.Errorf("Page = %v, want 2", result.Page)
+		}
+	})
+
+	t.Run("malformed body still fails", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "http://example.com/search?page=abc", strings.NewReader("{invalid"))
+		req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+
+		var result UserRequest
+		err := ParseRequestWithConfig(req, &result, lenient)
+		if err == nil || err.MessageKey() != "error.invalid_request" {
+			t.Fatalf("Expected invalid request error, got %v", err)
+		}
+	})
+
+	t.Run("strict by default", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "http://example.com/search?page=abc&sort=name", nil)
+
+		var result UserRequest
+		err := ParseRequest(req, &result)
+		if err == nil || err.HasErrors() {
+			t.Fatalf("Expected single invalid request error, got %v", err)
+		}
+	})
+}
+
+func TestParseRequestWithConfig_MaxBodyBytes(t *testing.T) {
+	config := ParseConfig{MaxBodyBytes: 20}
+	large := `{"name":"` + strings.Repeat("x", 30) + `"}`
+
+	tests := []struct {
+		name         string
+		body         string
+		contentType  string
+		chunked      bool
+		expectedCode int
+	}{
+		{"within limit", `{"name":"John"}`, MIMEApplicationJSON, false, 0},
+		{"content length over limit", large, MIMEApplicationJSON, false, http.StatusRequestEntityTooLarge},
+		{"chunked JSON over limit", large, MIMEApplicationJSON, true, http.StatusRequestEntityTooLarge},
+		{"chunked form over limit", "name=" + strings.Repeat("x", 30), MIMEApplicationForm, true, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "http://example.com/test", strings.NewReader(tt.body))
+			req.Header.Set(HeaderContentType, tt.contentType)
+			if tt.chunked {
+				req.ContentLength = -1
+				req.Body = io.NopCloser(strings.NewReader(tt.body))
+			}
+
+			var result UserRequest
+			err := ParseRequestWithConfig(req, &result, config)
+			if tt.expectedCode == 0 {
+				if err != nil {
+					t.Fatalf("Not expecting any error, but got %v", err)
+				}
+				return
+			}
+			if err == nil || err.Code() != tt.expectedCode {
+				t.Fatalf("Expected status %d, got %v", tt.expectedCode, err)
+			}
+			if err.Error() != "request body must not be larger than 20 bytes" {
+				t.Errorf("Unexpected message: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseRequestWithConfig_UseNumber(t *testing.T) {
+	const body = `{"id":1234567890123456789,"count":3}`
+
+	tests := []struct {
+		name      string
+		useNumber bool
+		expected  interface{}
+	}{
+		{"float64 by default", false, float64(1234567890123456789)},
+		{"json.Number", true, json.Number("1234567890123456789")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "http://example.com/test", strings.NewReader(body))
+			req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+
+			var result map[string]interface{}
+			if err := ParseRequestWithConfig(req, &result, ParseConfig{UseNumber: tt.useNumber}); err != nil {
+				t.Fatalf("Not expecting any error, but got %v", err)
+			}
+			if result["id"] != tt.expected {
+				t.Errorf("id = %#v, want %#v", result["id"], tt.expected)
+			}
+		})
+	}
+
+	t.Run("typed fields unaffected", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "http://example.com/test", strings.NewReader(body))
+		req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+
+		var result struct {
+			ID    int64       `json:"id"`
+			Count interface{} `json:"count"`
+		}
+		if err := ParseRequestWithConfig(req, &result, ParseConfig{UseNumber: true}); err != nil {
+			t.Fatalf("Not expecting any error, but got %v", err)
+		}
+		if result.ID != 1234567890123456789 {
+			t.Errorf("ID = %d, want 1234567890123456789", result.ID)
+		}
+		if result.Count != json.Number("3") {
+			t.Errorf("Count = %#v, want json.Number", result.Count)
+		}
+	})
+}
+
+func TestParseRequestWithConfig_QueryOverridesBody(t *testing.T) {
+	type Request struct {
+		Limit int    `json:"limit" form:"limit" query:"limit"`
+		Sort  string `json:"sort" form:"sort" query:"sort"`
+	}
+
+	tests := []struct {
+		name          string
+		contentType   string
+		body          string
+		queryWins     bool
+		expectedLimit int
+		expectedSort  string
+	}{
+		{"JSON body wins by default", MIMEApplicationJSON, `{"limit":10,"sort":"name"}`, false, 10, "name"},
+		{"JSON query wins", MIMEApplicationJSON, `{"limit":10,"sort":"name"}`, true, 50, "name"},
+		{"form body wins by default", MIMEApplicationForm, "limit=10&sort=name", false, 10, "name"},
+		{"form query wins", MIMEApplicationForm, "limit=10&sort=name", true, 50, "name"},
+		{"query fills fields missing from body", MIMEApplicationJSON, `{"sort":"name"}`, false, 50, "name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "http://example.com/items?limit=50", strings.NewReader(tt.body))
+			req.Header.Set(HeaderContentType, tt.contentType)
+
+			var result Request
+			if err := ParseRequestWithConfig(req, &result, ParseConfig{QueryOverridesBody: tt.queryWins}); err != nil {
+				t.Fatalf("Not expecting any error, but got %v", err)
+			}
+			if result.Limit != tt.expectedLimit || result.Sort != tt.expectedSort {
+				t.Errorf("got limit=%d sort=%q, want limit=%d sort=%q", result.Limit, result.Sort, tt.expectedLimit, tt.expectedSort)
+			}
+		})
+	}
+}
+
+func TestParseRequestStream(t *testing.T) {
+	type Item struct {
+		ID int `json:"id"`
+	}
+	decodeItems := func(items *[]Item) func(decoder *json.Decoder) error {
+		return func(decoder *json.Decoder) error {
+			if _, err := decoder.Token(); err != nil {
+				return err
+			}
+			for decoder.More() {
+				var item Item
+				if err := decoder.Decode(&item); err != nil {
+					return err
+				}
+				*items = append(*items, item)
+			}
+			_, err := decoder.Token()
+			return err
+		}
+	}
+
+	tests := []struct {
+		name         string
+		body         string
+		contentType  string
+		chunked      bool
+		config       ParseConfig
+		fn           func(decoder *json.Decoder) error
+		expectedCode int
+		expectedLen  int
+	}{
+		{"array", `[{"id":1},{"id":2},{"id":3}]`, MIMEApplicationJSON, false, DefaultParseConfig, nil, 0, 3},
+		{"no content type", `[{"id":1}]`, "", false, DefaultParseConfig, nil, 0, 1},
+		{"empty body", "", MIMEApplicationJSON, false, DefaultParseConfig, nil, 0, 0},
+		{"empty body required", "", MIMEApplicationJSON, false, ParseConfig{RequireBody: true}, nil, http.StatusBadRequest, 0},
+		{"malformed JSON", `[{"id":1},{"id":`, MIMEApplicationJSON, false, DefaultParseConfig, nil, http.StatusBadRequest, 1},
+		{"wrong type", `[{"id":"one"}]`, MIMEApplicationJSON, false, DefaultParseConfig, nil, http.StatusBadRequest, 0},
+		{"unsupported content type", `[]`, MIMEApplicationForm, false, DefaultParseConfig, nil, http.StatusBadRequest, 0},
+		{"content length over limit", `[{"id":1},{"id":2}]`, MIMEApplicationJSON, false, ParseConfig{MaxBodyBytes: 12}, nil, http.StatusRequestEntityTooLarge, 0},
+		{"chunked over limit", `[{"id":1},{"id":2}]`, MIMEApplicationJSON, true, ParseConfig{MaxBodyBytes: 12}, nil, http.StatusRequestEntityTooLarge, 1},
+		{"erm error from callback", `[]`, MIMEApplicationJSON, false, DefaultParseConfig, func(*json.Decoder) error {
+			return erm.Conflict("duplicate item", nil)
+		}, http.StatusConflict, 0},
+		{"wrapped erm error from callback", `[]`, MIMEApplicationJSON, false, DefaultParseConfig, func(*json.Decoder) error {
+			return fmt.Errorf("import items: %w", erm.Conflict("duplicate item", nil))
+		}, http.StatusConflict, 0},
+		{"plain error from callback", `[]`, MIMEApplicationJSON, false, DefaultParseConfig, func(*json.Decoder) error {
+			return errors.New("database down")
+		}, http.StatusInternalServerError, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "http://example.com/bulk", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set(HeaderContentType, tt.contentType)
+			}
+			if tt.chunked {
+				req.ContentLength = -1
+				req.Body = io.NopCloser(strings.NewReader(tt.body))
+			}
+
+			var items []Item
+			fn := tt.fn
+			if fn == nil {
+				fn = decodeItems(&items)
+			}
+			err := ParseRequestStreamWithConfig(req, tt.config, fn)
+
+			if tt.expectedCode == 0 && err != nil {
+				t.Fatalf("Not expecting any error, but got %v", err)
+			}
+			if tt.expectedCode != 0 && (err == nil || err.Code() != tt.expectedCode) {
+				t.Fatalf("Expected status %d, got %v", tt.expectedCode, err)
+			}
+			if len(items) != tt.expectedLen {
+				t.Errorf("Expected %d items decoded, got %d", tt.expectedLen, len(items))
+			}
+		})
+	}
+}
+
 func TestParseRequest_TextUnmarshaler_MultipartForm(t *testing.T) {
 	var buf bytes.Buffer
 	writer := multipart.NewWriter(&buf)
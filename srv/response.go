@@ -0,0 +1,237 @@
+package srv
+
+import (
+	"bytes"
+	"net/http"
+)
+
+// bufferedResponseWriter captures the status code and body written by a
+// handler so that middleware can inspect or rewrite the response before it is
+// sent to the client. Headers are shared with the underlying writer and are
+// only sent once the buffered response is flushed.
+//
+// If the handler flushes the response (directly through http.Flusher or via
+// http.ResponseController), the writer treats the response as a stream: the
+// buffered data is sent immediately and all further writes go straight to the
+// underlying writer. Middleware can check Streaming to leave such responses
+// untouched.
+type bufferedResponseWriter struct {
+	http.ResponseWriter
+	status    int
+	body      bytes.Buffer
+	streaming bool
+}
+
+// newBufferedResponseWriter wraps w in a bufferedResponseWriter.
+func newBufferedResponseWriter(w http.ResponseWriter) *bufferedResponseWriter {
+	return &bufferedResponseWriter{ResponseWriter: w}
+}
+
+// WriteHeader records the status code. Only the first call has any effect,
+// mirroring the behavior of http.ResponseWriter. Informational responses such
+// as 103 Early Hints are not final and are forwarded immediately.
+func (b *bufferedResponseWriter) WriteHeader(code int) {
+	if b.streaming || isInformational(code) {
+		b.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if b.status == 0 {
+		b.status = code
+	}
+}
+
+// Write appends p to the buffered body, or writes it through when streaming.
+func (b *bufferedResponseWriter) Write(p []byte) (int, error) {
+	if b.streaming {
+		return b.ResponseWriter.Write(p)
+	}
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	return b.body.Write(p)
+}
+
+// Flush switches the writer to streaming mode, sends everything buffered so
+// far and flushes the underlying writer if it supports flushing.
+func (b *bufferedResponseWriter) Flush() {
+	if !b.streaming {
+		b.streaming = true
+		_ = b.flushBuffer()
+	}
+	_ = http.NewResponseController(b.ResponseWriter).Flush()
+}
+
+// Unwrap returns the underlying http.ResponseWriter so that
+// http.ResponseController can reach optional interfaces such as Hijacker.
+func (b *bufferedResponseWriter) Unwrap() http.ResponseWriter {
+	return b.ResponseWriter
+}
+
+// Status returns the recorded status code, defaulting to 200 OK when the
+// handler wrote nothing or only wrote a body.
+func (b *bufferedResponseWriter) Status() int {
+	if b.status == 0 {
+		return http.StatusOK
+	}
+	return b.status
+}
+
+// Streaming reports whether the handler flushed the response.
+func (b *bufferedResponseWriter) Streaming() bool {
+	return b.streaming
+}
+
+// Body returns the buffered response body.
+func (b *bufferedResponseWriter) Body() []byte {
+	return b.body.Bytes()
+}
+
+// flushBuffer writes the recorded status and buffered body to the underlying
+// writer and resets the buffer. Nothing is written if the handler produced no
+// output at all.
+func (b *bufferedResponseWriter) flushBuffer() error {
+	if b.status == 0 && b.body.Len() == 0 {
+		return nil
+	}
+	b.ResponseWriter.WriteHeader(b.Status())
+	_, err := b.ResponseWriter.Write(b.body.Bytes())
+	b.body.Reset()
+	return err
+}
+
+// teeResponseWriter passes everything through to the underlying writer while
+// keeping a copy of the status code and up to limit bytes of the body. Unlike
+// bufferedResponseWriter it never delays the response, so it is safe for
+// streaming handlers.
+type teeResponseWriter struct {
+	http.ResponseWriter
+	status    int
+	body      bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+// newTeeResponseWriter wraps w, recording at most limit bytes of the body.
+func newTeeResponseWriter(w http.ResponseWriter, limit int) *teeResponseWriter {
+	return &teeResponseWriter{ResponseWriter: w, limit: limit}
+}
+
+// WriteHeader records the first final status code and forwards it.
+func (t *teeResponseWriter) WriteHeader(code int) {
+	if t.status == 0 && !isInformational(code) {
+		t.status = code
+	}
+	t.ResponseWriter.WriteHeader(code)
+}
+
+// Write records p up to the limit and forwards it.
+func (t *teeResponseWriter) Write(p []byte) (int, error) {
+	if t.status == 0 {
+		t.status = http.StatusOK
+	}
+	if remaining := t.limit - t.body.Len(); remaining > 0 {
+		if len(p) > remaining {
+			t.body.Write(p[:remaining])
+			t.truncated = true
+		} else {
+			t.body.Write(p)
+		}
+	} else if len(p) > 0 {
+		t.truncated = true
+	}
+	return t.ResponseWriter.Write(p)
+}
+
+// Flush flushes the underlying writer if it supports flushing.
+func (t *teeResponseWriter) Flush() {
+	_ = http.NewResponseController(t.ResponseWriter).Flush()
+}
+
+// Unwrap returns the underlying http.ResponseWriter.
+func (t *teeResponseWriter) Unwrap() http.ResponseWriter {
+	return t.ResponseWriter
+}
+
+// Status returns the recorded status code, defaulting to 200 OK.
+func (t *teeResponseWriter) Status() int {
+	if t.status == 0 {
+		return http.StatusOK
+	}
+	return t.status
+}
+
+// isInformational reports whether code is a non-final 1xx status. 101
+// Switching Protocols is final for the HTTP exchange and is not included.
+func isInformational(code int) bool {
+	return code >= 100 && code < 200 && code != http.StatusSwitchingProtocols
+}
+
+// hookResponseWriter calls hook exactly once, right before the final status
+// code is sent to the underlying writer. This is the last point at which
+// response headers can still be modified. With a nil hook it only tracks
+// whether the response has been committed.
+type hookResponseWriter struct {
+	http.ResponseWriter
+	hook  func()
+	fired bool
+}
+
+// newHookResponseWriter wraps w, calling hook before the response is committed.
+func newHookResponseWriter(w http.ResponseWriter, hook func()) *hookResponseWriter {
+	return &hookResponseWriter{ResponseWriter: w, hook: hook}
+}
+
+// WriteHeader runs the hook for final status codes and forwards the call.
+func (h *hookResponseWriter) WriteHeader(code int) {
+	if !isInformational(code) {
+		h.fire()
+	}
+	h.ResponseWriter.WriteHeader(code)
+}
+
+// Write runs the hook, since the first Write implies WriteHeader(200), and
+// forwards the call.
+func (h *hookResponseWriter) Write(p []byte) (int, error) {
+	h.fire()
+	return h.ResponseWriter.Write(p)
+}
+
+// Flush runs the hook and flushes the underlying writer if it supports
+// flushing.
+func (h *hookResponseWriter) Flush() {
+	h.fire()
+	_ = http.NewResponseController(h.ResponseWriter).Flush()
+}
+
+// Unwrap returns the underlying http.ResponseWriter.
+func (h *hookResponseWriter) Unwrap() http.ResponseWriter {
+	return h.ResponseWriter
+}
+
+// Fired reports whether the hook has run.
+func (h *hookResponseWriter) Fired() bool {
+	return h.fired
+}
+
+func (h *hookResponseWriter) fire() {
+	if !h.fired {
+		h.fired = true
+		if h.hook != nil {
+			h.hook()
+		}
+	}
+}
+
+// discardResponseWriter is an http.ResponseWriter that drops everything
+// written to it. It lets code that writes headers as a side effect (such as
+// session stores setting cookies) run after the response was committed.
+type discardResponseWriter struct{}
+
+// Header returns a fresh, unused header map.
+func (discardResponseWriter) Header() http.Header { return http.Header{} }
+
+// Write discards p.
+func (discardResponseWriter) Write(p []byte) (int, error) { return len(p), nil }
+
+// WriteHeader does nothing.
+func (discardResponseWriter) WriteHeader(int) {}
@@ -36,9 +36,14 @@ package srv
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"syscall"
@@ -51,6 +56,7 @@ const (
 	charsetUTF8 = "charset=UTF-8"
 	// MIMEApplicationJSON JavaScript Object Notation (JSON) https://www.rfc-editor.org/rfc/rfc8259
 	MIMEApplicationJSON                  = "application/json"
+	MIMEApplicationJSONCharsetUTF8       = MIMEApplicationJSON + "; " + charsetUTF8
 	MIMEApplicationJavaScript            = "application/javascript"
 	MIMEApplicationJavaScriptCharsetUTF8 = MIMEApplicationJavaScript + "; " + charsetUTF8
 	MIMEApplicationXML                   = "application/xml"
@@ -71,6 +77,7 @@ const (
 const (
 	HeaderAccept         = "Accept"
 	HeaderAcceptEncoding = "Accept-Encoding"
+	HeaderAcceptLanguage = "Accept-Language"
 	// HeaderAllow is the name of the "Allow" header field used to list the set of methods
 	// advertised as supported by the target resource. Returning an Allow header is mandatory
 	// for status 405 (method not found) and useful for the OPTIONS method in responses.
@@ -82,9 +89,12 @@ const (
 	HeaderContentLength       = "Content-Length"
 	HeaderContentType         = "Content-Type"
 	HeaderCookie              = "Cookie"
+	HeaderETag                = "ETag"
 	HeaderSetCookie           = "Set-Cookie"
 	HeaderIfModifiedSince     = "If-Modified-Since"
+	HeaderIfNoneMatch         = "If-None-Match"
 	HeaderLastModified        = "Last-Modified"
+	HeaderLink                = "Link"
 	HeaderLocation            = "Location"
 	HeaderRetryAfter          = "Retry-After"
 	HeaderUpgrade             = "Upgrade"
@@ -98,6 +108,7 @@ const (
 	HeaderXHTTPMethodOverride = "X-HTTP-Method-Override"
 	HeaderXRealIP             = "X-Real-Ip"
 	HeaderXRequestID          = "X-Request-Id"
+	HeaderXResponseTime       = "X-Response-Time"
 	HeaderXCorrelationID      = "X-Correlation-Id"
 	HeaderXRequestedWith      = "X-Requested-With"
 	HeaderServer              = "Server"
@@ -105,6 +116,12 @@ const (
 	HeaderCacheControl        = "Cache-Control"
 	HeaderConnection          = "Connection"
 
+	// WebSocket
+	HeaderSecWebSocketKey      = "Sec-WebSocket-Key"
+	HeaderSecWebSocketVersion  = "Sec-WebSocket-Version"
+	HeaderSecWebSocketAccept   = "Sec-WebSocket-Accept"
+	HeaderSecWebSocketProtocol = "Sec-WebSocket-Protocol"
+
 	// Access control
 	HeaderAccessControlRequestMethod    = "Access-Control-Request-Method"
 	HeaderAccessControlRequestHeaders   = "Access-Control-Request-Headers"
@@ -252,6 +269,58 @@ type HandlerFunc func(ctx Context) error
 //	}
 type HandlerFuncMiddleware func(next HandlerFunc) HandlerFunc
 
+// Stack composes middleware into a single HandlerFuncMiddleware, so that a
+// common set can be defined once and applied to several muxes. The first
+// middleware is the outermost wrapper, as with Mux.Middleware; nil entries
+// are skipped.
+//
+// Example:
+//
+//	apiStack := srv.Stack(
+//	    srv.LoggingMiddleware,
+//	    srv.RecoverMiddleware,
+//	    srv.CORSMiddleware(srv.DefaultCORSConfig),
+//	)
+//	apiMux.Middleware(apiStack)
+//	adminMux.Middleware(apiStack)
+func Stack(middlewares ...HandlerFuncMiddleware) HandlerFuncMiddleware {
+	middlewares = slices.Clone(middlewares)
+	return func(next HandlerFunc) HandlerFunc {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			if middlewares[i] != nil {
+				next = middlewares[i](next)
+			}
+		}
+		return next
+	}
+}
+
+// When applies middleware only to requests for which predicate returns
+// true; other requests go straight to the next handler. The predicate runs
+// once per request, before the middleware.
+//
+// Example:
+//
+//	// Verbose body logging in debug builds only
+//	mux.Middleware(srv.When(func(ctx srv.Context) bool { return debug },
+//	    srv.BodyLoggingMiddleware(srv.DefaultBodyLoggingConfig)))
+//
+//	// ETags for the API only
+//	mux.Middleware(srv.When(func(ctx srv.Context) bool {
+//	    return strings.HasPrefix(ctx.Path(), "/api/")
+//	}, srv.ETagMiddleware))
+func When(predicate func(ctx Context) bool, middleware HandlerFuncMiddleware) HandlerFuncMiddleware {
+	return func(next HandlerFunc) HandlerFunc {
+		wrapped := middleware(next)
+		return func(ctx Context) error {
+			if predicate(ctx) {
+				return wrapped(ctx)
+			}
+			return next(ctx)
+		}
+	}
+}
+
 // Mux provides a convenient wrapper around Go's standard http.ServeMux
 // with helper methods for common HTTP operations, RESTful routing, URL reversing, and centralized error handling.
 //
@@ -265,23 +334,44 @@ type Mux struct {
 	routes      map[string]Route        // Named routes for URL reversing, key format: "name"
 	routesMu    sync.RWMutex            // Protects routes map from concurrent access
 	middlewares []HandlerFuncMiddleware // HandlerFunc middleware stack
+	// disableHTMLEscape is applied to every Context created by the Mux.
+	disableHTMLEscape bool
+	// defaultContentType is set on responses that do not set a Content-Type.
+	defaultContentType string
+	// disableRecover lets panics of HandlerFunc-based routes propagate.
+	disableRecover bool
+	// caseInsensitive matches request paths in lower case.
+	caseInsensitive bool
+	// globalMiddlewares wrap every request, including unmatched ones.
+	globalMiddlewares []HandlerFuncMiddleware
+	beforeHooks       []func(ctx Context) error
+	afterHooks        []func(ctx Context, err error)
 }
 
 // NewMux creates a new Mux instance with an underlying http.ServeMux and a default error handler.
-// The default error handler responds with a 500 Internal Server Error and logs the error.
+// The default error handler responds to erm errors below 500 (e.g. from ctx.BadRequest) with
+// their status code and user-facing message, and to any other error with a 500 Internal Server
+// Error "Something went wrong", so that internal details never reach the client.
 // The returned Mux is safe for concurrent use by multiple goroutines.
 func NewMux() *Mux {
 	return &Mux{
-		mux: http.NewServeMux(),
-		errHandler: func(ctx Context, err error) {
-			_ = ctx.String(http.StatusInternalServerError, "Something went wrong")
-		},
+		mux:         http.NewServeMux(),
+		errHandler:  defaultErrorHandler,
 		routes:      make(map[string]Route),
 		routesMu:    sync.RWMutex{},
 		middlewares: make([]HandlerFuncMiddleware, 0),
 	}
 }
 
+// defaultErrorHandler is the error handler of a Mux without ErrorHandler.
+func defaultErrorHandler(ctx Context, err error) {
+	if code := erm.Status(err); code >= 400 && code < 500 {
+		_ = ctx.String(code, erm.Message(err))
+		return
+	}
+	_ = ctx.String(http.StatusInternalServerError, "Something went wrong")
+}
+
 // Mux returns the underlying http.ServeMux for advanced usage or
 // integration with other HTTP libraries.
 func (m *Mux) Mux() *http.ServeMux {
@@ -289,11 +379,86 @@ func (m *Mux) Mux() *http.ServeMux {
 }
 
 // ServeHTTP implements http.Handler interface, allowing Mux to be used
-// directly as an HTTP handler.
+// directly as an HTTP handler. Requests pass through the GlobalMiddleware
+// chain, if any, before being dispatched.
 func (m *Mux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if len(m.globalMiddlewares) == 0 {
+		m.dispatch(w, r)
+		return
+	}
+
+	tracked := newHookResponseWriter(w, nil)
+	ctx := NewHttpContext(tracked, r)
+	ctx.disableHTMLEscape = m.disableHTMLEscape
+	// Route handlers find and reuse this context, see execHandler
+	ctx.SetContext(muxContextKey{}, ctx)
+
+	handler := Stack(m.globalMiddlewares...)(func(ctx Context) error {
+		m.dispatch(ctx.Response(), ctx.Request())
+		return nil
+	})
+	if !m.disableRecover {
+		handler = RecoverMiddleware(handler)
+	}
+	if err := handler(ctx); err != nil {
+		m.handleError(ctx, tracked, err)
+	}
+}
+
+// muxContextKey is the request context key under which ServeHTTP stores the
+// Context shared by GlobalMiddleware and the matched route.
+type muxContextKey struct{}
+
+// originalURLKey is the request context key under which dispatch stores the
+// URL of a request whose path it lowercased for case-insensitive matching.
+type originalURLKey struct{}
+
+// dispatch routes r to the matching handler of the underlying ServeMux,
+// with a lowercased path if CaseInsensitive is enabled.
+func (m *Mux) dispatch(w http.ResponseWriter, r *http.Request) {
+	if m.caseInsensitive {
+		if lower := strings.ToLower(r.URL.Path); lower != r.URL.Path {
+			original := r.URL
+			r = r.WithContext(context.WithValue(r.Context(), originalURLKey{}, original))
+			lowered := *original
+			lowered.Path = lower
+			lowered.RawPath = strings.ToLower(original.RawPath)
+			r.URL = &lowered
+		}
+	}
 	m.mux.ServeHTTP(w, r)
 }
 
+// restoreOriginalPath undoes the lowercasing of dispatch on the request
+// passed to a route registered with pattern: it restores the original URL
+// and sets the path values from the original path segments, since
+// wildcards always match whole segments.
+func restoreOriginalPath(r *http.Request, pattern string) {
+	original, ok := r.Context().Value(originalURLKey{}).(*url.URL)
+	if !ok {
+		return
+	}
+	r.URL = original
+
+	if i := strings.IndexByte(pattern, '/'); i > 0 {
+		pattern = pattern[i:] // Strip the host
+	}
+	segments := strings.Split(original.Path, "/")
+	for i, segment := range strings.Split(pattern, "/") {
+		if i >= len(segments) || !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			continue
+		}
+		name := segment[1 : len(segment)-1]
+		switch {
+		case name == "$":
+		case strings.HasSuffix(name, "..."):
+			r.SetPathValue(strings.TrimSuffix(name, "..."), strings.Join(segments[i:], "/"))
+		default:
+			r.SetPathValue(name, segments[i])
+		}
+	}
+}
+
 // Handle registers a handler for the given pattern.
 func (m *Mux) Handle(pattern string, handler http.Handler) {
 	m.mux.Handle(pattern, handler)
@@ -304,9 +469,31 @@ func (m *Mux) HandleFunc(pattern string, handler http.HandlerFunc) {
 	m.mux.HandleFunc(pattern, handler)
 }
 
+// Mount registers handler for the subtree rooted at prefix and strips the
+// prefix from the request path before calling it, so that existing
+// http.Handler components (admin UIs, file servers, other routers) can be
+// served under a prefix while seeing paths relative to it.
+//
+// A trailing slash is added to prefix if missing; requests for the prefix
+// without the slash are redirected to it by the underlying http.ServeMux.
+// Mounted handlers are plain http.Handlers: HandlerFunc middleware, hooks and
+// the error handler do not apply to them.
+//
+// Example:
+//
+//	mux.Mount("/admin", adminUI)      // GET /admin/users -> adminUI sees /users
+//	mux.Mount("/static/", http.FileServer(http.Dir("public")))
+func (m *Mux) Mount(prefix string, handler http.Handler) {
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	m.mux.Handle(prefix, http.StripPrefix(strings.TrimSuffix(prefix, "/"), handler))
+}
+
 // ErrorHandler sets a custom error handler for all HandlerFunc-based routes.
 // The error handler will be called whenever a HandlerFunc returns a non-nil error.
-// If no custom error handler is set, the default handler returns a 500 Internal Server Error.
+// If no custom error handler is set, the default handler (see NewMux) responds with
+// the status and message of erm client errors and a 500 Internal Server Error otherwise.
 //
 // Example:
 //
@@ -318,8 +505,99 @@ func (m *Mux) ErrorHandler(handler func(c Context, err error)) {
 	m.errHandler = handler
 }
 
+// EscapeHTML sets whether JSON responses of all HandlerFunc-based routes
+// escape <, > and & (see HttpContext.SetEscapeHTML). Escaping is enabled by
+// default; handlers can still override the setting per request.
+//
+// Example:
+//
+//	mux.EscapeHTML(false) // {"url":"/search?q=a&page=2"} instead of \u0026
+func (m *Mux) EscapeHTML(escape bool) {
+	m.disableHTMLEscape = !escape
+}
+
+// RecoverPanics sets whether panics in HandlerFunc-based routes, their
+// middleware and GlobalMiddleware are recovered and passed to the error
+// handler as a 500 error, as RecoverMiddleware does. Recovery is enabled by
+// default, so a panic never bypasses the error handler; disable it to let
+// panics reach net/http, e.g. when a custom RecoverMiddleware registered as
+// the outermost middleware should handle them.
+//
+// Example:
+//
+//	mux.RecoverPanics(false)
+func (m *Mux) RecoverPanics(enabled bool) {
+	m.disableRecover = !enabled
+}
+
+// CaseInsensitive sets whether request paths match routes regardless of
+// case, e.g. /Users/42 matches "GET /users/{id}". The path is lowercased for
+// matching only: HandlerFunc-based routes see the original URL, and path
+// values keep their original case. Patterns must be registered in lower
+// case to match. Handlers registered with Handle, HandleFunc and Mount
+// receive the lowercased URL.
+//
+// Example:
+//
+//	mux.CaseInsensitive(true)
+func (m *Mux) CaseInsensitive(enabled bool) {
+	m.caseInsensitive = enabled
+}
+
+// DefaultContentType sets the Content-Type used for responses of
+// HandlerFunc-based routes that do not set one themselves, e.g. handlers
+// writing to ctx.Response() directly. Helpers such as ctx.String and ctx.JSON
+// still set their own type. The default is applied when the response is
+// committed, so handlers and middleware can override it at any point before
+// that. An empty string (the default) restores Go's content sniffing.
+//
+// Example:
+//
+//	mux.DefaultContentType(srv.MIMEApplicationJSONCharsetUTF8)
+func (m *Mux) DefaultContentType(contentType string) {
+	m.defaultContentType = contentType
+}
+
+// Before registers a hook that runs before every HandlerFunc-based route,
+// outside of the middleware chain. Hooks run in registration order; if one
+// returns an error, the remaining hooks and the handler are skipped and the
+// error is passed to the error handler.
+//
+// Unlike Middleware, hooks apply to all routes, including those registered
+// before the hook. Register hooks before the server starts serving requests.
+// Since hooks are not wrapped by RecoverMiddleware, the Mux recovers panics in
+// hooks itself: a panicking Before hook is treated as returning a 500 error.
+//
+// Example:
+//
+//	mux.Before(func(ctx srv.Context) error {
+//		requestsTotal.Inc()
+//		return nil
+//	})
+func (m *Mux) Before(hook func(ctx Context) error) {
+	m.beforeHooks = append(m.beforeHooks, hook)
+}
+
+// After registers a hook that runs after every HandlerFunc-based route has
+// completed, including the error handler. It receives the error returned by
+// the handler (or by a Before hook), or nil on success. Hooks run in
+// registration order and apply to all routes, like Before hooks. A panic in
+// an After hook is logged and does not stop the remaining hooks.
+//
+// Example:
+//
+//	mux.After(func(ctx srv.Context, err error) {
+//		audit.Log(ctx.Method(), ctx.Path(), err)
+//	})
+func (m *Mux) After(hook func(ctx Context, err error)) {
+	m.afterHooks = append(m.afterHooks, hook)
+}
+
 // Middleware adds HandlerFunc-based middleware to the Mux.
-// Middleware will be applied to all routes registered after this method is called.
+// Middleware will be applied to all routes registered after this method is called
+// with Get, Post, etc. It only runs when such a route matched, so it does not
+// see unmatched paths (404, 405) or Handle/HandleFunc/Mount routes; use
+// GlobalMiddleware for those.
 // Middleware are applied in the order they are added (first added = outermost wrapper).
 //
 // The middleware function receives the next HandlerFunc in the chain and returns
@@ -355,21 +633,41 @@ func (m *Mux) Middleware(middleware HandlerFuncMiddleware) {
 	m.middlewares = append(m.middlewares, middleware)
 }
 
+// GlobalMiddleware adds HandlerFunc middleware that wraps every request
+// served by the Mux: unmatched paths (404, 405), routes registered with
+// Handle, HandleFunc and Mount, and HandlerFunc routes. Use it for logging,
+// request IDs or metrics that must see all traffic, and Middleware for
+// middleware such as authentication that should only run when a route
+// matched.
+//
+// Global middleware run before the route's Before hooks and Middleware
+// chain, in the order they are added, and share the Context with the
+// matched HandlerFunc route, so values and loggers they set are visible to
+// the handler. An error returned by a global middleware is passed to the
+// error handler; errors returned by the route are handled there and are not
+// seen by global middleware. Unlike Middleware, global middleware apply
+// regardless of whether they are added before or after the routes.
+//
+// Example:
+//
+//	mux.GlobalMiddleware(srv.RequestIDMiddleware) // every request, 404s included
+//	mux.GlobalMiddleware(srv.LoggingMiddleware)
+//	mux.Middleware(authMiddleware)                // matched routes only
+func (m *Mux) GlobalMiddleware(middleware HandlerFuncMiddleware) {
+	m.globalMiddlewares = append(m.globalMiddlewares, middleware)
+}
+
 // applyMiddleware applies all registered HandlerFunc middleware to a handler.
 // Middleware are applied in reverse order so that the first added middleware
 // becomes the outermost wrapper, which is the expected behavior.
 func (m *Mux) applyMiddleware(handler HandlerFunc) HandlerFunc {
-	// Apply middleware in reverse order (last added = innermost)
-	for i := len(m.middlewares) - 1; i >= 0; i-- {
-		handler = m.middlewares[i](handler)
-	}
-	return handler
+	return Stack(m.middlewares...)(handler)
 }
 
 // execHandler is an internal method that wraps HandlerFunc with error handling,
 // applies registered middleware, and registers named routes for URL reversing when a name is provided.
 // It creates a Context and passes it to the middleware chain and handler. If the handler returns
-// an error, it calls the configured error handler with the same context.
+// an error, it calls the configured error handler with the same context (see handleError).
 // This method is safe for concurrent use.
 func (m *Mux) execHandler(name, method, pattern string, handler HandlerFunc) {
 	// Register named route if name is provided
@@ -382,17 +680,135 @@ func (m *Mux) execHandler(name, method, pattern string, handler HandlerFunc) {
 
 	// Apply all registered middleware to the handler
 	finalHandler := m.applyMiddleware(handler)
+	recoveringHandler := RecoverMiddleware(finalHandler)
 
 	// Register the handler with the HTTP mux
 	fullPattern := method + " " + pattern
 	m.mux.HandleFunc(fullPattern, func(w http.ResponseWriter, r *http.Request) {
-		ctx := NewHttpContext(w, r)
-		if err := finalHandler(ctx); err != nil {
-			m.errHandler(ctx, err)
+		restoreOriginalPath(r, pattern)
+		var applyDefaults func()
+		if contentType := m.defaultContentType; contentType != "" {
+			applyDefaults = func() {
+				if w.Header().Get(HeaderContentType) == "" {
+					w.Header().Set(HeaderContentType, contentType)
+				}
+			}
+		}
+		tracked := newHookResponseWriter(w, applyDefaults)
+		ctx, ok := r.Context().Value(muxContextKey{}).(*HttpContext)
+		if ok {
+			// Continue the context of the global middleware with the
+			// request carrying the path values
+			ctx.SetRequest(r)
+			ctx.SetResponse(tracked)
+			if ctx.logger != nil {
+				ctx.logger = ctx.logger.With(slog.String("route", pattern))
+			}
+		} else {
+			ctx = NewHttpContext(tracked, r)
+			ctx.disableHTMLEscape = m.disableHTMLEscape
+		}
+		ctx.routePattern, ctx.routeName = pattern, name
+		err := m.runBeforeHooks(ctx)
+		if err == nil {
+			if m.disableRecover {
+				err = finalHandler(ctx)
+			} else {
+				err = recoveringHandler(ctx)
+			}
+		}
+		if err != nil {
+			m.handleError(ctx, tracked, err)
+		}
+		for _, hook := range m.afterHooks {
+			runAfterHook(ctx, hook, err)
 		}
 	})
 }
 
+// runBeforeHooks runs the Before hooks in order, stopping at the first error.
+// Hooks run outside the middleware chain, so a panicking hook is recovered
+// here and reported as a 500 error.
+func (m *Mux) runBeforeHooks(ctx Context) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = hookPanicError(ctx, "Before", rec)
+		}
+	}()
+	for _, hook := range m.beforeHooks {
+		if err := hook(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runAfterHook runs a single After hook, recovering and logging a panic so
+// that the remaining hooks still run.
+func runAfterHook(ctx Context, hook func(ctx Context, err error), err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			_ = hookPanicError(ctx, "After", rec)
+		}
+	}()
+	hook(ctx, err)
+}
+
+// hookPanicError logs a panic recovered from a Before or After hook and
+// converts it to an erm 500 error.
+func hookPanicError(ctx Context, kind string, rec interface{}) error {
+	panicErr, ok := rec.(error)
+	if !ok {
+		panicErr = fmt.Errorf("panic: %v", rec)
+	}
+	stackErr := erm.Internal("panic recovered", panicErr)
+
+	slog.With(
+		slog.String("name", "srv.Mux"),
+		slog.String("hook", kind),
+		slog.Any("error", rec),
+		slog.String("path", ctx.Request().URL.Path),
+		slog.String("method", ctx.Request().Method),
+		slog.String("stack", erm.FormatStack(stackErr)),
+	).Error("recovered from panic in hook")
+
+	return stackErr
+}
+
+// handleError passes err to the configured error handler, guarding against
+// responses that can no longer be written correctly:
+//   - If the response was already committed (the handler wrote a status or
+//     body before returning the error), the error handler is skipped, since
+//     anything it writes would be appended to the partial response. The error
+//     is logged with slog instead.
+//   - If the error handler panics, the panic is logged with slog and a plain
+//     500 Internal Server Error is sent if the response is still uncommitted.
+func (m *Mux) handleError(ctx Context, tracked *hookResponseWriter, err error) {
+	req := ctx.Request()
+	logger := slog.With(
+		slog.String("name", "srv.Mux"),
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+		slog.String("error", err.Error()),
+	)
+
+	if tracked.Fired() {
+		logger.Error("handler returned an error after the response was written")
+		return
+	}
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			logger.Error("error handler panicked", slog.Any("panic", rec))
+			if !tracked.Fired() {
+				http.Error(tracked, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+		}
+	}()
+
+	m.errHandler(ctx, err)
+}
+
 // ============================
 // HTTP Method Helpers
 // ============================
@@ -501,14 +917,14 @@ func (m *Mux) Reverse(name string, params map[string]string) (string, error) {
 
 	url := route.Pattern
 
-	// Replace path parameters if provided
+	// Replace path parameters if provided, including {name...} wildcards
 	for paramName, paramValue := range params {
-		placeholder := "{" + paramName + "}"
-		if strings.Contains(url, placeholder) {
-			url = strings.ReplaceAll(url, placeholder, paramValue)
-		}
+		url = strings.ReplaceAll(url, "{"+paramName+"}", paramValue)
+		url = strings.ReplaceAll(url, "{"+paramName+"...}", paramValue)
 		// Ignore parameters that don't exist in the pattern
 	}
+	// {$} only anchors the trailing slash and has no value
+	url = strings.ReplaceAll(url, "{$}", "")
 
 	// Check if there are any unreplaced parameters
 	if strings.Contains(url, "{") && strings.Contains(url, "}") {
@@ -517,3 +933,155 @@ func (m *Mux) Reverse(name string, params map[string]string) (string, error) {
 
 	return url, nil
 }
+
+// ValidateRoutes checks that every named route can be reversed, using a
+// placeholder value for each path parameter. Call it at startup or from a
+// test to catch patterns that Reverse cannot handle before a handler hits
+// them at runtime. The returned error joins one error per failing route.
+//
+// Example:
+//
+//	if err := mux.ValidateRoutes(); err != nil {
+//	    log.Fatal(err)
+//	}
+func (m *Mux) ValidateRoutes() error {
+	m.routesMu.RLock()
+	routes := make([]Route, 0, len(m.routes))
+	for _, route := range m.routes {
+		routes = append(routes, route)
+	}
+	m.routesMu.RUnlock()
+	sort.Slice(routes, func(i, j int) bool { return routes[i].Name < routes[j].Name })
+
+	var errs []error
+	for _, route := range routes {
+		params, err := routeParams(route.Pattern)
+		if err == nil {
+			_, err = m.Reverse(route.Name, params)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("route %q (%s): %w", route.Name, route.Pattern, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// routeParams returns a placeholder value for each {name} and {name...}
+// wildcard in pattern, or an error if a wildcard is malformed.
+func routeParams(pattern string) (map[string]string, error) {
+	params := make(map[string]string)
+	for rest := pattern; ; {
+		start := strings.IndexAny(rest, "{}")
+		if start < 0 {
+			return params, nil
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if rest[start] == '}' || end < 0 {
+			return nil, fmt.Errorf("unbalanced braces in %q", pattern)
+		}
+		name := strings.TrimSuffix(rest[start+1:start+end], "...")
+		if name == "" || strings.ContainsAny(name, "{/") {
+			return nil, fmt.Errorf("invalid wildcard %q in %q", rest[start:start+end+1], pattern)
+		}
+		if name != "$" {
+			params[name] = "x"
+		}
+		rest = rest[start+end+1:]
+	}
+}
+
+// ============================
+// Health Checks
+// ============================
+
+// HealthCheck probes a single dependency (database, cache, downstream service)
+// and returns a non-nil error when it is unhealthy. The context passed to the
+// check is the incoming request's context, so probes should honor its
+// cancellation and deadline.
+//
+// Example:
+//
+//	dbCheck := func(ctx context.Context) error {
+//		return db.PingContext(ctx)
+//	}
+type HealthCheck func(ctx context.Context) error
+
+// NamedHealthCheck wraps check so that its failure is reported under the
+// given name by Mux.Health. The returned error keeps the name as a prefix
+// (e.g. "db: connection refused") for logging.
+func NamedHealthCheck(name string, check HealthCheck) HealthCheck {
+	return func(ctx context.Context) error {
+		if err := check(ctx); err != nil {
+			return &healthCheckError{name: name, err: err}
+		}
+		return nil
+	}
+}
+
+// healthCheckError is the error of a failed NamedHealthCheck.
+type healthCheckError struct {
+	name string
+	err  error
+}
+
+func (e *healthCheckError) Error() string { return e.name + ": " + e.err.Error() }
+func (e *healthCheckError) Unwrap() error { return e.err }
+
+// Health registers a GET endpoint at path that runs the given checks in order
+// on every request. It is intended for liveness/readiness probes such as
+// "/healthz" and "/readyz".
+//
+// When all checks pass the endpoint responds with 200 OK and the JSON body
+// {"status":"ok"}. When one or more checks fail it responds with
+// 503 Service Unavailable and a JSON body listing the failed checks by name:
+//
+//	{"status":"unavailable","failed":["db"]}
+//
+// Check errors are logged with slog but never sent to the client, since they
+// may reveal hostnames or credentials. Checks not wrapped in
+// NamedHealthCheck are reported by position ("check 1", "check 2", ...).
+//
+// Nil checks are ignored. The route is registered unnamed and goes through the
+// middleware registered before this call, like any other route.
+//
+// Example:
+//
+//	mux.Health("/healthz")
+//	mux.Health("/readyz",
+//		srv.NamedHealthCheck("db", db.PingContext),
+//		srv.NamedHealthCheck("cache", cache.Ping),
+//	)
+func (m *Mux) Health(path string, checks ...HealthCheck) {
+	m.Get("", path, func(ctx Context) error {
+		failed := make([]string, 0)
+		for i, check := range checks {
+			if check == nil {
+				continue
+			}
+			err := check(ctx.Request().Context())
+			if err == nil {
+				continue
+			}
+			name := fmt.Sprintf("check %d", i+1)
+			var named *healthCheckError
+			if errors.As(err, &named) {
+				name = named.name
+			}
+			slog.Error("health check failed",
+				slog.String("name", "srv.Health"),
+				slog.String("path", path),
+				slog.String("check", name),
+				slog.Any("error", err),
+			)
+			failed = append(failed, name)
+		}
+
+		if len(failed) > 0 {
+			return ctx.JSON(http.StatusServiceUnavailable, map[string]interface{}{
+				"status": "unavailable",
+				"failed": failed,
+			})
+		}
+		return ctx.JSON(http.StatusOK, map[string]interface{}{"status": "ok"})
+	})
+}
@@ -1,6 +1,7 @@
 package srv
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -9,6 +10,8 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	"github.com/c3p0-box/utils/erm"
 )
 
 // Test cleanup function behavior
@@ -368,6 +371,459 @@ func TestMux_DefaultErrorHandler(t *testing.T) {
 	}
 }
 
+func TestMux_DefaultErrorHandler_ErmErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		handler      HandlerFunc
+		expectedCode int
+		expectedBody string
+	}{
+		{"ctx.BadRequest", func(ctx Context) error { return ctx.BadRequest("invalid id") }, http.StatusBadRequest, "invalid id"},
+		{"ctx.NotFound", func(ctx Context) error { return ctx.NotFound("user not found") }, http.StatusNotFound, "user not found"},
+		{"ctx.Error", func(ctx Context) error { return ctx.Error(http.StatusConflict, "already exists") }, http.StatusConflict, "already exists"},
+		{"client error without message", func(ctx Context) error { return erm.RequiredError("name", "") }, http.StatusBadRequest, "Bad Request"},
+		{"server error hides message", func(ctx Context) error { return erm.Internal("db password is wrong", nil) }, http.StatusInternalServerError, "Something went wrong"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := NewMux()
+			mux.Get("", "/error", tt.handler)
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest("GET", "/error", nil))
+
+			if rec.Code != tt.expectedCode {
+				t.Errorf("Expected status %d, got %d", tt.expectedCode, rec.Code)
+			}
+			if body := strings.TrimSpace(rec.Body.String()); body != tt.expectedBody {
+				t.Errorf("Expected body %q, got %q", tt.expectedBody, body)
+			}
+		})
+	}
+}
+
+func TestMux_CaseInsensitive(t *testing.T) {
+	newMux := func(enabled, global bool) *Mux {
+		mux := NewMux()
+		mux.CaseInsensitive(enabled)
+		if global {
+			mux.GlobalMiddleware(func(next HandlerFunc) HandlerFunc { return next })
+		}
+		mux.Get("", "/users/{id}", func(ctx Context) error {
+			return ctx.String(http.StatusOK, ctx.Path()+" id="+ctx.Param("id"))
+		})
+		mux.Get("", "/files/{path...}", func(ctx Context) error {
+			return ctx.String(http.StatusOK, ctx.Param("path"))
+		})
+		return mux
+	}
+
+	tests := []struct {
+		name         string
+		enabled      bool
+		global       bool
+		path         string
+		expectedCode int
+		expectedBody string
+	}{
+		{"disabled rejects other case", false, false, "/Users/42", http.StatusNotFound, ""},
+		{"lower case", true, false, "/users/42", http.StatusOK, "/users/42 id=42"},
+		{"mixed case keeps path and params", true, false, "/Users/AbC", http.StatusOK, "/Users/AbC id=AbC"},
+		{"upper case", true, false, "/USERS/42", http.StatusOK, "/USERS/42 id=42"},
+		{"remaining wildcard", true, false, "/Files/Docs/ReadMe.md", http.StatusOK, "Docs/ReadMe.md"},
+		{"with global middleware", true, true, "/Users/AbC", http.StatusOK, "/Users/AbC id=AbC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			newMux(tt.enabled, tt.global).ServeHTTP(rec, httptest.NewRequest("GET", tt.path, nil))
+
+			if rec.Code != tt.expectedCode {
+				t.Errorf("Expected status %d, got %d", tt.expectedCode, rec.Code)
+			}
+			if tt.expectedBody != "" && rec.Body.String() != tt.expectedBody {
+				t.Errorf("Expected body %q, got %q", tt.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestMux_EscapeHTML(t *testing.T) {
+	mux := NewMux()
+	mux.EscapeHTML(false)
+	mux.Get("", "/link", func(ctx Context) error {
+		return ctx.JSON(http.StatusOK, map[string]string{"next": "/items?page=2&size=10"})
+	})
+
+	req := httptest.NewRequest("GET", "/link", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	expected := `{"next":"/items?page=2&size=10"}`
+	if got := strings.TrimSpace(rec.Body.String()); got != expected {
+		t.Errorf("Expected body %s, got %s", expected, got)
+	}
+}
+
+func TestMux_DefaultContentType(t *testing.T) {
+	mux := NewMux()
+	mux.DefaultContentType(MIMEApplicationJSONCharsetUTF8)
+
+	mux.Get("", "/raw", func(ctx Context) error {
+		_, err := ctx.Response().Write([]byte(`{"ok":true}`))
+		return err
+	})
+	mux.Get("", "/text", func(ctx Context) error {
+		return ctx.String(http.StatusOK, "plain")
+	})
+	mux.Get("", "/custom", func(ctx Context) error {
+		ctx.SetHeader(HeaderContentType, MIMEApplicationXML)
+		ctx.WriteHeader(http.StatusOK)
+		return nil
+	})
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "/raw", expected: MIMEApplicationJSONCharsetUTF8},
+		{path: "/text", expected: MIMETextPlain},
+		{path: "/custom", expected: MIMEApplicationXML},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if got := rec.Header().Get(HeaderContentType); got != tt.expected {
+				t.Errorf("Expected Content-Type %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestMux_BeforeAfterHooks(t *testing.T) {
+	mux := NewMux()
+
+	var events []string
+	var afterErr error
+	mux.Get("", "/ok", func(ctx Context) error {
+		events = append(events, "handler")
+		return ctx.NoContent(http.StatusNoContent)
+	})
+	mux.Get("", "/fail", func(ctx Context) error {
+		events = append(events, "handler")
+		return errors.New("handler failed")
+	})
+	mux.ErrorHandler(func(ctx Context, err error) {
+		events = append(events, "error-handler")
+		_ = ctx.String(http.StatusInternalServerError, err.Error())
+	})
+
+	// Registered after the routes on purpose: hooks apply to all routes.
+	mux.Before(func(ctx Context) error {
+		events = append(events, "before")
+		if ctx.GetHeader("X-Block") != "" {
+			return erm.Forbidden("blocked", nil)
+		}
+		return nil
+	})
+	mux.Before(func(ctx Context) error {
+		events = append(events, "before-2")
+		return nil
+	})
+	mux.After(func(ctx Context, err error) {
+		events = append(events, "after")
+		afterErr = err
+	})
+
+	tests := []struct {
+		name       string
+		path       string
+		block      bool
+		wantEvents []string
+		wantErr    string
+	}{
+		{
+			name:       "success",
+			path:       "/ok",
+			wantEvents: []string{"before", "before-2", "handler", "after"},
+		},
+		{
+			name:       "handler error",
+			path:       "/fail",
+			wantEvents: []string{"before", "before-2", "handler", "error-handler", "after"},
+			wantErr:    "handler failed",
+		},
+		{
+			name:       "before hook error skips handler",
+			path:       "/ok",
+			block:      true,
+			wantEvents: []string{"before", "error-handler", "after"},
+			wantErr:    "blocked",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events = nil
+			afterErr = nil
+
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.block {
+				req.Header.Set("X-Block", "1")
+			}
+			mux.ServeHTTP(httptest.NewRecorder(), req)
+
+			if strings.Join(events, ",") != strings.Join(tt.wantEvents, ",") {
+				t.Errorf("Expected events %v, got %v", tt.wantEvents, events)
+			}
+			if tt.wantErr == "" && afterErr != nil {
+				t.Errorf("Expected nil error in After hook, got %v", afterErr)
+			}
+			if tt.wantErr != "" && (afterErr == nil || afterErr.Error() != tt.wantErr) {
+				t.Errorf("Expected error %q in After hook, got %v", tt.wantErr, afterErr)
+			}
+		})
+	}
+
+	t.Run("panicking hooks are recovered", func(t *testing.T) {
+		mux := NewMux()
+		handled := false
+		mux.Get("", "/ok", func(ctx Context) error {
+			handled = true
+			return nil
+		})
+		mux.Before(func(ctx Context) error { panic("before boom") })
+		mux.After(func(ctx Context, err error) { panic("after boom") })
+		var afterErr error
+		mux.After(func(ctx Context, err error) { afterErr = err })
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest("GET", "/ok", nil))
+
+		if handled {
+			t.Error("Expected handler to be skipped after Before hook panic")
+		}
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("Expected status 500, got %d", rec.Code)
+		}
+		if erm.Status(afterErr) != http.StatusInternalServerError {
+			t.Errorf("Expected 500 error in later After hook, got %v", afterErr)
+		}
+	})
+}
+
+func TestMux_Mount(t *testing.T) {
+	mux := NewMux()
+	mux.Mount("/admin", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("admin:" + r.URL.Path))
+	}))
+	mux.Get("", "/admin-api", func(ctx Context) error {
+		return ctx.String(http.StatusOK, "api")
+	})
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{path: "/admin/users", wantStatus: http.StatusOK, wantBody: "admin:/users"},
+		{path: "/admin/", wantStatus: http.StatusOK, wantBody: "admin:/"},
+		{path: "/admin", wantStatus: http.StatusTemporaryRedirect},
+		{path: "/admin-api", wantStatus: http.StatusOK, wantBody: "api"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("Expected body %q, got %q", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestMux_ErrorHandlerFallback(t *testing.T) {
+	t.Run("skips error handler when response is committed", func(t *testing.T) {
+		mux := NewMux()
+		called := false
+		mux.ErrorHandler(func(ctx Context, err error) {
+			called = true
+			_ = ctx.JSON(500, map[string]string{"error": err.Error()})
+		})
+		mux.Get("", "/partial", func(ctx Context) error {
+			_ = ctx.String(http.StatusOK, "partial")
+			return errors.New("failed mid-response")
+		})
+
+		req := httptest.NewRequest("GET", "/partial", nil)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		if called {
+			t.Error("Expected error handler to be skipped")
+		}
+		if rec.Code != http.StatusOK || rec.Body.String() != "partial" {
+			t.Errorf("Expected untouched partial response, got %d %q", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("recovers panicking error handler", func(t *testing.T) {
+		mux := NewMux()
+		mux.ErrorHandler(func(ctx Context, err error) {
+			panic("error handler bug")
+		})
+		mux.Get("", "/error", func(ctx Context) error {
+			return errors.New("test error")
+		})
+
+		req := httptest.NewRequest("GET", "/error", nil)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("Expected status 500, got %d", rec.Code)
+		}
+		if strings.TrimSpace(rec.Body.String()) != http.StatusText(http.StatusInternalServerError) {
+			t.Errorf("Unexpected body %q", rec.Body.String())
+		}
+	})
+
+	t.Run("keeps response written before error handler panicked", func(t *testing.T) {
+		mux := NewMux()
+		mux.ErrorHandler(func(ctx Context, err error) {
+			ctx.WriteHeader(http.StatusBadGateway)
+			panic("error handler bug")
+		})
+		mux.Get("", "/error", func(ctx Context) error {
+			return errors.New("test error")
+		})
+
+		req := httptest.NewRequest("GET", "/error", nil)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusBadGateway {
+			t.Errorf("Expected status 502, got %d", rec.Code)
+		}
+		if rec.Body.Len() != 0 {
+			t.Errorf("Expected no fallback body, got %q", rec.Body.String())
+		}
+	})
+}
+
+func TestMux_RecoverPanics(t *testing.T) {
+	panicking := func(ctx Context) error { panic("handler bug") }
+	panickingMiddleware := func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error { panic("middleware bug") }
+	}
+
+	tests := []struct {
+		name  string
+		setup func(mux *Mux)
+	}{
+		{"handler", func(mux *Mux) {
+			mux.Get("", "/panic", panicking)
+		}},
+		{"route middleware", func(mux *Mux) {
+			mux.Middleware(panickingMiddleware)
+			mux.Get("", "/panic", func(ctx Context) error { return nil })
+		}},
+		{"global middleware", func(mux *Mux) {
+			mux.GlobalMiddleware(panickingMiddleware)
+			mux.Get("", "/panic", func(ctx Context) error { return nil })
+		}},
+		{"handler behind global middleware", func(mux *Mux) {
+			mux.GlobalMiddleware(func(next HandlerFunc) HandlerFunc { return next })
+			mux.Get("", "/panic", panicking)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := NewMux()
+			var handled error
+			mux.ErrorHandler(func(ctx Context, err error) {
+				handled = err
+				_ = ctx.String(erm.Status(err), erm.Message(err))
+			})
+			tt.setup(mux)
+
+			rec := httptest.NewRecorder()
+			captureLogs(t, func() {
+				mux.ServeHTTP(rec, httptest.NewRequest("GET", "/panic", nil))
+			})
+
+			if handled == nil {
+				t.Fatal("Expected the panic to reach the error handler")
+			}
+			if erm.Status(handled) != http.StatusInternalServerError {
+				t.Errorf("Expected a 500 error, got %d", erm.Status(handled))
+			}
+			if rec.Code != http.StatusInternalServerError || rec.Body.String() != "panic recovered" {
+				t.Errorf("Unexpected response %d %q", rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		mux := NewMux()
+		mux.RecoverPanics(false)
+		mux.Get("", "/panic", panicking)
+
+		defer func() {
+			if rec := recover(); rec != "handler bug" {
+				t.Errorf("Expected the panic to propagate, got %v", rec)
+			}
+		}()
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/panic", nil))
+	})
+
+	for _, global := range []bool{false, true} {
+		t.Run(fmt.Sprintf("ErrAbortHandler propagates (global %v)", global), func(t *testing.T) {
+			mux := NewMux()
+			handled := false
+			mux.ErrorHandler(func(ctx Context, err error) { handled = true })
+			abort := func(next HandlerFunc) HandlerFunc {
+				return func(ctx Context) error { panic(http.ErrAbortHandler) }
+			}
+			if global {
+				mux.GlobalMiddleware(abort)
+				mux.Get("", "/panic", func(ctx Context) error { return nil })
+			} else {
+				mux.Get("", "/panic", abort(nil))
+			}
+
+			var recovered interface{}
+			logs := captureLogs(t, func() {
+				defer func() { recovered = recover() }()
+				mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/panic", nil))
+			})
+
+			if recovered != http.ErrAbortHandler {
+				t.Errorf("Expected http.ErrAbortHandler to propagate, got %v", recovered)
+			}
+			if handled {
+				t.Error("Expected the error handler not to run")
+			}
+			if strings.Contains(logs, "recovered from panic") {
+				t.Errorf("Expected no panic log, got %q", logs)
+			}
+		})
+	}
+}
+
 func TestMux_NoErrorHandling(t *testing.T) {
 	mux := NewMux()
 
@@ -474,6 +930,46 @@ func TestMux_NamedRoutes_BasicFunctionality(t *testing.T) {
 	}
 }
 
+func TestMux_RoutePatternAndName(t *testing.T) {
+	mux := NewMux()
+	handler := func(ctx Context) error {
+		return ctx.String(200, ctx.RoutePattern()+"|"+ctx.RouteName())
+	}
+	mux.Get("user", "/users/{id}", handler)
+	mux.Post("", "/users", handler)
+	mux.HandleFunc("GET /plain/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(NewHttpContext(w, r).RoutePattern()))
+	})
+
+	tests := []struct {
+		method       string
+		path         string
+		expectedBody string
+	}{
+		{"GET", "/users/42", "/users/{id}|user"},
+		{"POST", "/users", "/users|"},
+		{"GET", "/plain/7", "/plain/{id}"},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s %s", test.method, test.path), func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.path, nil)
+			rec := httptest.NewRecorder()
+
+			mux.ServeHTTP(rec, req)
+
+			if rec.Body.String() != test.expectedBody {
+				t.Errorf("Expected body '%s', got '%s'", test.expectedBody, rec.Body.String())
+			}
+		})
+	}
+
+	ctx := NewHttpContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	if ctx.RoutePattern() != "" || ctx.RouteName() != "" {
+		t.Errorf("Expected empty route for unrouted request, got %q %q", ctx.RoutePattern(), ctx.RouteName())
+	}
+}
+
 func TestMux_NamedRoutes_AllMethods(t *testing.T) {
 	mux := NewMux()
 
@@ -790,6 +1286,62 @@ func TestMux_Reverse_SameNameDifferentMethods(t *testing.T) {
 	}
 }
 
+func TestMux_Reverse_Wildcards(t *testing.T) {
+	mux := NewMux()
+	handler := func(ctx Context) error { return nil }
+	mux.Get("files", "/files/{path...}", handler)
+	mux.Get("home", "/{$}", handler)
+
+	if url, err := mux.Reverse("files", map[string]string{"path": "a/b.txt"}); err != nil || url != "/files/a/b.txt" {
+		t.Errorf("Expected '/files/a/b.txt', got '%s' (%v)", url, err)
+	}
+	if url, err := mux.Reverse("home", nil); err != nil || url != "/" {
+		t.Errorf("Expected '/', got '%s' (%v)", url, err)
+	}
+	if _, err := mux.Reverse("files", nil); err == nil {
+		t.Error("Expected error for missing wildcard parameter")
+	}
+}
+
+func TestMux_ValidateRoutes(t *testing.T) {
+	handler := func(ctx Context) error { return nil }
+
+	t.Run("valid routes", func(t *testing.T) {
+		mux := NewMux()
+		mux.Get("users", "/users", handler)
+		mux.Get("user", "/users/{id}", handler)
+		mux.Get("post", "/users/{id}/posts/{postID}", handler)
+		mux.Get("files", "example.com/files/{path...}", handler)
+		mux.Get("home", "/{$}", handler)
+		mux.Get("", "/unnamed/{id}", handler)
+
+		if err := mux.ValidateRoutes(); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("invalid routes", func(t *testing.T) {
+		mux := NewMux()
+		mux.Get("users", "/users", handler)
+		// Bypass ServeMux registration, which panics on malformed patterns
+		mux.routes["empty"] = Route{Name: "empty", Pattern: "/users/{}"}
+		mux.routes["unbalanced"] = Route{Name: "unbalanced", Pattern: "/users/{id"}
+
+		err := mux.ValidateRoutes()
+		if err == nil {
+			t.Fatal("Expected error for malformed routes")
+		}
+		for _, name := range []string{`"empty"`, `"unbalanced"`} {
+			if !strings.Contains(err.Error(), name) {
+				t.Errorf("Expected error to mention route %s, got %v", name, err)
+			}
+		}
+		if strings.Contains(err.Error(), `"users"`) {
+			t.Errorf("Expected valid route not to be reported, got %v", err)
+		}
+	})
+}
+
 func TestMux_Reverse_Integration(t *testing.T) {
 	mux := NewMux()
 
@@ -1047,6 +1599,160 @@ func TestMux_Middleware_ErrorHandling(t *testing.T) {
 	}
 }
 
+func TestStack(t *testing.T) {
+	var order []string
+	tag := func(label string) HandlerFuncMiddleware {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(ctx Context) error {
+				order = append(order, label)
+				return next(ctx)
+			}
+		}
+	}
+
+	stack := Stack(tag("a"), nil, Stack(tag("b"), tag("c")))
+	handler := func(ctx Context) error {
+		order = append(order, "handler")
+		return ctx.NoContent(http.StatusNoContent)
+	}
+
+	for _, mux := range []*Mux{NewMux(), NewMux()} {
+		order = nil
+		mux.Middleware(stack)
+		mux.Middleware(tag("d"))
+		mux.Get("", "/", handler)
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+		if got := strings.Join(order, ","); got != "a,b,c,d,handler" {
+			t.Errorf("Expected order a,b,c,d,handler, got %s", got)
+		}
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("Expected status 204, got %d", rec.Code)
+		}
+	}
+
+	order = nil
+	if err := Stack()(handler)(NewTestContext(httptest.NewRequest("GET", "/", nil), httptest.NewRecorder())); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := strings.Join(order, ","); got != "handler" {
+		t.Errorf("Expected empty stack to call the handler only, got %s", got)
+	}
+}
+
+func TestWhen(t *testing.T) {
+	header := func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			ctx.SetHeader("X-Applied", "yes")
+			return next(ctx)
+		}
+	}
+
+	mux := NewMux()
+	mux.Middleware(When(func(ctx Context) bool {
+		return strings.HasPrefix(ctx.Path(), "/api/")
+	}, header))
+	handler := func(ctx Context) error { return ctx.String(http.StatusOK, "ok") }
+	mux.Get("", "/api/users", handler)
+	mux.Get("", "/static/app.js", handler)
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/api/users", "yes"},
+		{"/static/app.js", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest("GET", tt.path, nil))
+
+			if rec.Code != http.StatusOK || rec.Body.String() != "ok" {
+				t.Errorf("Expected handler to run, got %d %q", rec.Code, rec.Body.String())
+			}
+			if got := rec.Header().Get("X-Applied"); got != tt.expected {
+				t.Errorf("Expected X-Applied %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestMux_GlobalMiddleware(t *testing.T) {
+	var calls []string
+	record := func(label string) HandlerFuncMiddleware {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(ctx Context) error {
+				calls = append(calls, label+" "+ctx.Path())
+				return next(ctx)
+			}
+		}
+	}
+
+	mux := NewMux()
+	mux.Middleware(record("route"))
+	mux.Get("user", "/users/{id}", func(ctx Context) error {
+		return ctx.String(200, fmt.Sprint(ctx.Get("global"), " ", ctx.Param("id")))
+	})
+	mux.HandleFunc("GET /plain", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	// Added after the routes, still applies
+	mux.GlobalMiddleware(record("global"))
+	mux.GlobalMiddleware(func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			if ctx.GetHeader("Authorization") == "deny" {
+				return erm.Unauthorized("denied", nil)
+			}
+			ctx.Set("global", "set")
+			return next(ctx)
+		}
+	})
+	mux.ErrorHandler(func(ctx Context, err error) {
+		_ = ctx.String(erm.Status(err), erm.Message(err))
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		auth           string
+		expectedStatus int
+		expectedBody   string
+		expectedCalls  []string
+	}{
+		{"matched route", "/users/42", "", 200, "set 42", []string{"global /users/42", "route /users/42"}},
+		{"unmatched path", "/missing", "", 404, "404 page not found\n", []string{"global /missing"}},
+		{"traditional handler", "/plain", "", 204, "", []string{"global /plain"}},
+		{"global error", "/users/42", "deny", 401, "denied", []string{"global /users/42"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = nil
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if rec.Body.String() != tt.expectedBody {
+				t.Errorf("Expected body %q, got %q", tt.expectedBody, rec.Body.String())
+			}
+			if strings.Join(calls, ",") != strings.Join(tt.expectedCalls, ",") {
+				t.Errorf("Expected calls %v, got %v", tt.expectedCalls, calls)
+			}
+		})
+	}
+}
+
 func TestMux_Middleware_ContextValues(t *testing.T) {
 	mux := NewMux()
 
@@ -1187,3 +1893,109 @@ func TestMux_Middleware_Integration(t *testing.T) {
 		}
 	})
 }
+
+func TestMux_Health(t *testing.T) {
+	okCheck := func(ctx context.Context) error { return nil }
+	failCheck := func(ctx context.Context) error { return errors.New("connection refused") }
+
+	tests := []struct {
+		name           string
+		checks         []HealthCheck
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:           "no checks",
+			checks:         nil,
+			expectedStatus: http.StatusOK,
+			expectedBody:   map[string]interface{}{"status": "ok"},
+		},
+		{
+			name:           "all checks pass",
+			checks:         []HealthCheck{okCheck, nil, okCheck},
+			expectedStatus: http.StatusOK,
+			expectedBody:   map[string]interface{}{"status": "ok"},
+		},
+		{
+			name:           "one check fails",
+			checks:         []HealthCheck{okCheck, NamedHealthCheck("db", failCheck)},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody: map[string]interface{}{
+				"status": "unavailable",
+				"failed": []interface{}{"db"},
+			},
+		},
+		{
+			name:           "multiple checks fail",
+			checks:         []HealthCheck{NamedHealthCheck("db", failCheck), NamedHealthCheck("cache", failCheck)},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody: map[string]interface{}{
+				"status": "unavailable",
+				"failed": []interface{}{"db", "cache"},
+			},
+		},
+		{
+			name:           "unnamed check fails",
+			checks:         []HealthCheck{okCheck, failCheck},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody: map[string]interface{}{
+				"status": "unavailable",
+				"failed": []interface{}{"check 2"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := NewMux()
+			mux.Health("/healthz", tt.checks...)
+
+			req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if ct := rec.Header().Get(HeaderContentType); ct != MIMEApplicationJSON {
+				t.Errorf("Expected Content-Type %q, got %q", MIMEApplicationJSON, ct)
+			}
+
+			var body map[string]interface{}
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("Failed to decode JSON response: %v", err)
+			}
+			if fmt.Sprint(body) != fmt.Sprint(tt.expectedBody) {
+				t.Errorf("Expected body %v, got %v", tt.expectedBody, body)
+			}
+			if strings.Contains(fmt.Sprint(body), "connection refused") {
+				t.Errorf("Expected check errors not to be exposed, got %v", body)
+			}
+		})
+	}
+
+	t.Run("named check error keeps cause", func(t *testing.T) {
+		err := NamedHealthCheck("db", failCheck)(context.Background())
+		if err == nil || err.Error() != "db: connection refused" {
+			t.Errorf("Expected prefixed error, got %v", err)
+		}
+	})
+
+	t.Run("check receives request context", func(t *testing.T) {
+		type ctxKey struct{}
+		var got interface{}
+		mux := NewMux()
+		mux.Health("/readyz", func(ctx context.Context) error {
+			got = ctx.Value(ctxKey{})
+			return nil
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+		req = req.WithContext(context.WithValue(req.Context(), ctxKey{}, "marker"))
+		mux.ServeHTTP(httptest.NewRecorder(), req)
+
+		if got != "marker" {
+			t.Errorf("Expected check to receive request context, got %v", got)
+		}
+	})
+}
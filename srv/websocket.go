@@ -0,0 +1,204 @@
+package srv
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/c3p0-box/utils/erm"
+)
+
+// websocketGUID is the fixed GUID used to derive Sec-WebSocket-Accept (RFC 6455, section 1.3).
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// ErrWebSocketHandshake is wrapped by the error returned from Upgrade when the
+// request is not a valid WebSocket opening handshake.
+var ErrWebSocketHandshake = errors.New("invalid websocket handshake")
+
+// WebSocketConfig defines the configuration for UpgradeWithConfig.
+type WebSocketConfig struct {
+	// Protocols lists the supported subprotocols in order of preference.
+	// The first one that the client also offers in Sec-WebSocket-Protocol
+	// is selected.
+	//
+	// Optional. Default value nil (no subprotocol).
+	Protocols []string
+
+	// CheckOrigin reports whether the handshake request may be accepted
+	// based on its Origin header. Browsers send cookies with cross-site
+	// WebSocket handshakes, so accepting any origin would let other sites
+	// open authenticated connections on behalf of the user.
+	//
+	// Optional. Default value SameOrigin.
+	CheckOrigin func(r *http.Request) bool
+}
+
+// SameOrigin is the default WebSocketConfig.CheckOrigin. It accepts requests
+// without an Origin header (non-browser clients) and requests whose Origin
+// host matches the request's Host, compared case-insensitively.
+func SameOrigin(r *http.Request) bool {
+	origin := r.Header.Get(HeaderOrigin)
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+// Hijack takes over the underlying network connection, e.g. to implement a
+// protocol other than HTTP. After a successful call the HTTP server no longer
+// manages the connection: the caller is responsible for closing it, and the
+// Context's response methods must not be used.
+//
+// It works through wrapped response writers that implement Unwrap, and
+// returns an error if the connection cannot be hijacked (for example with
+// HTTP/2).
+func (c *HttpContext) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(c.Response()).Hijack()
+}
+
+// Upgrade performs the server side of the WebSocket opening handshake
+// (RFC 6455) and returns the hijacked connection. It only implements the
+// handshake; framing is left to the caller, so any WebSocket library that can
+// work on an established net.Conn can be plugged in.
+//
+// Because Upgrade is called from a regular handler, middleware registered on
+// the Mux (authentication, sessions, logging) runs before the upgrade. Note
+// that SessionMiddleware cannot set a cookie after the upgrade; save the
+// session before calling Upgrade if needed.
+//
+// If protocols are given, they are taken in order of preference: the first
+// one that the client also offers in Sec-WebSocket-Protocol is selected and
+// echoed in the response.
+//
+// Cross-origin handshakes are rejected with 403 Forbidden (see SameOrigin);
+// use UpgradeWithConfig to allow other origins. Invalid handshakes are
+// rejected with a 400 Bad Request erm.Error wrapping ErrWebSocketHandshake,
+// or 426 Upgrade Required for unsupported protocol versions. In all cases
+// nothing has been written and the error can be returned from the handler.
+//
+// Example:
+//
+//	mux.Get("ws", "/ws", func(ctx srv.Context) error {
+//		conn, rw, err := ctx.Upgrade("chat.v1")
+//		if err != nil {
+//			return err
+//		}
+//		defer conn.Close()
+//		return serveWebSocket(conn, rw) // read/write frames with your WS library
+//	})
+func (c *HttpContext) Upgrade(protocols ...string) (net.Conn, *bufio.ReadWriter, error) {
+	return c.UpgradeWithConfig(WebSocketConfig{Protocols: protocols})
+}
+
+// UpgradeWithConfig is like Upgrade with a custom configuration, e.g. to
+// accept handshakes from other trusted origins.
+//
+// Example:
+//
+//	conn, rw, err := ctx.(*srv.HttpContext).UpgradeWithConfig(srv.WebSocketConfig{
+//		CheckOrigin: func(r *http.Request) bool {
+//			return r.Header.Get("Origin") == "https://app.example.com"
+//		},
+//	})
+func (c *HttpContext) UpgradeWithConfig(config WebSocketConfig) (net.Conn, *bufio.ReadWriter, error) {
+	if config.CheckOrigin == nil {
+		config.CheckOrigin = SameOrigin
+	}
+	req := c.Request()
+
+	if req.Method != http.MethodGet {
+		return nil, nil, erm.BadRequest("websocket upgrade requires GET", ErrWebSocketHandshake)
+	}
+	if !headerContainsToken(req.Header, HeaderConnection, "upgrade") ||
+		!headerContainsToken(req.Header, HeaderUpgrade, "websocket") {
+		return nil, nil, erm.BadRequest("missing websocket upgrade headers", ErrWebSocketHandshake)
+	}
+	if req.Header.Get(HeaderSecWebSocketVersion) != "13" {
+		c.SetHeader(HeaderSecWebSocketVersion, "13")
+		return nil, nil, erm.New(http.StatusUpgradeRequired, "unsupported websocket version", ErrWebSocketHandshake)
+	}
+	key := req.Header.Get(HeaderSecWebSocketKey)
+	if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 16 {
+		return nil, nil, erm.BadRequest("invalid websocket key", ErrWebSocketHandshake)
+	}
+	if !config.CheckOrigin(req) {
+		return nil, nil, erm.Forbidden("websocket origin not allowed", ErrWebSocketHandshake)
+	}
+
+	protocol := selectWebSocketProtocol(req.Header, config.Protocols)
+
+	conn, rw, err := c.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var response strings.Builder
+	response.WriteString("HTTP/1.1 101 Switching Protocols\r\n")
+	response.WriteString("Upgrade: websocket\r\n")
+	response.WriteString("Connection: Upgrade\r\n")
+	response.WriteString(HeaderSecWebSocketAccept + ": " + websocketAccept(key) + "\r\n")
+	if protocol != "" {
+		response.WriteString(HeaderSecWebSocketProtocol + ": " + protocol + "\r\n")
+	}
+	response.WriteString("\r\n")
+
+	if _, err := rw.WriteString(response.String()); err != nil {
+		_ = conn.Close()
+		return nil, nil, err
+	}
+	if err := rw.Flush(); err != nil {
+		_ = conn.Close()
+		return nil, nil, err
+	}
+
+	return conn, rw, nil
+}
+
+// websocketAccept computes the Sec-WebSocket-Accept value for a client key.
+func websocketAccept(key string) string {
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// selectWebSocketProtocol returns the first supported protocol, in the
+// server's order of preference, that the client offered, or "" if there is
+// none.
+func selectWebSocketProtocol(header http.Header, supported []string) string {
+	if len(supported) == 0 {
+		return ""
+	}
+	offered := make(map[string]bool)
+	for _, value := range header.Values(HeaderSecWebSocketProtocol) {
+		for _, protocol := range strings.Split(value, ",") {
+			offered[strings.TrimSpace(protocol)] = true
+		}
+	}
+	for _, protocol := range supported {
+		if offered[protocol] {
+			return protocol
+		}
+	}
+	return ""
+}
+
+// headerContainsToken reports whether the comma-separated header contains
+// token, compared case-insensitively.
+func headerContainsToken(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package vix
+
+import "strings"
+
+// EmailProviderRule describes how the local part of addresses at a mail
+// provider can be canonicalized without changing the mailbox they reach.
+type EmailProviderRule struct {
+	// Lowercase lowercases the local part, for providers whose mailboxes are
+	// case-insensitive.
+	Lowercase bool
+	// StripDots removes all "." from the local part.
+	StripDots bool
+	// StripTags removes everything from the first TagSeparator in the local
+	// part, e.g. "john+news" becomes "john".
+	StripTags bool
+	// TagSeparator marks the start of a sub-address tag. Defaults to "+".
+	TagSeparator string
+	// Domain replaces the domain, for providers with aliases such as
+	// googlemail.com. Empty keeps the original domain.
+	Domain string
+}
+
+// EmailNormalizationConfig configures NormalizeEmail.
+type EmailNormalizationConfig struct {
+	// Providers maps lowercase domains to their normalization rule. Domains
+	// not listed only have their domain lowercased.
+	Providers map[string]EmailProviderRule
+	// LowercaseLocal lowercases the local part for every domain, not only
+	// for providers whose rule sets Lowercase. Local parts are case-sensitive
+	// per RFC 5321, although few providers treat them that way.
+	LowercaseLocal bool
+}
+
+// DefaultEmailNormalizationConfig lowercases the local part and strips dots
+// and tags for Gmail, and lowercases and strips tags for other large
+// providers known to support "+" sub-addressing. Local parts at other
+// domains are left as is.
+var DefaultEmailNormalizationConfig = EmailNormalizationConfig{
+	Providers: map[string]EmailProviderRule{
+		"gmail.com":      {Lowercase: true, StripDots: true, StripTags: true, Domain: "gmail.com"},
+		"googlemail.com": {Lowercase: true, StripDots: true, StripTags: true, Domain: "gmail.com"},
+		"outlook.com":    {Lowercase: true, StripTags: true},
+		"hotmail.com":    {Lowercase: true, StripTags: true},
+		"live.com":       {Lowercase: true, StripTags: true},
+		"icloud.com":     {Lowercase: true, StripTags: true},
+		"fastmail.com":   {Lowercase: true, StripTags: true},
+		"proton.me":      {Lowercase: true, StripTags: true},
+		"protonmail.com": {Lowercase: true, StripTags: true},
+	},
+	LowercaseLocal: false,
+}
+
+// normalizeEmail returns the canonical form of email according to config.
+// Values without exactly one "@" or with an empty local part or domain are
+// returned unchanged.
+func normalizeEmail(email string, config EmailNormalizationConfig) string {
+	email = strings.TrimSpace(email)
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || local == "" || domain == "" || strings.Contains(domain, "@") {
+		return email
+	}
+
+	domain = strings.ToLower(domain)
+	if config.LowercaseLocal {
+		local = strings.ToLower(local)
+	}
+
+	if rule, ok := config.Providers[domain]; ok {
+		if rule.Lowercase {
+			local = strings.ToLower(local)
+		}
+		if rule.StripTags {
+			separator := rule.TagSeparator
+			if separator == "" {
+				separator = "+"
+			}
+			if i := strings.Index(local, separator); i > 0 {
+				local = local[:i]
+			}
+		}
+		if rule.StripDots {
+			local = strings.ReplaceAll(local, ".", "")
+		}
+		if rule.Domain != "" {
+			domain = rule.Domain
+		}
+	}
+
+	return local + "@" + domain
+}
@@ -0,0 +1,152 @@
+package vix
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/c3p0-box/utils/erm"
+	"golang.org/x/text/language"
+)
+
+// =============================================================================
+// Enum Validator
+// =============================================================================
+
+// EnumValidator validates that a value is one of the defined constants of an
+// enum type.
+type EnumValidator[T comparable] struct {
+	*BaseValidator
+	value   T
+	allowed []T
+	checked bool
+}
+
+// Enum creates a new EnumValidator that checks that value is one of allowed.
+// It is In for any comparable type, with its own message key
+// (validation.enum) so that enum errors can be worded separately. On failure
+// the allowed values are also available as the "allowed" param ([]T).
+//
+// The membership rule runs when the chain is terminated (Validate, IsValid,
+// Result or ValidateInto), so Not, When, Unless and Optional apply to it
+// regardless of where they appear in the chain.
+//
+// Example:
+//
+//	type Status int
+//
+//	const (
+//		StatusDraft Status = iota
+//		StatusPublished
+//		StatusArchived
+//	)
+//
+//	var statuses = []Status{StatusDraft, StatusPublished, StatusArchived}
+//
+//	err := vix.Enum(post.Status, "status", statuses...).Validate()
+func Enum[T comparable](value T, fieldName string, allowed ...T) *EnumValidator[T] {
+	return &EnumValidator[T]{
+		BaseValidator: NewBaseValidator(value, fieldName),
+		value:         value,
+		allowed:       allowed,
+	}
+}
+
+// =============================================================================
+// Chain Methods
+// =============================================================================
+
+// Not negates the membership rule: the value must not be one of allowed.
+func (ev *EnumValidator[T]) Not() *EnumValidator[T] {
+	ev.BaseValidator.Not()
+	return ev
+}
+
+// When adds a condition that must be true for validation to run.
+func (ev *EnumValidator[T]) When(condition func() bool) *EnumValidator[T] {
+	ev.BaseValidator.When(condition)
+	return ev
+}
+
+// Unless adds a condition that must be false for validation to run.
+func (ev *EnumValidator[T]) Unless(condition func() bool) *EnumValidator[T] {
+	ev.BaseValidator.Unless(condition)
+	return ev
+}
+
+// Optional skips validation if the value is the zero value of its type,
+// e.g. an enum field that was not set. Unlike the other validators, this
+// also covers named types such as `type Status int`.
+func (ev *EnumValidator[T]) Optional() *EnumValidator[T] {
+	var zero T
+	if ev.value == zero {
+		ev.BaseValidator.When(func() bool { return false })
+	}
+	return ev
+}
+
+// Locale sets the language of the validation messages.
+func (ev *EnumValidator[T]) Locale(tag language.Tag) *EnumValidator[T] {
+	ev.BaseValidator.Locale(tag)
+	return ev
+}
+
+// Custom validates using a custom validation function.
+func (ev *EnumValidator[T]) Custom(fn func(value interface{}, fieldName string) error) *EnumValidator[T] {
+	ev.BaseValidator.Custom(fn)
+	return ev
+}
+
+// =============================================================================
+// Terminal Methods
+// =============================================================================
+
+// Validate runs the membership rule and returns the validation result.
+func (ev *EnumValidator[T]) Validate() error {
+	ev.check()
+	return ev.BaseValidator.Validate()
+}
+
+// IsValid runs the membership rule and reports whether the chain passed.
+func (ev *EnumValidator[T]) IsValid() bool {
+	ev.check()
+	return ev.BaseValidator.IsValid()
+}
+
+// Result runs the membership rule and returns the full validation result.
+func (ev *EnumValidator[T]) Result() *ValidationResult {
+	ev.check()
+	return ev.BaseValidator.Result()
+}
+
+// ValidateInto runs the membership rule and appends the errors to result.
+func (ev *EnumValidator[T]) ValidateInto(result *ValidationResult) {
+	ev.check()
+	ev.BaseValidator.ValidateInto(result)
+}
+
+// check applies the membership rule once.
+func (ev *EnumValidator[T]) check() {
+	if ev.checked {
+		return
+	}
+	ev.checked = true
+
+	if slices.Contains(ev.allowed, ev.value) == ev.negated {
+		ev.addValidationError(erm.MsgEnum, map[string]interface{}{
+			"values":  formatEnumValues(ev.allowed),
+			"allowed": ev.allowed,
+		})
+	}
+	ev.negated = false
+}
+
+// formatEnumValues formats enum values for error messages. Types with a
+// String method (e.g. generated by stringer) are listed by name.
+func formatEnumValues[T comparable](values []T) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprint(v)
+	}
+	return strings.Join(parts, ", ")
+}
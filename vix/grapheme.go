@@ -0,0 +1,140 @@
+package vix
+
+import "unicode"
+
+// =============================================================================
+// Grapheme Clusters
+// =============================================================================
+
+// graphemeClass is the Grapheme_Cluster_Break property of a rune (UAX #29),
+// plus Extended_Pictographic for emoji ZWJ sequences.
+type graphemeClass int
+
+const (
+	gcOther graphemeClass = iota
+	gcCR
+	gcLF
+	gcControl
+	gcExtend
+	gcZWJ
+	gcRegionalIndicator
+	gcSpacingMark
+	gcL
+	gcV
+	gcT
+	gcLV
+	gcLVT
+	gcPictographic
+)
+
+// classifyGrapheme returns the grapheme break class of r. The Unicode tables
+// of the standard library lack Extended_Pictographic, so pictographs are
+// approximated by the emoji and symbol blocks, which covers emoji sequences.
+func classifyGrapheme(r rune) graphemeClass {
+	switch {
+	case r == '\r':
+		return gcCR
+	case r == '\n':
+		return gcLF
+	case r == 0x200D:
+		return gcZWJ
+	case r == 0x200C, r >= 0x1F3FB && r <= 0x1F3FF,
+		unicode.In(r, unicode.Mn, unicode.Me, unicode.Other_Grapheme_Extend):
+		return gcExtend
+	case unicode.In(r, unicode.Cc, unicode.Cf, unicode.Zl, unicode.Zp):
+		return gcControl
+	case r >= 0x1F1E6 && r <= 0x1F1FF:
+		return gcRegionalIndicator
+	case unicode.Is(unicode.Mc, r):
+		return gcSpacingMark
+	case r >= 0x1100 && r <= 0x115F, r >= 0xA960 && r <= 0xA97C:
+		return gcL
+	case r >= 0x1160 && r <= 0x11A7, r >= 0xD7B0 && r <= 0xD7C6:
+		return gcV
+	case r >= 0x11A8 && r <= 0x11FF, r >= 0xD7CB && r <= 0xD7FB:
+		return gcT
+	case r >= 0xAC00 && r <= 0xD7A3:
+		if (r-0xAC00)%28 == 0 {
+			return gcLV
+		}
+		return gcLVT
+	case isPictographic(r):
+		return gcPictographic
+	}
+	return gcOther
+}
+
+// isPictographic approximates the Extended_Pictographic property.
+func isPictographic(r rune) bool {
+	switch {
+	case r == 0x00A9, r == 0x00AE, r == 0x203C, r == 0x2049, r == 0x2122,
+		r == 0x2139, r == 0x3030, r == 0x303D, r == 0x3297, r == 0x3299:
+		return true
+	case r >= 0x2190 && r <= 0x21FF, r >= 0x2300 && r <= 0x23FF,
+		r >= 0x2600 && r <= 0x27BF, r >= 0x2B00 && r <= 0x2BFF,
+		r >= 0x1F000 && r <= 0x1FAFF, r >= 0x1FC00 && r <= 0x1FFFD:
+		return true
+	}
+	return false
+}
+
+// graphemeCount returns the number of user-perceived characters in s,
+// following the extended grapheme cluster rules of UAX #29: combining marks,
+// emoji modifiers and ZWJ sequences, flags and Hangul syllables each count
+// as one character.
+func graphemeCount(s string) int {
+	count := 0
+	prev := gcControl // forces a break before the first rune
+	pictographic := false
+	regionalIndicators := 0
+
+	for _, r := range s {
+		class := classifyGrapheme(r)
+		if graphemeBreak(prev, class, pictographic, regionalIndicators) {
+			count++
+		}
+
+		switch class {
+		case gcPictographic:
+			pictographic = true
+		case gcExtend, gcZWJ:
+		default:
+			pictographic = false
+		}
+		if class == gcRegionalIndicator {
+			regionalIndicators++
+		} else {
+			regionalIndicators = 0
+		}
+		prev = class
+	}
+	return count
+}
+
+// graphemeBreak reports whether there is a grapheme cluster boundary between
+// a rune of class prev and one of class next. pictographic reports whether
+// the runes before next match ExtPict Extend* (ZWJ), and regionalIndicators
+// is the number of regional indicators immediately before next.
+func graphemeBreak(prev, next graphemeClass, pictographic bool, regionalIndicators int) bool {
+	switch {
+	case prev == gcCR && next == gcLF: // GB3
+		return false
+	case prev == gcCR, prev == gcLF, prev == gcControl: // GB4
+		return true
+	case next == gcCR, next == gcLF, next == gcControl: // GB5
+		return true
+	case prev == gcL && (next == gcL || next == gcV || next == gcLV || next == gcLVT): // GB6
+		return false
+	case (prev == gcLV || prev == gcV) && (next == gcV || next == gcT): // GB7
+		return false
+	case (prev == gcLVT || prev == gcT) && next == gcT: // GB8
+		return false
+	case next == gcExtend, next == gcZWJ, next == gcSpacingMark: // GB9, GB9a
+		return false
+	case prev == gcZWJ && next == gcPictographic && pictographic: // GB11
+		return false
+	case prev == gcRegionalIndicator && next == gcRegionalIndicator: // GB12, GB13
+		return regionalIndicators%2 == 0
+	}
+	return true // GB999
+}
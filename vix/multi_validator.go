@@ -3,6 +3,7 @@ package vix
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/c3p0-box/utils/erm"
@@ -32,7 +33,7 @@ func NewValidationOrchestrator() *ValidationOrchestrator {
 	return &ValidationOrchestrator{
 		fieldResults: make(map[string]*ValidationResult),
 		fieldOrder:   make([]string, 0),
-		err:          erm.New(400, "", nil),
+		err:          erm.New(ValidationStatus(), "", nil),
 	}
 }
 
@@ -52,18 +53,18 @@ func (vo *ValidationOrchestrator) Is(validators ...Validator) *ValidationOrchest
 
 // In adds validations within a namespace. The namespace is prefixed to field names.
 func (vo *ValidationOrchestrator) In(namespace string, orchestrator *ValidationOrchestrator) *ValidationOrchestrator {
-	for fieldName, result := range orchestrator.fieldResults {
+	for _, fieldName := range orchestrator.fieldOrder {
 		namespacedField := namespace + "." + fieldName
-		vo.addResult(namespacedField, result)
+		vo.addResult(namespacedField, orchestrator.fieldResults[fieldName])
 	}
 	return vo
 }
 
 // InRow adds validations within an indexed namespace. The namespace and index are prefixed to field names.
 func (vo *ValidationOrchestrator) InRow(namespace string, index int, orchestrator *ValidationOrchestrator) *ValidationOrchestrator {
-	for fieldName, result := range orchestrator.fieldResults {
+	for _, fieldName := range orchestrator.fieldOrder {
 		namespacedField := fmt.Sprintf("%s[%d].%s", namespace, index, fieldName)
-		vo.addResult(namespacedField, result)
+		vo.addResult(namespacedField, orchestrator.fieldResults[fieldName])
 	}
 	return vo
 }
@@ -79,7 +80,7 @@ func (vo *ValidationOrchestrator) addResult(fieldName string, result *Validation
 // Valid returns true if all validations passed.
 func (vo *ValidationOrchestrator) Valid() bool {
 	// Clear old errors by creating a new container
-	vo.err = erm.New(400, "", nil)
+	vo.err = erm.New(ValidationStatus(), "", nil)
 
 	// Collect errors from all field results, preserving namespaced field names
 	for _, fieldName := range vo.fieldOrder {
@@ -120,6 +121,12 @@ func (vo *ValidationOrchestrator) ErrMap() map[string][]string {
 	return vo.err.ErrMap()
 }
 
+// FirstErrMap returns a map of field names to the first error message of
+// each field. Returns nil if all validations passed.
+func (vo *ValidationOrchestrator) FirstErrMap() map[string]string {
+	return firstErrors(vo.ErrMap())
+}
+
 // LocalizedErrMap returns a map of field names to localized error messages
 // for the specified language. This provides full internationalization support
 // while preserving the orchestrator's namespaced field structure.
@@ -181,3 +188,109 @@ func (vo *ValidationOrchestrator) String() string {
 
 	return fmt.Sprintf("ValidationOrchestrator: Invalid (%s)", strings.Join(messages, ", "))
 }
+
+// =============================================================================
+// Form Builder
+// =============================================================================
+
+// FormValidator collects field validators and combines their errors into a
+// single ValidationResult. It is the lightweight counterpart of
+// ValidationOrchestrator for validating a whole request when namespacing is
+// not needed.
+type FormValidator struct {
+	validators []Validator
+	rules      []formRule
+}
+
+// formRule is a cross-field requirement registered with RequiredIf or
+// RequiredUnless.
+type formRule struct {
+	field  string
+	other  string
+	value  interface{}
+	unless bool
+}
+
+// Form creates a new, empty FormValidator.
+//
+// Example:
+//
+//	result := vix.Form().
+//		Add(vix.String(req.Email, "email").Required().Email()).
+//		Add(vix.Int(req.Age, "age").Min(18)).
+//		Validate()
+//
+//	if !result.Valid() {
+//		return result.ErrMap() // one entry per invalid field
+//	}
+func Form() *FormValidator {
+	return &FormValidator{}
+}
+
+// Add adds a field validator to the form.
+func (fv *FormValidator) Add(validator Validator) *FormValidator {
+	fv.validators = append(fv.validators, validator)
+	return fv
+}
+
+// RequiredIf requires field to be non-empty when the value of otherField
+// equals value (compared with reflect.DeepEqual). Both fields are looked up
+// by name among the validators added to the form, so a validator must be
+// added for each of them, even one without rules. The rule is skipped if
+// either field is missing.
+//
+// Example:
+//
+//	result := vix.Form().
+//		Add(vix.String(req.Country, "country").Required()).
+//		Add(vix.String(req.State, "state")).
+//		RequiredIf("state", "country", "US").
+//		Validate()
+func (fv *FormValidator) RequiredIf(field, otherField string, value interface{}) *FormValidator {
+	fv.rules = append(fv.rules, formRule{field: field, other: otherField, value: value})
+	return fv
+}
+
+// RequiredUnless requires field to be non-empty unless the value of
+// otherField equals value. Fields are looked up as described for RequiredIf.
+func (fv *FormValidator) RequiredUnless(field, otherField string, value interface{}) *FormValidator {
+	fv.rules = append(fv.rules, formRule{field: field, other: otherField, value: value, unless: true})
+	return fv
+}
+
+// Validate returns a ValidationResult containing the errors of all added
+// validators, in the order they were added, followed by the errors of
+// cross-field rules. Each error keeps its own field name, so ErrMap groups
+// messages by field.
+func (fv *FormValidator) Validate() *ValidationResult {
+	result := NewValidationResult(nil, "form")
+	values := make(map[string]interface{}, len(fv.validators))
+	for _, validator := range fv.validators {
+		fieldResult := validator.Result()
+		values[fieldResult.FieldName] = fieldResult.Value
+		for _, err := range fieldResult.AllErrors() {
+			result.AddError(err)
+		}
+	}
+
+	for _, rule := range fv.rules {
+		value, ok := values[rule.field]
+		other, otherOK := values[rule.other]
+		if !ok || !otherOK {
+			continue
+		}
+		if reflect.DeepEqual(other, rule.value) == rule.unless || !isEmpty(value) {
+			continue
+		}
+
+		messageKey := erm.MsgRequiredIf
+		if rule.unless {
+			messageKey = erm.MsgRequiredUnless
+		}
+		result.AddError(erm.NewValidationError(messageKey, rule.field, value).
+			WithParam("other", rule.other).
+			WithParam("value", rule.value))
+	}
+
+	return result
+}
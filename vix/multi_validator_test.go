@@ -2,6 +2,7 @@ package vix
 
 import (
 	"encoding/json"
+	"slices"
 	"strings"
 	"testing"
 
@@ -141,6 +142,40 @@ func TestValidationOrchestrator_ToError(t *testing.T) {
 	})
 }
 
+func TestValidationOrchestrator_FirstErrMap(t *testing.T) {
+	valid := Is(String("test@example.com", "email").Required().Email())
+	if got := valid.FirstErrMap(); got != nil {
+		t.Errorf("expected nil map but got: %v", got)
+	}
+
+	orchestrator := Is(
+		String("ab", "username").MinLength(3).Regex(SlugRegex).AlphaNumeric().Not().In("ab"),
+		Int(16, "age").Required().Min(18),
+	)
+	if n := len(orchestrator.ErrMap()["username"]); n < 2 {
+		t.Fatalf("expected several username errors, got %d", n)
+	}
+
+	first := orchestrator.FirstErrMap()
+	if len(first) != 2 {
+		t.Fatalf("expected one entry per field, got %v", first)
+	}
+	if first["username"] != orchestrator.ErrMap()["username"][0] {
+		t.Errorf("expected first username message, got %q", first["username"])
+	}
+	if !strings.Contains(first["age"], "age must be at least 18") {
+		t.Errorf("expected age message, got %q", first["age"])
+	}
+
+	result := String("", "email").Required().Email().Result()
+	if got := result.FirstErrMap(); len(got) != 1 || got["email"] != result.ErrMap()["email"][0] {
+		t.Errorf("unexpected result first error map: %v", got)
+	}
+	if got := String("x", "email").Required().Result().FirstErrMap(); got != nil {
+		t.Errorf("expected nil map for valid result, got %v", got)
+	}
+}
+
 func TestValidationOrchestrator_ToJSON(t *testing.T) {
 	t.Run("valid orchestrator returns empty JSON", func(t *testing.T) {
 		orchestrator := Is(
@@ -263,6 +298,11 @@ func TestValidationOrchestrator_InRow(t *testing.T) {
 	if _, exists := errorMap["addresses[1].name"]; exists {
 		t.Error("error map should not contain 'addresses[1].name' key")
 	}
+
+	expected := []string{"name", "addresses[0].name", "addresses[0].street", "addresses[1].name", "addresses[1].street"}
+	if got := orchestrator.FieldNames(); !slices.Equal(got, expected) {
+		t.Errorf("FieldNames() = %v, want %v (namespaced fields keep their order)", got, expected)
+	}
 }
 
 func TestValidationOrchestrator_FieldNames(t *testing.T) {
@@ -764,3 +804,118 @@ func TestValidationOrchestrator_LocalizedErrMap(t *testing.T) {
 		t.Fatal("Localized error map should contain password field errors")
 	}
 }
+
+// =============================================================================
+// FormValidator Tests
+// =============================================================================
+
+func TestFormValidator(t *testing.T) {
+	t.Run("combines field errors", func(t *testing.T) {
+		result := Form().
+			Add(String("", "email").Required().Email()).
+			Add(Int(16, "age").Min(18)).
+			Add(String("john", "name").Required()).
+			Validate()
+
+		if result.Valid() {
+			t.Fatal("expected form to be invalid")
+		}
+		errorMap := result.ErrMap()
+		if len(errorMap) != 2 {
+			t.Fatalf("expected 2 fields in error map, got %v", errorMap)
+		}
+		if len(errorMap["email"]) != 2 {
+			t.Errorf("expected 2 email errors, got %v", errorMap["email"])
+		}
+		if len(errorMap["age"]) != 1 || !strings.Contains(errorMap["age"][0], "at least 18") {
+			t.Errorf("unexpected age errors %v", errorMap["age"])
+		}
+		if result.Error() == nil {
+			t.Error("expected error for invalid form")
+		}
+	})
+
+	t.Run("valid form", func(t *testing.T) {
+		result := Form().
+			Add(String("john@example.com", "email").Required().Email()).
+			Add(Int(30, "age").Min(18)).
+			Validate()
+
+		if !result.Valid() {
+			t.Errorf("expected valid form, got %v", result.ErrMap())
+		}
+		if result.ErrMap() != nil {
+			t.Error("expected nil error map for valid form")
+		}
+	})
+
+	t.Run("empty form", func(t *testing.T) {
+		if !Form().Validate().Valid() {
+			t.Error("expected empty form to be valid")
+		}
+	})
+}
+
+func TestFormValidator_RequiredIfUnless(t *testing.T) {
+	tests := []struct {
+		name      string
+		country   string
+		state     string
+		rule      func(f *FormValidator) *FormValidator
+		wantError string
+	}{
+		{
+			name:      "required if condition met and empty",
+			country:   "US",
+			rule:      func(f *FormValidator) *FormValidator { return f.RequiredIf("state", "country", "US") },
+			wantError: "state is required when country is US",
+		},
+		{
+			name:    "required if condition met and present",
+			country: "US",
+			state:   "CA",
+			rule:    func(f *FormValidator) *FormValidator { return f.RequiredIf("state", "country", "US") },
+		},
+		{
+			name:    "required if condition not met",
+			country: "FR",
+			rule:    func(f *FormValidator) *FormValidator { return f.RequiredIf("state", "country", "US") },
+		},
+		{
+			name:      "required unless condition not met",
+			country:   "FR",
+			rule:      func(f *FormValidator) *FormValidator { return f.RequiredUnless("state", "country", "DE") },
+			wantError: "state is required unless country is DE",
+		},
+		{
+			name:    "required unless condition met",
+			country: "DE",
+			rule:    func(f *FormValidator) *FormValidator { return f.RequiredUnless("state", "country", "DE") },
+		},
+		{
+			name:    "unknown field is skipped",
+			country: "US",
+			rule:    func(f *FormValidator) *FormValidator { return f.RequiredIf("zip", "country", "US") },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := Form().
+				Add(String(tt.country, "country").Required()).
+				Add(String(tt.state, "state"))
+			result := tt.rule(form).Validate()
+
+			if tt.wantError == "" {
+				if !result.Valid() {
+					t.Errorf("expected valid form, got %v", result.ErrMap())
+				}
+				return
+			}
+			errorMap := result.ErrMap()
+			if len(errorMap["state"]) != 1 || errorMap["state"][0] != tt.wantError {
+				t.Errorf("expected state error %q, got %v", tt.wantError, errorMap)
+			}
+		})
+	}
+}
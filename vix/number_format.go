@@ -0,0 +1,123 @@
+package vix
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+)
+
+// NumberFormat describes how numbers are written in a locale, so that
+// NormalizeNumber can turn user input such as "1.234,56" into the canonical
+// "1234.56" understood by Float and Integer.
+type NumberFormat struct {
+	// Decimal is the decimal separator, e.g. "." or ",".
+	Decimal string
+	// GroupSeparators lists the runes accepted as thousands separators,
+	// e.g. "," or ".". Empty disallows grouping.
+	GroupSeparators string
+}
+
+// Common number formats.
+var (
+	// NumberFormatPoint writes 1,234.56 (English, Chinese, Japanese, ...).
+	NumberFormatPoint = NumberFormat{Decimal: ".", GroupSeparators: ","}
+	// NumberFormatComma writes 1.234,56 (German, Spanish, Italian, ...).
+	NumberFormatComma = NumberFormat{Decimal: ",", GroupSeparators: "."}
+	// NumberFormatCommaSpace writes 1 234,56 (French, Polish, Swedish, ...).
+	// Regular, no-break and narrow no-break spaces are all accepted.
+	NumberFormatCommaSpace = NumberFormat{Decimal: ",", GroupSeparators: " \u00a0\u202f"}
+	// NumberFormatPointApostrophe writes 1'234.56 (Swiss German).
+	NumberFormatPointApostrophe = NumberFormat{Decimal: ".", GroupSeparators: "'’"}
+)
+
+// numberFormatsByLanguage maps base languages to their number format.
+// Languages not listed use NumberFormatPoint.
+var numberFormatsByLanguage = map[string]NumberFormat{
+	"de": NumberFormatComma, "es": NumberFormatComma, "it": NumberFormatComma,
+	"nl": NumberFormatComma, "pt": NumberFormatComma, "da": NumberFormatComma,
+	"id": NumberFormatComma, "tr": NumberFormatComma, "el": NumberFormatComma,
+	"ro": NumberFormatComma, "hr": NumberFormatComma, "sl": NumberFormatComma,
+	"sr": NumberFormatComma,
+	"fr": NumberFormatCommaSpace, "pl": NumberFormatCommaSpace, "sv": NumberFormatCommaSpace,
+	"fi": NumberFormatCommaSpace, "nb": NumberFormatCommaSpace, "no": NumberFormatCommaSpace,
+	"cs": NumberFormatCommaSpace, "sk": NumberFormatCommaSpace, "ru": NumberFormatCommaSpace,
+	"uk": NumberFormatCommaSpace, "hu": NumberFormatCommaSpace, "bg": NumberFormatCommaSpace,
+	"lt": NumberFormatCommaSpace, "lv": NumberFormatCommaSpace, "et": NumberFormatCommaSpace,
+}
+
+// NumberFormatFor returns the number format of the language tag, e.g. the
+// language negotiated from the request's Accept-Language header. It covers
+// the common European conventions; regional variants that differ from their
+// language (e.g. Spanish in Mexico) should pass an explicit NumberFormat.
+func NumberFormatFor(tag language.Tag) NumberFormat {
+	base, _ := tag.Base()
+	if region, _ := tag.Region(); base.String() == "de" && (region.String() == "CH" || region.String() == "LI") {
+		return NumberFormatPointApostrophe
+	}
+	if format, ok := numberFormatsByLanguage[base.String()]; ok {
+		return format
+	}
+	return NumberFormatPoint
+}
+
+// normalizeNumber converts str from format to the canonical form with an
+// optional sign, no grouping and "." as decimal separator. Group separators
+// must split the integer part into groups of three digits. It returns false
+// if str is not a number in that format.
+func normalizeNumber(str string, format NumberFormat) (string, bool) {
+	str = strings.TrimSpace(str)
+	sign := ""
+	if str != "" && (str[0] == '-' || str[0] == '+') {
+		sign, str = str[:1], str[1:]
+	}
+
+	integer, fraction, hasFraction := str, "", false
+	if format.Decimal != "" {
+		integer, fraction, hasFraction = strings.Cut(str, format.Decimal)
+	}
+	if hasFraction && (fraction == "" || !isDigits(fraction)) {
+		return "", false
+	}
+
+	groups := []string{integer}
+	if format.GroupSeparators != "" {
+		start := 0
+		groups = groups[:0]
+		for i, r := range integer {
+			if strings.ContainsRune(format.GroupSeparators, r) {
+				groups = append(groups, integer[start:i])
+				start = i + utf8.RuneLen(r)
+			}
+		}
+		groups = append(groups, integer[start:])
+	}
+
+	var digits strings.Builder
+	for i, group := range groups {
+		grouped := len(groups) > 1
+		if !isDigits(group) || (grouped && (group == "" || len(group) > 3 || (i > 0 && len(group) != 3))) {
+			return "", false
+		}
+		digits.WriteString(group)
+	}
+	if digits.Len() == 0 && !hasFraction {
+		return "", false
+	}
+
+	if hasFraction {
+		return sign + digits.String() + "." + fraction, true
+	}
+	return sign + digits.String(), true
+}
+
+// isDigits reports whether str consists only of ASCII digits. The empty
+// string counts as digits.
+func isDigits(str string) bool {
+	for i := 0; i < len(str); i++ {
+		if str[i] < '0' || str[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
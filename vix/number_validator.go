@@ -2,10 +2,16 @@ package vix
 
 import (
 	"fmt"
+	"maps"
 	"math"
+	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/c3p0-box/utils/erm"
+	"github.com/c3p0-box/utils/set"
+	"golang.org/x/text/language"
 )
 
 // =============================================================================
@@ -64,6 +70,18 @@ func (nv *NumberValidator[T]) Unless(condition func() bool) *NumberValidator[T]
 	return nv
 }
 
+// Optional skips all subsequent rules if the number is zero.
+func (nv *NumberValidator[T]) Optional() *NumberValidator[T] {
+	nv.BaseValidator.Optional()
+	return nv
+}
+
+// Locale sets the language of the validation messages.
+func (nv *NumberValidator[T]) Locale(tag language.Tag) *NumberValidator[T] {
+	nv.BaseValidator.Locale(tag)
+	return nv
+}
+
 // Custom validates using a custom validation function.
 // The function receives both the numeric value being validated and the field name,
 // allowing for more contextual error messages.
@@ -325,52 +343,95 @@ func (nv *NumberValidator[T]) Negative() *NumberValidator[T] {
 	return nv
 }
 
-// In validates that the number is one of the specified values.
+// In validates that the number is one of the specified values. On failure
+// the allowed values are also available as the "allowed" param ([]T).
+//
+// The values are scanned linearly. For large sets that are checked
+// repeatedly, build a set.Set once and use InSet instead.
 func (nv *NumberValidator[T]) In(values ...T) *NumberValidator[T] {
 	if !nv.shouldValidate() {
 		return nv
 	}
+	return nv.membership(slices.Contains(values, nv.value), func() []T { return values })
+}
 
-	valid := false
-	for _, v := range values {
-		if nv.value == v {
-			valid = true
-			break
-		}
+// NotIn validates that the number is not one of the specified values.
+func (nv *NumberValidator[T]) NotIn(values ...T) *NumberValidator[T] {
+	if !nv.shouldValidate() {
+		return nv
 	}
+	nv.negated = !nv.negated
+	return nv.membership(slices.Contains(values, nv.value), func() []T { return values })
+}
 
-	if !valid && !nv.negated {
-		nv.addValidationError(erm.MsgIn,
-			map[string]interface{}{"values": formatValues(values)})
-	} else if valid && nv.negated {
-		nv.addValidationError(erm.MsgNotIn,
-			map[string]interface{}{"values": formatValues(values)})
+// InSet validates that the number is in allowed. Membership is checked in
+// O(1), so a set built once can be reused for thousands of values.
+//
+// Example:
+//
+//	var knownIDs = set.New[int64]() // filled at startup
+//
+//	err := vix.Int64(id, "id").InSet(knownIDs).Validate()
+func (nv *NumberValidator[T]) InSet(allowed set.Set[T]) *NumberValidator[T] {
+	if !nv.shouldValidate() {
+		return nv
+	}
+	return nv.membership(allowed.Contains(nv.value), func() []T { return slices.Sorted(maps.Keys(allowed)) })
+}
+
+// NotInSet validates that the number is not in denied.
+func (nv *NumberValidator[T]) NotInSet(denied set.Set[T]) *NumberValidator[T] {
+	if !nv.shouldValidate() {
+		return nv
+	}
+	nv.negated = !nv.negated
+	return nv.membership(denied.Contains(nv.value), func() []T { return slices.Sorted(maps.Keys(denied)) })
+}
+
+// membership records the In/NotIn error for the given membership result.
+// The values are only listed (and formatted) when the rule fails, so that
+// passing checks against large sets do not pay for the error message.
+func (nv *NumberValidator[T]) membership(member bool, values func() []T) *NumberValidator[T] {
+	// addValidationError turns MsgIn into MsgNotIn when negated
+	if member == nv.negated {
+		listed := values()
+		params := map[string]interface{}{"values": formatValues(listed)}
+		if !member {
+			params["allowed"] = listed
+		}
+		nv.addValidationError(erm.MsgIn, params)
 	}
 
 	nv.negated = false
 	return nv
 }
 
-// NotIn validates that the number is not one of the specified values.
-func (nv *NumberValidator[T]) NotIn(values ...T) *NumberValidator[T] {
+// InRanges validates that the number falls within at least one of the given
+// inclusive [min, max] ranges.
+//
+// Example:
+//
+//	// 2xx or 4xx status codes
+//	err := vix.Int(status, "status").
+//		InRanges([][2]int{{200, 299}, {400, 499}}).
+//		Validate()
+func (nv *NumberValidator[T]) InRanges(ranges [][2]T) *NumberValidator[T] {
 	if !nv.shouldValidate() {
 		return nv
 	}
 
-	valid := true
-	for _, v := range values {
-		if nv.value == v {
-			valid = false
+	valid := false
+	for _, r := range ranges {
+		if nv.value >= r[0] && nv.value <= r[1] {
+			valid = true
 			break
 		}
 	}
 
-	if !valid && !nv.negated {
-		nv.addValidationError(erm.MsgNotIn,
-			map[string]interface{}{"values": formatValues(values)})
-	} else if valid && nv.negated {
-		nv.addValidationError(erm.MsgIn,
-			map[string]interface{}{"values": formatValues(values)})
+	// addValidationError turns MsgInRanges into MsgNotInRanges when negated
+	if valid == nv.negated {
+		nv.addValidationError(erm.MsgInRanges,
+			map[string]interface{}{"ranges": formatRanges(ranges), "value": nv.value})
 	}
 
 	nv.negated = false
@@ -388,15 +449,7 @@ func (nv *NumberValidator[T]) MultipleOf(divisor T) *NumberValidator[T] {
 		return nv
 	}
 
-	valid := false
-	switch any(nv.value).(type) {
-	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
-		// For integers, use modulo
-		valid = int64(nv.value)%int64(divisor) == 0
-	case float32, float64:
-		// For floats, use math.Mod
-		valid = math.Mod(float64(nv.value), float64(divisor)) == 0
-	}
+	valid := isMultipleOf(nv.value, divisor)
 
 	if !valid && !nv.negated {
 		nv.addValidationError(erm.MsgMultipleOf,
@@ -410,6 +463,127 @@ func (nv *NumberValidator[T]) MultipleOf(divisor T) *NumberValidator[T] {
 	return nv
 }
 
+// MultipleOfAll validates that the number is a multiple of every divisor,
+// e.g. MultipleOfAll(3, 5) accepts 15 and 30 but not 9. A zero divisor is
+// reported like in MultipleOf. On failure the divisors are available as the
+// "divisors" param.
+func (nv *NumberValidator[T]) MultipleOfAll(divisors ...T) *NumberValidator[T] {
+	return nv.multipleOfEach(divisors, true)
+}
+
+// MultipleOfAny validates that the number is a multiple of at least one
+// divisor, e.g. MultipleOfAny(3, 5) accepts 9 and 10 but not 7. A zero
+// divisor is reported like in MultipleOf. On failure the divisors are
+// available as the "divisors" param.
+func (nv *NumberValidator[T]) MultipleOfAny(divisors ...T) *NumberValidator[T] {
+	return nv.multipleOfEach(divisors, false)
+}
+
+// multipleOfEach implements MultipleOfAll (all is true) and MultipleOfAny,
+// reporting a single error for the whole list.
+func (nv *NumberValidator[T]) multipleOfEach(divisors []T, all bool) *NumberValidator[T] {
+	if !nv.shouldValidate() {
+		return nv
+	}
+
+	if slices.Contains(divisors, 0) {
+		nv.addValidationError(erm.MsgDivisorZero, nil)
+		return nv
+	}
+
+	matches := 0
+	for _, divisor := range divisors {
+		if isMultipleOf(nv.value, divisor) {
+			matches++
+		}
+	}
+	valid, msg := matches > 0, erm.MsgMultipleOfAny
+	if all {
+		valid, msg = matches == len(divisors), erm.MsgMultipleOfAll
+	}
+
+	// addValidationError turns msg into its negated key (e.g.
+	// MsgNotMultipleOfAll) after Not
+	if valid == nv.negated {
+		nv.addValidationError(msg,
+			map[string]interface{}{"divisors": formatValues(divisors)})
+	}
+
+	nv.negated = false
+	return nv
+}
+
+// isMultipleOf reports whether value is a multiple of the non-zero divisor.
+func isMultipleOf[T Number](value, divisor T) bool {
+	switch any(value).(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		// For integers, use modulo
+		return int64(value)%int64(divisor) == 0
+	case float32, float64:
+		// For floats, use math.Mod
+		return math.Mod(float64(value), float64(divisor)) == 0
+	}
+	return false
+}
+
+// Step validates that the number is base plus a whole multiple of step,
+// i.e. (value - base) % step == 0. Use it for quantized inputs such as
+// sliders or pricing tiers that increment by a fixed amount from a non-zero
+// base; MultipleOf covers the case where the base is zero. Float values
+// within a billionth of a step count as on it, so decimal steps such as 0.1
+// work despite rounding.
+//
+// Example:
+//
+//	// 10, 15, 20, ... (and 5, 0, -5, ... below the base)
+//	err := vix.Int(price, "price").Step(10, 5).Validate()
+func (nv *NumberValidator[T]) Step(base, step T) *NumberValidator[T] {
+	if !nv.shouldValidate() {
+		return nv
+	}
+
+	if step == 0 {
+		nv.addValidationError(erm.MsgDivisorZero, nil)
+		return nv
+	}
+
+	valid := onStep(nv.value, base, step)
+
+	// addValidationError turns MsgStep into MsgNotStep when negated
+	if valid == nv.negated {
+		nv.addValidationError(erm.MsgStep,
+			map[string]interface{}{"base": base, "step": step})
+	}
+
+	nv.negated = false
+	return nv
+}
+
+// stepTolerance is the fraction of the step by which a float may miss a
+// step and still count as on it, absorbing rounding errors such as
+// 0.3 - 0.1 = 0.19999999999999998.
+const stepTolerance = 1e-9
+
+// onStep reports whether value is base plus a whole multiple of step.
+// Unsigned values are compared in uint64 so that neither values below the
+// base nor values above math.MaxInt64 wrap around.
+func onStep[T Number](value, base, step T) bool {
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		diff := uint64(value) - uint64(base)
+		if value < base {
+			diff = uint64(base) - uint64(value)
+		}
+		return diff%uint64(step) == 0
+	case reflect.Float32, reflect.Float64:
+		s := math.Abs(float64(step))
+		r := math.Abs(math.Mod(float64(value)-float64(base), s))
+		return r <= s*stepTolerance || s-r <= s*stepTolerance
+	default:
+		return (int64(value)-int64(base))%int64(step) == 0
+	}
+}
+
 // Even validates that the number is even.
 func (nv *NumberValidator[T]) Even() *NumberValidator[T] {
 	if !nv.shouldValidate() {
@@ -473,6 +647,93 @@ func (nv *NumberValidator[T]) Finite() *NumberValidator[T] {
 	return nv
 }
 
+// Whole validates that the number is finite and has no fractional part,
+// e.g. a quantity decoded from JSON as float64. Integer types always pass.
+//
+// Example:
+//
+//	err := vix.Float64(3.0, "quantity").Whole().Validate() // passes
+func (nv *NumberValidator[T]) Whole() *NumberValidator[T] {
+	if !nv.shouldValidate() {
+		return nv
+	}
+
+	valid := true
+	switch reflect.ValueOf(nv.value).Kind() {
+	case reflect.Float32, reflect.Float64:
+		f := float64(nv.value)
+		valid = !math.IsInf(f, 0) && !math.IsNaN(f) && f == math.Trunc(f)
+	}
+
+	// addValidationError turns MsgWholeNumber into MsgNotWholeNumber when
+	// negated
+	if valid == nv.negated {
+		nv.addValidationError(erm.MsgWholeNumber, nil)
+	}
+
+	nv.negated = false
+	return nv
+}
+
+// IsInteger is an alias for Whole.
+func (nv *NumberValidator[T]) IsInteger() *NumberValidator[T] {
+	return nv.Whole()
+}
+
+// NumericPrecision validates that the number fits a fixed-point column such
+// as SQL DECIMAL(p, s): at most integerDigits digits before the decimal point
+// and at most fractionDigits after it. Leading zeros of the integer part and
+// trailing zeros of the fraction do not count.
+//
+// Example:
+//
+//	// DECIMAL(10,2): up to 99999999.99
+//	err := vix.Float64(amount, "amount").NumericPrecision(8, 2).Validate()
+func (nv *NumberValidator[T]) NumericPrecision(integerDigits, fractionDigits int) *NumberValidator[T] {
+	if !nv.shouldValidate() {
+		return nv
+	}
+
+	var str string
+	switch reflect.ValueOf(nv.value).Kind() {
+	case reflect.Float32:
+		str = strconv.FormatFloat(float64(nv.value), 'f', -1, 32)
+	case reflect.Float64:
+		str = strconv.FormatFloat(float64(nv.value), 'f', -1, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		str = strconv.FormatUint(uint64(nv.value), 10)
+	default:
+		str = strconv.FormatInt(int64(nv.value), 10)
+	}
+	intDigits, fracDigits, ok := countDecimalDigits(str)
+	valid := ok && intDigits <= integerDigits && fracDigits <= fractionDigits
+
+	// addValidationError turns MsgNumericPrecision into MsgNotNumericPrecision
+	// when negated
+	if valid == nv.negated {
+		nv.addValidationError(erm.MsgNumericPrecision,
+			map[string]interface{}{"integer_digits": integerDigits, "fraction_digits": fractionDigits})
+	}
+
+	nv.negated = false
+	return nv
+}
+
+// countDecimalDigits returns the number of significant integer digits and
+// fraction digits of a decimal string such as "-0012.340" (2 and 2). It
+// returns false if str is not a plain decimal number; NaN and infinities
+// are not.
+func countDecimalDigits(str string) (int, int, bool) {
+	if str != "" && (str[0] == '+' || str[0] == '-') {
+		str = str[1:]
+	}
+	integer, fraction, _ := strings.Cut(str, ".")
+	if (integer == "" && fraction == "") || !isDigits(integer) || !isDigits(fraction) {
+		return 0, 0, false
+	}
+	return len(strings.TrimLeft(integer, "0")), len(strings.TrimRight(fraction, "0")), true
+}
+
 // Precision validates that a float has at most the specified number of decimal places.
 func (nv *NumberValidator[T]) Precision(places int) *NumberValidator[T] {
 	if !nv.shouldValidate() {
@@ -523,11 +784,34 @@ func formatValues[T Number](values []T) string {
 		return ""
 	}
 
-	result := fmt.Sprintf("%v", values[0])
-	for i := 1; i < len(values); i++ {
-		result += fmt.Sprintf(", %v", values[i])
+	kind := reflect.TypeFor[T]().Kind()
+	buf := make([]byte, 0, len(values)*8)
+	for i, v := range values {
+		if i > 0 {
+			buf = append(buf, ", "...)
+		}
+		// strconv instead of fmt avoids boxing every value of large lists
+		switch kind {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			buf = strconv.AppendUint(buf, uint64(v), 10)
+		case reflect.Float32:
+			buf = strconv.AppendFloat(buf, float64(v), 'g', -1, 32)
+		case reflect.Float64:
+			buf = strconv.AppendFloat(buf, float64(v), 'g', -1, 64)
+		default:
+			buf = strconv.AppendInt(buf, int64(v), 10)
+		}
+	}
+	return string(buf)
+}
+
+// Helper function to format ranges for error messages, e.g. "[200, 299], [400, 499]"
+func formatRanges[T Number](ranges [][2]T) string {
+	parts := make([]string, len(ranges))
+	for i, r := range ranges {
+		parts[i] = fmt.Sprintf("[%v, %v]", r[0], r[1])
 	}
-	return result
+	return strings.Join(parts, ", ")
 }
 
 // =============================================================================
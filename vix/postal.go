@@ -0,0 +1,65 @@
+package vix
+
+import (
+	"regexp"
+	"strings"
+)
+
+// postalCodePatterns maps ISO 3166-1 alpha-2 country codes to the format of
+// their postal codes. Matching is case-insensitive for letters.
+var postalCodePatterns = map[string]*regexp.Regexp{
+	"AT": regexp.MustCompile(`^\d{4}$`),
+	"AU": regexp.MustCompile(`^\d{4}$`),
+	"BE": regexp.MustCompile(`^\d{4}$`),
+	"BR": regexp.MustCompile(`^\d{5}-?\d{3}$`),
+	"CA": regexp.MustCompile(`^(?i)[ABCEGHJ-NPRSTVXY]\d[ABCEGHJ-NPRSTV-Z] ?\d[ABCEGHJ-NPRSTV-Z]\d$`),
+	"CH": regexp.MustCompile(`^\d{4}$`),
+	"CN": regexp.MustCompile(`^\d{6}$`),
+	"CZ": regexp.MustCompile(`^\d{3} ?\d{2}$`),
+	"DE": regexp.MustCompile(`^\d{5}$`),
+	"DK": regexp.MustCompile(`^\d{4}$`),
+	"ES": regexp.MustCompile(`^(?:0[1-9]|[1-4]\d|5[0-2])\d{3}$`),
+	"FI": regexp.MustCompile(`^\d{5}$`),
+	"FR": regexp.MustCompile(`^\d{5}$`),
+	"GB": regexp.MustCompile(`^(?i)(?:GIR ?0AA|[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2})$`),
+	"IE": regexp.MustCompile(`^(?i)[AC-FHKNPRTV-Y]\d[\dW] ?[\dAC-FHKNPRTV-Y]{4}$`),
+	"IN": regexp.MustCompile(`^[1-9]\d{2} ?\d{3}$`),
+	"IT": regexp.MustCompile(`^\d{5}$`),
+	"JP": regexp.MustCompile(`^\d{3}-?\d{4}$`),
+	"MX": regexp.MustCompile(`^\d{5}$`),
+	"NL": regexp.MustCompile(`^(?i)[1-9]\d{3} ?[A-Z]{2}$`),
+	"NO": regexp.MustCompile(`^\d{4}$`),
+	"NZ": regexp.MustCompile(`^\d{4}$`),
+	"PL": regexp.MustCompile(`^\d{2}-\d{3}$`),
+	"PT": regexp.MustCompile(`^\d{4}-\d{3}$`),
+	"RU": regexp.MustCompile(`^\d{6}$`),
+	"SE": regexp.MustCompile(`^\d{3} ?\d{2}$`),
+	"US": regexp.MustCompile(`^\d{5}(?:-\d{4})?$`),
+}
+
+// postalCodeAliases maps commonly used non-ISO country codes to their ISO
+// equivalent.
+var postalCodeAliases = map[string]string{
+	"UK": "GB",
+}
+
+// lenientPostalCode is used for countries without a known format: letters or
+// digits, optionally separated by single spaces or hyphens.
+var lenientPostalCode = regexp.MustCompile(`^[A-Za-z0-9]+(?:[ -][A-Za-z0-9]+)*$`)
+
+// isValidPostalCode checks str against the postal code format of country
+// (an ISO 3166-1 alpha-2 code, case-insensitive). Unknown countries get a
+// lenient check.
+func isValidPostalCode(str, country string) bool {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if alias, ok := postalCodeAliases[country]; ok {
+		country = alias
+	}
+
+	if pattern, ok := postalCodePatterns[country]; ok {
+		return pattern.MatchString(str)
+	}
+
+	compact := strings.NewReplacer(" ", "", "-", "").Replace(str)
+	return len(compact) >= 2 && len(compact) <= 10 && lenientPostalCode.MatchString(str)
+}
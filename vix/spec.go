@@ -0,0 +1,288 @@
+package vix
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/c3p0-box/utils/erm"
+)
+
+// =============================================================================
+// Validation Specs
+// =============================================================================
+
+// Spec describes the validation rules of a field as data, so that rules can
+// be stored (e.g. as JSON in a database) and turned into validators at
+// runtime with FromSpec. Unset fields add no rule.
+//
+// Example JSON:
+//
+//	{"required": true, "minLength": 8, "pattern": "^[a-z0-9_]+$"}
+//	{"type": "number", "min": 0, "max": 100, "multipleOf": 5}
+type Spec struct {
+	// Type is "string" (the default) or "number".
+	Type string `json:"type,omitempty"`
+	// Required makes the field mandatory. When false, missing values skip
+	// all other rules (see Optional).
+	Required bool `json:"required,omitempty"`
+
+	// String rules.
+	MinLength *int   `json:"minLength,omitempty"`
+	MaxLength *int   `json:"maxLength,omitempty"`
+	Pattern   string `json:"pattern,omitempty"`
+	// Format is one of the named string formats: "email", "url", "uuid",
+	// "slug", "alpha", "alphanumeric", "numeric", "integer", "float", "json",
+	// "base64", "luhn", "isbn", "ean" or "upc".
+	Format string `json:"format,omitempty"`
+
+	// Number rules.
+	Min        *float64 `json:"min,omitempty"`
+	Max        *float64 `json:"max,omitempty"`
+	MultipleOf *float64 `json:"multipleOf,omitempty"`
+
+	// In lists the allowed values, strings or numbers depending on Type.
+	In []interface{} `json:"in,omitempty"`
+}
+
+// specFormats maps Spec.Format names to the corresponding string rule.
+var specFormats = map[string]func(*StringValidator) *StringValidator{
+	"email":        (*StringValidator).Email,
+	"url":          (*StringValidator).URL,
+	"uuid":         (*StringValidator).UUID,
+	"slug":         (*StringValidator).Slug,
+	"alpha":        (*StringValidator).Alpha,
+	"alphanumeric": (*StringValidator).AlphaNumeric,
+	"numeric":      (*StringValidator).Numeric,
+	"integer":      (*StringValidator).Integer,
+	"float":        (*StringValidator).Float,
+	"json":         (*StringValidator).JSON,
+	"base64":       (*StringValidator).Base64,
+	"luhn":         (*StringValidator).Luhn,
+	"isbn":         (*StringValidator).ISBN,
+	"ean":          (*StringValidator).EAN,
+	"upc":          (*StringValidator).UPC,
+}
+
+// ParseSpec decodes a JSON spec. Unknown keys are rejected so that typos in
+// stored rules do not silently disable validation.
+func ParseSpec(data []byte) (Spec, error) {
+	var spec Spec
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&spec); err != nil {
+		return Spec{}, fmt.Errorf("invalid validation spec: %w", err)
+	}
+	return spec, nil
+}
+
+// Merge returns a copy of s with the rules set in other applied on top:
+// set fields of other replace those of s, and Required is true if either
+// spec requires the field. It allows a base spec to be refined per form.
+func (s Spec) Merge(other Spec) Spec {
+	merged := s
+	if other.Type != "" {
+		merged.Type = other.Type
+	}
+	merged.Required = s.Required || other.Required
+	if other.MinLength != nil {
+		merged.MinLength = other.MinLength
+	}
+	if other.MaxLength != nil {
+		merged.MaxLength = other.MaxLength
+	}
+	if other.Pattern != "" {
+		merged.Pattern = other.Pattern
+	}
+	if other.Format != "" {
+		merged.Format = other.Format
+	}
+	if other.Min != nil {
+		merged.Min = other.Min
+	}
+	if other.Max != nil {
+		merged.Max = other.Max
+	}
+	if other.MultipleOf != nil {
+		merged.MultipleOf = other.MultipleOf
+	}
+	if other.In != nil {
+		merged.In = other.In
+	}
+	return merged
+}
+
+// SpecValidator validates values against a compiled Spec. It is safe to
+// reuse for any number of values.
+type SpecValidator struct {
+	field     string
+	spec      Spec
+	pattern   *regexp.Regexp
+	inStrings []string
+	inNumbers []float64
+}
+
+// FromSpec compiles spec into a reusable validator for field. It returns an
+// error if the spec is inconsistent, e.g. an unknown type or format, an
+// invalid pattern, or non-numeric "in" values for a number.
+//
+// Example:
+//
+//	spec, err := vix.ParseSpec(storedJSON)
+//	if err != nil {
+//		return err
+//	}
+//	sv, err := vix.FromSpec("username", spec)
+//	if err != nil {
+//		return err
+//	}
+//	err = sv.Validate(form.Username).Validate()
+func FromSpec(field string, spec Spec) (*SpecValidator, error) {
+	sv := &SpecValidator{field: field, spec: spec}
+
+	switch spec.Type {
+	case "", "string":
+		if spec.Format != "" {
+			if _, ok := specFormats[spec.Format]; !ok {
+				return nil, fmt.Errorf("unknown format %q for field %s", spec.Format, field)
+			}
+		}
+		if spec.Pattern != "" {
+			pattern, err := regexp.Compile(spec.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern for field %s: %w", field, err)
+			}
+			sv.pattern = pattern
+		}
+		for _, v := range spec.In {
+			sv.inStrings = append(sv.inStrings, toString(v))
+		}
+	case "number":
+		for _, v := range spec.In {
+			n, ok := toFloat64(v)
+			if !ok {
+				return nil, fmt.Errorf("invalid number %v in \"in\" for field %s", v, field)
+			}
+			sv.inNumbers = append(sv.inNumbers, n)
+		}
+	default:
+		return nil, fmt.Errorf("unknown type %q for field %s", spec.Type, field)
+	}
+
+	return sv, nil
+}
+
+// Validate runs the spec's rules on value and returns the resulting
+// validator. Number specs accept finite Go numeric types, json.Number and
+// numeric strings; other values (including NaN and infinities) fail with the
+// standard float message.
+func (sv *SpecValidator) Validate(value interface{}) Validator {
+	if sv.spec.Type == "number" {
+		return sv.validateNumber(value)
+	}
+	return sv.validateString(toString(value))
+}
+
+// validateString applies the string rules of the spec.
+func (sv *SpecValidator) validateString(value string) *StringValidator {
+	v := String(value, sv.field)
+	if sv.spec.Required {
+		v.Required()
+	}
+	v.Optional()
+
+	if sv.spec.MinLength != nil {
+		v.MinLength(*sv.spec.MinLength)
+	}
+	if sv.spec.MaxLength != nil {
+		v.MaxLength(*sv.spec.MaxLength)
+	}
+	if sv.pattern != nil {
+		v.Regex(sv.pattern)
+	}
+	if sv.spec.Format != "" {
+		specFormats[sv.spec.Format](v)
+	}
+	if sv.inStrings != nil {
+		v.In(sv.inStrings...)
+	}
+	return v
+}
+
+// validateNumber applies the number rules of the spec. Only nil and blank
+// strings count as missing: an explicit 0 is a value like any other.
+func (sv *SpecValidator) validateNumber(value interface{}) Validator {
+	if str, isString := value.(string); value == nil || (isString && strings.TrimSpace(str) == "") {
+		v := String("", sv.field)
+		if sv.spec.Required {
+			v.Required()
+		}
+		return v
+	}
+
+	number, ok := toFloat64(value)
+	if !ok {
+		// Not Float(): it would accept "NaN" and "Inf"
+		v := String(toString(value), sv.field)
+		v.addValidationError(erm.MsgFloat, nil)
+		return v
+	}
+
+	v := Float64(number, sv.field)
+	if sv.spec.Min != nil {
+		v.Min(*sv.spec.Min)
+	}
+	if sv.spec.Max != nil {
+		v.Max(*sv.spec.Max)
+	}
+	if sv.spec.MultipleOf != nil {
+		v.MultipleOf(*sv.spec.MultipleOf)
+	}
+	if sv.inNumbers != nil {
+		v.In(sv.inNumbers...)
+	}
+	return v
+}
+
+// toFloat64 converts numeric values, json.Number and numeric strings to
+// float64. NaN and infinities are rejected, since they would slip past
+// one-sided bounds ("Inf" satisfies any min) or every rule (NaN).
+func toFloat64(value interface{}) (float64, bool) {
+	var f float64
+	switch v := value.(type) {
+	case json.Number:
+		parsed, err := v.Float64()
+		if err != nil {
+			return 0, false
+		}
+		f = parsed
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, false
+		}
+		f = parsed
+	default:
+		val := reflect.ValueOf(value)
+		switch val.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return float64(val.Int()), true
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return float64(val.Uint()), true
+		case reflect.Float32, reflect.Float64:
+			f = val.Float()
+		default:
+			return 0, false
+		}
+	}
+
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, false
+	}
+	return f, true
+}
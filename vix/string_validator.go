@@ -3,11 +3,17 @@ package vix
 import (
 	"encoding/base64"
 	"encoding/json"
+	"math"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/c3p0-box/utils/erm"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
 )
 
 // =============================================================================
@@ -18,6 +24,8 @@ import (
 // It supports method chaining for readable and maintainable validation.
 type StringValidator struct {
 	*BaseValidator
+	normForm   norm.Form // Unicode form applied by NormalizeUnicode
+	normalized bool
 }
 
 // String creates a new StringValidator for the given value and field name.
@@ -59,6 +67,27 @@ func (sv *StringValidator) Unless(condition func() bool) *StringValidator {
 	return sv
 }
 
+// Optional skips all subsequent rules if the string is empty or whitespace
+// only, so that format rules apply only when a value is present.
+//
+// Example:
+//
+//	err := vix.String(" ", "email").Optional().Email().Validate() // nil
+func (sv *StringValidator) Optional() *StringValidator {
+	sv.BaseValidator.Optional()
+	return sv
+}
+
+// Locale sets the language of the validation messages.
+//
+// Example:
+//
+//	err := vix.String(req.Email, "email").Locale(language.French).Required().Email().Validate()
+func (sv *StringValidator) Locale(tag language.Tag) *StringValidator {
+	sv.BaseValidator.Locale(tag)
+	return sv
+}
+
 // Custom validates using a custom validation function.
 // The function receives both the string value being validated and the field name,
 // allowing for more contextual error messages.
@@ -144,6 +173,9 @@ func (sv *StringValidator) EqualTo(other string, msgTemplate ...string) *StringV
 		return sv
 	}
 
+	if sv.normalized {
+		other = sv.normForm.String(other)
+	}
 	str := toString(sv.value)
 	isValid := str == other
 
@@ -259,6 +291,71 @@ func (sv *StringValidator) LengthBetween(min, max int) *StringValidator {
 	return sv
 }
 
+// MinGraphemes validates that the string has at least min user-perceived
+// characters (extended grapheme clusters). Unlike MinLength, which counts
+// runes, an emoji such as 👨‍👩‍👧 (five runes) or a letter with a combining
+// accent counts as one, so it suits limits shown to users.
+func (sv *StringValidator) MinGraphemes(min int) *StringValidator {
+	if !sv.shouldValidate() {
+		return sv
+	}
+
+	isValid := graphemeCount(toString(sv.value)) >= min
+
+	// addValidationError turns MsgMinLength into MsgNotMinLength when negated
+	if isValid == sv.negated {
+		sv.addValidationError(erm.MsgMinLength,
+			map[string]interface{}{"min": min})
+	}
+
+	sv.negated = false
+	return sv
+}
+
+// MaxGraphemes validates that the string has at most max user-perceived
+// characters (extended grapheme clusters).
+//
+// Example:
+//
+//	// Display names of up to 20 characters, emoji included
+//	err := vix.String(name, "display_name").MaxGraphemes(20).Validate()
+func (sv *StringValidator) MaxGraphemes(max int) *StringValidator {
+	if !sv.shouldValidate() {
+		return sv
+	}
+
+	isValid := graphemeCount(toString(sv.value)) <= max
+
+	// addValidationError turns MsgMaxLength into MsgNotMaxLength when negated
+	if isValid == sv.negated {
+		sv.addValidationError(erm.MsgMaxLength,
+			map[string]interface{}{"max": max})
+	}
+
+	sv.negated = false
+	return sv
+}
+
+// GraphemesBetween validates that the number of user-perceived characters
+// is between min and max (inclusive).
+func (sv *StringValidator) GraphemesBetween(min, max int) *StringValidator {
+	if !sv.shouldValidate() {
+		return sv
+	}
+
+	length := graphemeCount(toString(sv.value))
+	isValid := length >= min && length <= max
+
+	// addValidationError turns MsgBetween into MsgNotBetween when negated
+	if isValid == sv.negated {
+		sv.addValidationError(erm.MsgBetween,
+			map[string]interface{}{"min": min, "max": max})
+	}
+
+	sv.negated = false
+	return sv
+}
+
 // =============================================================================
 // Format Validation
 // =============================================================================
@@ -282,6 +379,57 @@ func (sv *StringValidator) Email() *StringValidator {
 	return sv
 }
 
+// NormalizeEmail replaces the value with its canonical form for
+// deduplication using DefaultEmailNormalizationConfig: the domain is
+// lowercased and, for known providers such as Gmail, the local part is
+// lowercased and dots and "+tags" are removed from it. The normalized value
+// is validated by subsequent rules and available as Result().Value.
+//
+// Example:
+//
+//	v := vix.String("John.Doe+news@GMail.com", "email").NormalizeEmail().Email()
+//	v.Result().Value // "johndoe@gmail.com"
+func (sv *StringValidator) NormalizeEmail() *StringValidator {
+	return sv.NormalizeEmailWithConfig(DefaultEmailNormalizationConfig)
+}
+
+// NormalizeEmailWithConfig is like NormalizeEmail with custom provider rules.
+func (sv *StringValidator) NormalizeEmailWithConfig(config EmailNormalizationConfig) *StringValidator {
+	if !sv.shouldValidate() {
+		return sv
+	}
+
+	sv.value = normalizeEmail(toString(sv.value), config)
+	if sv.result != nil {
+		sv.result.Value = sv.value
+	}
+	return sv
+}
+
+// NormalizeUnicode replaces the value with its Unicode normalization form,
+// so that composed and decomposed spellings of the same text ("é" as one
+// rune or as "e" plus a combining accent) are stored and compared alike.
+// Subsequent rules see the normalized value, EqualTo normalizes its expected
+// value the same way, and Result().Value holds the normalized string. NFC is
+// the usual choice for storage.
+//
+// Example:
+//
+//	v := vix.String(input, "name").NormalizeUnicode(norm.NFC).Required()
+//	name := v.Result().Value.(string)
+func (sv *StringValidator) NormalizeUnicode(form norm.Form) *StringValidator {
+	if !sv.shouldValidate() {
+		return sv
+	}
+
+	sv.normForm, sv.normalized = form, true
+	sv.value = form.String(toString(sv.value))
+	if sv.result != nil {
+		sv.result.Value = sv.value
+	}
+	return sv
+}
+
 // URL validates that the string is a valid URL format.
 func (sv *StringValidator) URL() *StringValidator {
 	if !sv.shouldValidate() {
@@ -320,6 +468,49 @@ func (sv *StringValidator) Numeric() *StringValidator {
 	return sv
 }
 
+// NumericLength validates that the string consists of exactly length ASCII
+// digits, e.g. a 4-digit PIN. It reports a single error where Numeric and
+// ExactLength would report two.
+func (sv *StringValidator) NumericLength(length int) *StringValidator {
+	if !sv.shouldValidate() {
+		return sv
+	}
+
+	str := toString(sv.value)
+	valid := str != "" && isDigits(str) && len(str) == length
+
+	// addValidationError turns MsgNumericLength into MsgNotNumericLength when
+	// negated
+	if valid == sv.negated {
+		sv.addValidationError(erm.MsgNumericLength,
+			map[string]interface{}{"length": length})
+	}
+
+	sv.negated = false
+	return sv
+}
+
+// NumericLengthBetween validates that the string consists of between min and
+// max ASCII digits (inclusive), e.g. an account number.
+func (sv *StringValidator) NumericLengthBetween(min, max int) *StringValidator {
+	if !sv.shouldValidate() {
+		return sv
+	}
+
+	str := toString(sv.value)
+	valid := str != "" && isDigits(str) && len(str) >= min && len(str) <= max
+
+	// addValidationError turns MsgNumericLengthBetween into
+	// MsgNotNumericLengthBetween when negated
+	if valid == sv.negated {
+		sv.addValidationError(erm.MsgNumericLengthBetween,
+			map[string]interface{}{"min": min, "max": max})
+	}
+
+	sv.negated = false
+	return sv
+}
+
 // Alpha validates that the string contains only alphabetic characters.
 func (sv *StringValidator) Alpha() *StringValidator {
 	if !sv.shouldValidate() {
@@ -384,6 +575,11 @@ func (sv *StringValidator) Regex(pattern *regexp.Regexp) *StringValidator {
 // =============================================================================
 
 // In validates that the string is one of the specified values.
+//
+// When the value is not allowed, the error carries the allowed values in the
+// "allowed" param ([]string) and, if one of them is close enough to the value
+// (by Levenshtein distance), that value in the "suggestion" param, so that
+// clients can render "did you mean X?". Use erm.Error.Params to read them.
 func (sv *StringValidator) In(values ...string) *StringValidator {
 	if !sv.shouldValidate() {
 		return sv
@@ -399,8 +595,7 @@ func (sv *StringValidator) In(values ...string) *StringValidator {
 	}
 
 	if !valid && !sv.negated {
-		sv.addValidationError(erm.MsgIn,
-			map[string]interface{}{"values": strings.Join(values, ", ")})
+		sv.addValidationError(erm.MsgIn, allowedValuesParams(str, values))
 	} else if valid && sv.negated {
 		sv.addValidationError(erm.MsgNotIn,
 			map[string]interface{}{"values": strings.Join(values, ", ")})
@@ -429,8 +624,7 @@ func (sv *StringValidator) NotIn(values ...string) *StringValidator {
 		sv.addValidationError(erm.MsgNotIn,
 			map[string]interface{}{"values": strings.Join(values, ", ")})
 	} else if valid && sv.negated {
-		sv.addValidationError(erm.MsgIn,
-			map[string]interface{}{"values": strings.Join(values, ", ")})
+		sv.addValidationError(erm.MsgIn, allowedValuesParams(str, values))
 	}
 
 	sv.negated = false
@@ -462,6 +656,62 @@ func (sv *StringValidator) Contains(substring string) *StringValidator {
 	return sv
 }
 
+// NotContainsAny validates that the string contains none of words, compared
+// case-insensitively. The error reports the first matched word in the
+// "word" param.
+func (sv *StringValidator) NotContainsAny(words ...string) *StringValidator {
+	return sv.blocklist(words, false)
+}
+
+// Blocklist validates that the string contains no word of list, compared
+// case-insensitively. It takes a slice so that one list can be shared
+// across fields.
+//
+// Example:
+//
+//	var reserved = []string{"admin", "root", "support"}
+//
+//	err := vix.String(username, "username").Blocklist(reserved).Validate()
+func (sv *StringValidator) Blocklist(list []string) *StringValidator {
+	return sv.blocklist(list, false)
+}
+
+// BlocklistLeet is like Blocklist but also normalizes common leetspeak
+// substitutions in the value ("4dm1n" matches "admin") before matching.
+func (sv *StringValidator) BlocklistLeet(list []string) *StringValidator {
+	return sv.blocklist(list, true)
+}
+
+// blocklist reports the first word of list found in the value.
+func (sv *StringValidator) blocklist(list []string, leet bool) *StringValidator {
+	if !sv.shouldValidate() {
+		return sv
+	}
+
+	str := strings.ToLower(toString(sv.value))
+	if leet {
+		str = leetReplacer.Replace(str)
+	}
+
+	match := ""
+	for _, word := range list {
+		if word != "" && strings.Contains(str, strings.ToLower(word)) {
+			match = word
+			break
+		}
+	}
+
+	if match != "" && !sv.negated {
+		sv.addValidationError(erm.MsgBlocklist, map[string]interface{}{"word": match})
+	} else if match == "" && sv.negated {
+		sv.addValidationError(erm.MsgNotBlocklist,
+			map[string]interface{}{"words": strings.Join(list, ", ")})
+	}
+
+	sv.negated = false
+	return sv
+}
+
 // StartsWith validates that the string starts with the specified prefix.
 func (sv *StringValidator) StartsWith(prefix string) *StringValidator {
 	if !sv.shouldValidate() {
@@ -504,6 +754,121 @@ func (sv *StringValidator) EndsWith(suffix string) *StringValidator {
 	return sv
 }
 
+// StartsWithFold validates that the string starts with prefix, ignoring
+// case (Unicode simple case folding, like strings.EqualFold). Unlike
+// lowercasing the value first, it leaves the value seen by other rules
+// untouched.
+//
+// Example:
+//
+//	err := vix.String(endpoint, "endpoint").StartsWithFold("https://").Validate()
+func (sv *StringValidator) StartsWithFold(prefix string) *StringValidator {
+	if !sv.shouldValidate() {
+		return sv
+	}
+
+	valid := hasPrefixFold(toString(sv.value), prefix)
+
+	if !valid && !sv.negated {
+		sv.addValidationError(erm.MsgStartsWith,
+			map[string]interface{}{"prefix": prefix})
+	} else if valid && sv.negated {
+		sv.addValidationError(erm.MsgNotStartsWith,
+			map[string]interface{}{"prefix": prefix})
+	}
+
+	sv.negated = false
+	return sv
+}
+
+// EndsWithFold validates that the string ends with suffix, ignoring case.
+func (sv *StringValidator) EndsWithFold(suffix string) *StringValidator {
+	if !sv.shouldValidate() {
+		return sv
+	}
+
+	valid := hasSuffixFold(toString(sv.value), suffix)
+
+	if !valid && !sv.negated {
+		sv.addValidationError(erm.MsgEndsWith,
+			map[string]interface{}{"suffix": suffix})
+	} else if valid && sv.negated {
+		sv.addValidationError(erm.MsgNotEndsWith,
+			map[string]interface{}{"suffix": suffix})
+	}
+
+	sv.negated = false
+	return sv
+}
+
+// ContainsFold validates that the string contains substring, ignoring case.
+func (sv *StringValidator) ContainsFold(substring string) *StringValidator {
+	if !sv.shouldValidate() {
+		return sv
+	}
+
+	valid := containsFold(toString(sv.value), substring)
+
+	if !valid && !sv.negated {
+		sv.addValidationError(erm.MsgContains,
+			map[string]interface{}{"substring": substring})
+	} else if valid && sv.negated {
+		sv.addValidationError(erm.MsgNotContains,
+			map[string]interface{}{"substring": substring})
+	}
+
+	sv.negated = false
+	return sv
+}
+
+// StartsWithAny validates that the string starts with at least one of the
+// given prefixes, e.g. identifiers with one of several allowed type prefixes.
+//
+// Example:
+//
+//	err := vix.String(id, "id").StartsWithAny("cus_", "acct_").Validate()
+func (sv *StringValidator) StartsWithAny(prefixes ...string) *StringValidator {
+	if !sv.shouldValidate() {
+		return sv
+	}
+
+	str := toString(sv.value)
+	valid := slices.ContainsFunc(prefixes, func(prefix string) bool {
+		return strings.HasPrefix(str, prefix)
+	})
+
+	// Fails when no prefix matches, or when one does under Not();
+	// addValidationError picks the negated message in the latter case.
+	if valid == sv.negated {
+		sv.addValidationError(erm.MsgStartsWithAny,
+			map[string]interface{}{"prefixes": strings.Join(prefixes, ", ")})
+	}
+
+	sv.negated = false
+	return sv
+}
+
+// EndsWithAny validates that the string ends with at least one of the given
+// suffixes.
+func (sv *StringValidator) EndsWithAny(suffixes ...string) *StringValidator {
+	if !sv.shouldValidate() {
+		return sv
+	}
+
+	str := toString(sv.value)
+	valid := slices.ContainsFunc(suffixes, func(suffix string) bool {
+		return strings.HasSuffix(str, suffix)
+	})
+
+	if valid == sv.negated {
+		sv.addValidationError(erm.MsgEndsWithAny,
+			map[string]interface{}{"suffixes": strings.Join(suffixes, ", ")})
+	}
+
+	sv.negated = false
+	return sv
+}
+
 // =============================================================================
 // Case Validation
 // =============================================================================
@@ -570,6 +935,59 @@ func (sv *StringValidator) Integer() *StringValidator {
 	return sv
 }
 
+// NumericPrecision validates that the string is a decimal number with at
+// most integerDigits digits before the decimal point and at most
+// fractionDigits after it, like NumberValidator.NumericPrecision. The string
+// must use "." as decimal separator; chain NormalizeNumber first for
+// localized input. Non-numeric strings fail.
+func (sv *StringValidator) NumericPrecision(integerDigits, fractionDigits int) *StringValidator {
+	if !sv.shouldValidate() {
+		return sv
+	}
+
+	intDigits, fracDigits, ok := countDecimalDigits(strings.TrimSpace(toString(sv.value)))
+	valid := ok && intDigits <= integerDigits && fracDigits <= fractionDigits
+
+	// addValidationError turns MsgNumericPrecision into MsgNotNumericPrecision
+	// when negated
+	if valid == sv.negated {
+		sv.addValidationError(erm.MsgNumericPrecision,
+			map[string]interface{}{"integer_digits": integerDigits, "fraction_digits": fractionDigits})
+	}
+
+	sv.negated = false
+	return sv
+}
+
+// NormalizeNumber rewrites a number written in format, such as "1.234,56"
+// with NumberFormatComma, to the canonical "1234.56" that Float and Integer
+// accept. The normalized value is validated by subsequent rules and
+// available as Result().Value. Values that are not numbers in format are
+// left unchanged: a following Float or Integer rejects "1,2,3" but still
+// accepts canonical input such as "0.5".
+//
+// Example:
+//
+//	v := vix.String(input, "price").
+//		NormalizeNumber(vix.NumberFormatFor(locale)).
+//		Float()
+//	price, _ := strconv.ParseFloat(v.Result().Value.(string), 64)
+func (sv *StringValidator) NormalizeNumber(format NumberFormat) *StringValidator {
+	if !sv.shouldValidate() {
+		return sv
+	}
+
+	normalized, ok := normalizeNumber(toString(sv.value), format)
+	if !ok {
+		return sv
+	}
+	sv.value = normalized
+	if sv.result != nil {
+		sv.result.Value = sv.value
+	}
+	return sv
+}
+
 // Float validates that the string represents a valid floating-point number.
 func (sv *StringValidator) Float() *StringValidator {
 	if !sv.shouldValidate() {
@@ -590,6 +1008,26 @@ func (sv *StringValidator) Float() *StringValidator {
 	return sv
 }
 
+// ValidUTF8 validates that the string is valid UTF-8. Use it at the
+// boundary for data from binary sources, before invalid bytes end up in
+// JSON responses or the database.
+func (sv *StringValidator) ValidUTF8() *StringValidator {
+	if !sv.shouldValidate() {
+		return sv
+	}
+
+	valid := utf8.ValidString(toString(sv.value))
+
+	if !valid && !sv.negated {
+		sv.addValidationError(erm.MsgUTF8, nil)
+	} else if valid && sv.negated {
+		sv.addValidationError(erm.MsgNotUTF8, nil)
+	}
+
+	sv.negated = false
+	return sv
+}
+
 // JSON validates that the string is valid JSON.
 func (sv *StringValidator) JSON() *StringValidator {
 	if !sv.shouldValidate() {
@@ -666,6 +1104,159 @@ func (sv *StringValidator) Slug() *StringValidator {
 	return sv
 }
 
+// Luhn validates that the string is a sequence of digits with a valid Luhn
+// (mod 10) checksum, as used by IMEIs and many identification numbers. Unlike
+// a credit card check, no length or issuer rules are applied.
+func (sv *StringValidator) Luhn() *StringValidator {
+	if !sv.shouldValidate() {
+		return sv
+	}
+
+	str := toString(sv.value)
+	valid := IsLuhn(str)
+
+	if !valid && !sv.negated {
+		sv.addValidationError(erm.MsgLuhn, nil)
+	} else if valid && sv.negated {
+		sv.addValidationError(erm.MsgNotLuhn, nil)
+	}
+
+	sv.negated = false
+	return sv
+}
+
+// ISBN validates that the string is a valid ISBN-10 or ISBN-13. Hyphens and
+// spaces are ignored before the check digit is verified.
+func (sv *StringValidator) ISBN() *StringValidator {
+	return sv.isbn(func(str string) bool {
+		return isValidISBN10(str) || isValidISBN13(str)
+	})
+}
+
+// ISBN10 validates that the string is a valid ISBN-10; the check digit may be
+// "X". Hyphens and spaces are ignored.
+func (sv *StringValidator) ISBN10() *StringValidator {
+	return sv.isbn(isValidISBN10)
+}
+
+// ISBN13 validates that the string is a valid ISBN-13. Hyphens and spaces are
+// ignored.
+func (sv *StringValidator) ISBN13() *StringValidator {
+	return sv.isbn(isValidISBN13)
+}
+
+// isbn applies an ISBN check to the normalized string value.
+func (sv *StringValidator) isbn(check func(string) bool) *StringValidator {
+	if !sv.shouldValidate() {
+		return sv
+	}
+
+	str := normalizeISBN(toString(sv.value))
+	valid := check(str)
+
+	if !valid && !sv.negated {
+		sv.addValidationError(erm.MsgISBN, nil)
+	} else if valid && sv.negated {
+		sv.addValidationError(erm.MsgNotISBN, nil)
+	}
+
+	sv.negated = false
+	return sv
+}
+
+// EAN validates that the string is an EAN-8 or EAN-13 barcode with a valid
+// check digit.
+func (sv *StringValidator) EAN() *StringValidator {
+	if !sv.shouldValidate() {
+		return sv
+	}
+
+	str := toString(sv.value)
+	valid := (len(str) == 8 || len(str) == 13) && isValidGTIN(str)
+
+	if !valid && !sv.negated {
+		sv.addValidationError(erm.MsgEAN, nil)
+	} else if valid && sv.negated {
+		sv.addValidationError(erm.MsgNotEAN, nil)
+	}
+
+	sv.negated = false
+	return sv
+}
+
+// UPC validates that the string is a 12-digit UPC-A barcode with a valid
+// check digit.
+func (sv *StringValidator) UPC() *StringValidator {
+	if !sv.shouldValidate() {
+		return sv
+	}
+
+	str := toString(sv.value)
+	valid := len(str) == 12 && isValidGTIN(str)
+
+	if !valid && !sv.negated {
+		sv.addValidationError(erm.MsgUPC, nil)
+	} else if valid && sv.negated {
+		sv.addValidationError(erm.MsgNotUPC, nil)
+	}
+
+	sv.negated = false
+	return sv
+}
+
+// PostalCode validates that the string is a postal code in the format used by
+// country, an ISO 3166-1 alpha-2 code such as "US", "GB" (or "UK") or "DE".
+// Unknown countries only get a lenient check: 2 to 10 letters or digits,
+// optionally grouped with spaces or hyphens.
+//
+// Example:
+//
+//	err := vix.String(addr.Zip, "zip").Required().PostalCode(addr.Country).Validate()
+func (sv *StringValidator) PostalCode(country string) *StringValidator {
+	if !sv.shouldValidate() {
+		return sv
+	}
+
+	str := strings.TrimSpace(toString(sv.value))
+	valid := isValidPostalCode(str, country)
+
+	params := map[string]interface{}{"country": country}
+	if !valid && !sv.negated {
+		sv.addValidationError(erm.MsgPostalCode, params)
+	} else if valid && sv.negated {
+		sv.addValidationError(erm.MsgNotPostalCode, params)
+	}
+
+	sv.negated = false
+	return sv
+}
+
+// MinEntropy validates that the string carries at least bits of Shannon
+// entropy, estimated from its character frequencies (entropy per character
+// times length). It is a lightweight guard against predictable secrets such
+// as "aaaaaaaa" or "abcabcabc"; it does not detect dictionary words.
+//
+// For reference, 16 random hex characters have about 64 bits and 22 random
+// base64 characters about 128 bits.
+func (sv *StringValidator) MinEntropy(bits float64) *StringValidator {
+	if !sv.shouldValidate() {
+		return sv
+	}
+
+	str := toString(sv.value)
+	valid := shannonEntropy(str) >= bits
+
+	params := map[string]interface{}{"min": bits}
+	if !valid && !sv.negated {
+		sv.addValidationError(erm.MsgMinEntropy, params)
+	} else if valid && sv.negated {
+		sv.addValidationError(erm.MsgNotMinEntropy, params)
+	}
+
+	sv.negated = false
+	return sv
+}
+
 // String format validation helper functions
 // These functions are used internally and can be reused across different validators.
 
@@ -678,6 +1269,66 @@ func isValidJSON(str string) bool {
 	return json.Unmarshal([]byte(str), &js) == nil
 }
 
+// equalFoldRune reports whether r1 and r2 are equal under Unicode simple
+// case folding.
+func equalFoldRune(r1, r2 rune) bool {
+	if r1 == r2 {
+		return true
+	}
+	for r := unicode.SimpleFold(r1); r != r1; r = unicode.SimpleFold(r) {
+		if r == r2 {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPrefixFold is strings.HasPrefix under Unicode simple case folding.
+// It compares rune by rune, since folded runes may differ in byte length.
+func hasPrefixFold(s, prefix string) bool {
+	for prefix != "" {
+		if s == "" {
+			return false
+		}
+		r1, n1 := utf8.DecodeRuneInString(s)
+		r2, n2 := utf8.DecodeRuneInString(prefix)
+		if !equalFoldRune(r1, r2) {
+			return false
+		}
+		s, prefix = s[n1:], prefix[n2:]
+	}
+	return true
+}
+
+// hasSuffixFold is strings.HasSuffix under Unicode simple case folding.
+func hasSuffixFold(s, suffix string) bool {
+	for suffix != "" {
+		if s == "" {
+			return false
+		}
+		r1, n1 := utf8.DecodeLastRuneInString(s)
+		r2, n2 := utf8.DecodeLastRuneInString(suffix)
+		if !equalFoldRune(r1, r2) {
+			return false
+		}
+		s, suffix = s[:len(s)-n1], suffix[:len(suffix)-n2]
+	}
+	return true
+}
+
+// containsFold is strings.Contains under Unicode simple case folding.
+func containsFold(s, substr string) bool {
+	if substr == "" {
+		return true
+	}
+	for i := range s {
+		if hasPrefixFold(s[i:], substr) {
+			return true
+		}
+	}
+	return false
+}
+
 // isValidUUID checks if the string is a valid UUID (version 4 format).
 func isValidUUID(str string) bool {
 	// UUID format: xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx
@@ -686,8 +1337,7 @@ func isValidUUID(str string) bool {
 	}
 
 	// Check format with regex for better validation
-	uuidRegex := regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
-	return uuidRegex.MatchString(str)
+	return UUIDRegex.MatchString(str)
 }
 
 // isValidSlug checks if the string is a valid URL slug.
@@ -699,10 +1349,143 @@ func isValidSlug(str string) bool {
 	}
 
 	// Check for valid slug pattern
-	slugRegex := regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
-	return slugRegex.MatchString(str)
+	return SlugRegex.MatchString(str)
+}
+
+// IsLuhn reports whether str consists only of ASCII digits and passes the
+// Luhn (mod 10) checksum. Strings with fewer than two digits are rejected.
+func IsLuhn(str string) bool {
+	if len(str) < 2 {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(str) - 1; i >= 0; i-- {
+		c := str[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		digit := int(c - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+
+	return sum%10 == 0
+}
+
+// normalizeISBN removes the hyphens and spaces commonly used to group ISBN
+// digits.
+func normalizeISBN(str string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(str)
+}
+
+// isValidISBN10 checks the ISBN-10 checksum of a normalized string. The last
+// character may be "X" (or "x") for a check digit of 10.
+func isValidISBN10(str string) bool {
+	if len(str) != 10 {
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < 10; i++ {
+		c := str[i]
+		var digit int
+		switch {
+		case c >= '0' && c <= '9':
+			digit = int(c - '0')
+		case i == 9 && (c == 'X' || c == 'x'):
+			digit = 10
+		default:
+			return false
+		}
+		sum += digit * (10 - i)
+	}
+
+	return sum%11 == 0
 }
 
+// isValidISBN13 checks the ISBN-13 checksum of a normalized string.
+func isValidISBN13(str string) bool {
+	if len(str) != 13 {
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < 13; i++ {
+		c := str[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(c-'0') * weight
+	}
+
+	return sum%10 == 0
+}
+
+// isValidGTIN checks the GS1 check digit shared by EAN-8, UPC-A and EAN-13:
+// counting from the check digit, digits are weighted 1, 3, 1, 3, ... and the
+// sum must be a multiple of 10.
+func isValidGTIN(str string) bool {
+	if str == "" {
+		return false
+	}
+
+	sum := 0
+	for i := len(str) - 1; i >= 0; i-- {
+		c := str[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		weight := 1
+		if (len(str)-1-i)%2 == 1 {
+			weight = 3
+		}
+		sum += int(c-'0') * weight
+	}
+
+	return sum%10 == 0
+}
+
+// shannonEntropy returns the total Shannon entropy of str in bits: the
+// entropy per character, computed from the character frequencies, multiplied
+// by the number of characters.
+func shannonEntropy(str string) float64 {
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range str {
+		counts[r]++
+		total++
+	}
+	if total == 0 {
+		return 0
+	}
+
+	perChar := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		perChar -= p * math.Log2(p)
+	}
+
+	return perChar * float64(total)
+}
+
+// leetReplacer maps common leetspeak characters back to letters.
+var leetReplacer = strings.NewReplacer(
+	"0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t",
+	"@", "a", "$", "s", "!", "i", "|", "l",
+)
+
 // isValidBase64 checks if the string is valid base64 encoding.
 func isValidBase64(str string) bool {
 	if str == "" {
@@ -719,3 +1502,54 @@ func isValidBase64(str string) bool {
 
 	return err == nil
 }
+
+// allowedValuesParams builds the params of an "in" error: the joined values
+// for the message, the allowed list and, if there is a close match for str,
+// a suggestion.
+func allowedValuesParams(str string, values []string) map[string]interface{} {
+	params := map[string]interface{}{
+		"values":  strings.Join(values, ", "),
+		"allowed": values,
+	}
+	if suggestion, ok := closestMatch(str, values); ok {
+		params["suggestion"] = suggestion
+	}
+	return params
+}
+
+// closestMatch returns the candidate with the smallest Levenshtein distance
+// to str, compared case-insensitively. Matches that differ in more than a third
+// of the candidate's characters (at least one) are not considered close.
+func closestMatch(str string, candidates []string) (string, bool) {
+	best, bestDistance := "", -1
+	for _, candidate := range candidates {
+		distance := levenshtein(strings.ToLower(str), strings.ToLower(candidate))
+		limit := max(utf8.RuneCountInString(candidate)/3, 1)
+		if distance <= limit && (bestDistance < 0 || distance < bestDistance) {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best, bestDistance >= 0
+}
+
+// levenshtein returns the edit distance between a and b, counted in runes.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
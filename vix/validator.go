@@ -98,9 +98,12 @@ import (
 	"reflect"
 	"regexp"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/c3p0-box/utils/erm"
+	"golang.org/x/text/language"
 )
 
 // =============================================================================
@@ -139,12 +142,35 @@ type ValidatorChain interface {
 // It contains the original value, field name, and collects validation errors
 // as a slice of erm.Error instances for unified error handling.
 // Internationalization is handled automatically through the erm package.
-// All validation errors use HTTP 400 Bad Request status code.
+// The error returned by Error carries the status set by WithStatus, or the
+// package-wide ValidationStatus (400 Bad Request by default).
 type ValidationResult struct {
 	Value     interface{}
 	FieldName string
 	errors    []erm.Error // Slice of validation errors
 	IsValid   bool
+	locale    language.Tag // Language of Error and ErrMap; English if unset
+	status    int          // HTTP status of Error; ValidationStatus if 0
+}
+
+// validationStatus holds the status set by SetValidationStatus; 0 means 400.
+var validationStatus atomic.Int32
+
+// SetValidationStatus sets the HTTP status code carried by the errors that
+// validation results and orchestrators return, e.g. 422 to separate failed
+// validation from malformed requests (400). Zero restores the default 400.
+// It is meant to be called once at startup.
+func SetValidationStatus(code int) {
+	validationStatus.Store(int32(code))
+}
+
+// ValidationStatus returns the HTTP status code of validation errors, as set
+// by SetValidationStatus.
+func ValidationStatus() int {
+	if code := validationStatus.Load(); code != 0 {
+		return int(code)
+	}
+	return http.StatusBadRequest
 }
 
 // NewValidationResult creates a new ValidationResult with the given value and field name.
@@ -161,16 +187,33 @@ func NewValidationResult(value interface{}, fieldName string) *ValidationResult
 	}
 }
 
+// WithStatus overrides the HTTP status code of the error returned by Error
+// for this result only. Zero falls back to ValidationStatus.
+func (vr *ValidationResult) WithStatus(code int) *ValidationResult {
+	vr.status = code
+	return vr
+}
+
+// statusCode returns the HTTP status code of the result's error container.
+func (vr *ValidationResult) statusCode() int {
+	if vr.status != 0 {
+		return vr.status
+	}
+	return ValidationStatus()
+}
+
 // AddError adds a validation error to the result.
 func (vr *ValidationResult) AddError(err error) *ValidationResult {
 	if err != nil {
-		if ermErr, ok := err.(erm.Error); ok {
-			vr.errors = append(vr.errors, ermErr)
-		} else {
+		ermErr, ok := err.(erm.Error)
+		if !ok {
 			// Convert regular error to erm.Error
-			ermErr := erm.New(http.StatusBadRequest, err.Error(), err)
-			vr.errors = append(vr.errors, ermErr)
+			ermErr = erm.New(vr.statusCode(), err.Error(), err)
 		}
+		if vr.locale != language.Und {
+			ermErr = ermErr.WithLocale(vr.locale)
+		}
+		vr.errors = append(vr.errors, ermErr)
 		vr.IsValid = false
 	}
 	return vr
@@ -187,10 +230,7 @@ func (vr *ValidationResult) Error() error {
 		return nil
 	}
 
-	// Create a container error and add all errors as children
-	container := erm.New(http.StatusBadRequest, "", nil)
-	container.AddErrors(vr.errors)
-	return container
+	return vr.container()
 }
 
 // AllErrors returns all validation errors.
@@ -202,6 +242,18 @@ func (vr *ValidationResult) AllErrors() []erm.Error {
 	return vr.errors
 }
 
+// Errors returns a copy of the individual validation errors, in the order
+// they were added. Unlike ErrMap, each erm.Error keeps its MessageKey,
+// FieldName and Params, which makes it suitable for building custom error
+// responses. It returns nil if validation passed.
+func (vr *ValidationResult) Errors() []erm.Error {
+	if len(vr.errors) == 0 {
+		return nil
+	}
+
+	return append([]erm.Error(nil), vr.errors...)
+}
+
 // ErrMap returns a map of field names to error messages.
 // Returns nil if validation passed, otherwise returns the structured error map.
 func (vr *ValidationResult) ErrMap() map[string][]string {
@@ -209,10 +261,135 @@ func (vr *ValidationResult) ErrMap() map[string][]string {
 		return nil
 	}
 
-	// Create a container error with all errors and use its ErrMap method
-	container := erm.New(http.StatusBadRequest, "", nil)
+	// Use the container's ErrMap method
+	return vr.container().ErrMap()
+}
+
+// MergeWith adds the errors of child to vr with their field names prefixed
+// by prefix and a dot, so that a separately validated nested object ends up
+// under dotted keys such as "billing_address.street" in ErrMap. Nested error
+// containers are flattened the same way erm does it. An empty prefix merges
+// the errors unchanged. A nil or valid child adds nothing.
+//
+// Example:
+//
+//	address := vix.Form().
+//		Add(vix.String(req.Billing.Street, "street").Required()).
+//		Validate()
+//	result.MergeWith("billing_address", address)
+func (vr *ValidationResult) MergeWith(prefix string, child *ValidationResult) *ValidationResult {
+	if child == nil || child.Valid() {
+		return vr
+	}
+
+	flat := erm.New(http.StatusBadRequest, "", nil)
+	flat.AddErrors(child.errors)
+	for _, err := range flat.AllErrors() {
+		if prefix != "" {
+			field := err.FieldName()
+			if field == "" {
+				field = child.FieldName
+			}
+			err = err.WithFieldName(prefix + "." + field)
+		}
+		vr.AddError(err)
+	}
+	return vr
+}
+
+// FirstErrMap returns a map of field names to the first error message of
+// each field, for UIs that show a single message per input. Returns nil if
+// validation passed.
+func (vr *ValidationResult) FirstErrMap() map[string]string {
+	return firstErrors(vr.ErrMap())
+}
+
+// firstErrors keeps the first message of each field of an ErrMap.
+func firstErrors(errMap map[string][]string) map[string]string {
+	if errMap == nil {
+		return nil
+	}
+
+	first := make(map[string]string, len(errMap))
+	for field, messages := range errMap {
+		if len(messages) > 0 {
+			first[field] = messages[0]
+		}
+	}
+	return first
+}
+
+// container creates an error container with all errors as children,
+// rendered in the result's locale.
+func (vr *ValidationResult) container() erm.Error {
+	container := erm.New(vr.statusCode(), "", nil)
 	container.AddErrors(vr.errors)
-	return container.ErrMap()
+	if vr.locale != language.Und {
+		container = container.WithLocale(vr.locale)
+	}
+	return container
+}
+
+// setLocale sets the language of the result's messages, including the
+// errors already collected.
+func (vr *ValidationResult) setLocale(tag language.Tag) {
+	vr.locale = tag
+	for i, err := range vr.errors {
+		vr.errors[i] = err.WithLocale(tag)
+	}
+}
+
+// =============================================================================
+// Result Pooling
+// =============================================================================
+
+// resultPool holds released ValidationResults for reuse.
+var resultPool = sync.Pool{
+	New: func() interface{} { return new(ValidationResult) },
+}
+
+// AcquireValidationResult returns an empty ValidationResult from a pool,
+// reusing the error slice of a previously released result. It is an opt-in
+// alternative to NewValidationResult for hot paths that validate many
+// requests; pair every call with Release.
+//
+// Use-after-release is a bug that the pool cannot detect: once Release is
+// called, neither the result nor the slice returned by its AllErrors may be
+// used, since both may already belong to another request. The errors
+// returned by Error, Errors and ErrMap are copies and stay valid.
+//
+// Example:
+//
+//	result := vix.AcquireValidationResult(nil, "signup")
+//	defer result.Release()
+//
+//	vix.String(req.Email, "email").Required().Email().ValidateInto(result)
+//	vix.Int(req.Age, "age").Min(18).ValidateInto(result)
+//	if err := result.Error(); err != nil {
+//		return err
+//	}
+func AcquireValidationResult(value interface{}, fieldName string) *ValidationResult {
+	if fieldName == "" {
+		fieldName = "value"
+	}
+
+	vr := resultPool.Get().(*ValidationResult)
+	vr.Value = value
+	vr.FieldName = fieldName
+	vr.IsValid = true
+	if vr.errors == nil {
+		vr.errors = []erm.Error{}
+	}
+	return vr
+}
+
+// Release resets the result and returns it to the pool used by
+// AcquireValidationResult. The result must not be used afterwards. Results
+// created with NewValidationResult may be released too.
+func (vr *ValidationResult) Release() {
+	clear(vr.errors) // drop references so released errors can be collected
+	*vr = ValidationResult{errors: vr.errors[:0]}
+	resultPool.Put(vr)
 }
 
 // =============================================================================
@@ -223,19 +400,20 @@ func (vr *ValidationResult) ErrMap() map[string][]string {
 type BaseValidator struct {
 	value      interface{}
 	fieldName  string
-	result     *ValidationResult
+	result     *ValidationResult // Created on first error or Result call
 	negated    bool
 	conditions []func() bool
 }
 
 // NewBaseValidator creates a new BaseValidator.
+//
+// The ValidationResult is allocated lazily, so a chain whose rules all pass
+// and that ends with Validate allocates nothing beyond the validator itself.
 func NewBaseValidator(value interface{}, fieldName string) *BaseValidator {
 	return &BaseValidator{
-		value:      value,
-		fieldName:  fieldName,
-		result:     NewValidationResult(value, fieldName),
-		negated:    false,
-		conditions: []func() bool{},
+		value:     value,
+		fieldName: fieldName,
+		negated:   false,
 	}
 }
 
@@ -257,6 +435,24 @@ func (bv *BaseValidator) Unless(condition func() bool) *BaseValidator {
 	return bv
 }
 
+// Optional marks the value as optional: if it is empty (nil, a blank string,
+// zero, false or an empty collection), all subsequent rules are skipped and
+// the field is valid. Rules chained before Optional still apply.
+func (bv *BaseValidator) Optional() *BaseValidator {
+	if isEmpty(bv.value) {
+		bv.conditions = append(bv.conditions, func() bool { return false })
+	}
+	return bv
+}
+
+// Locale sets the language in which Validate and the result's Error and
+// ErrMap render messages, e.g. the language negotiated from the request's
+// Accept-Language header. The global localizers are not modified.
+func (bv *BaseValidator) Locale(tag language.Tag) *BaseValidator {
+	bv.Result().setLocale(tag)
+	return bv
+}
+
 // shouldValidate checks if validation should run based on conditions.
 func (bv *BaseValidator) shouldValidate() bool {
 	for _, condition := range bv.conditions {
@@ -289,23 +485,65 @@ func (bv *BaseValidator) addValidationError(messageKey string, params map[string
 		}
 	}
 
-	bv.result.AddError(err)
+	bv.Result().AddError(err)
 	bv.negated = false // Reset negation after use
 }
 
 // Validate returns the validation result.
 func (bv *BaseValidator) Validate() error {
+	// Fast path: no rule has failed, so no result was ever created
+	if bv.result == nil {
+		return nil
+	}
 	if !bv.result.Valid() {
 		return bv.result.Error()
 	}
 	return nil
 }
 
+// IsValid reports whether every rule of the chain passed. It is a terminal
+// for conditionals that do not need the error details and, like Validate,
+// allocates nothing when the chain passes.
+//
+// Example:
+//
+//	if !vix.String(name, "name").Required().IsValid() {
+//		name = "anonymous"
+//	}
+func (bv *BaseValidator) IsValid() bool {
+	return bv.result == nil || bv.result.Valid()
+}
+
 // Result returns the full validation result.
 func (bv *BaseValidator) Result() *ValidationResult {
+	if bv.result == nil {
+		bv.result = NewValidationResult(bv.value, bv.fieldName)
+	}
 	return bv.result
 }
 
+// ValidateInto appends this validator's errors to result, so that several
+// fields can be validated into one shared result with a combined ErrMap.
+// Each error keeps its own field name. Call it once per validator, after all
+// rules have been chained; calling it again appends the errors again.
+//
+// Example:
+//
+//	result := vix.NewValidationResult(nil, "signup")
+//	vix.String(req.Email, "email").Required().Email().ValidateInto(result)
+//	vix.Int(req.Age, "age").Min(18).ValidateInto(result)
+//	if !result.Valid() {
+//		return result.ErrMap() // {"email": [...], "age": [...]}
+//	}
+func (bv *BaseValidator) ValidateInto(result *ValidationResult) {
+	if bv.result == nil {
+		return
+	}
+	for _, err := range bv.result.AllErrors() {
+		result.AddError(err)
+	}
+}
+
 // Custom validates using a custom validation function.
 // The function receives both the value being validated and the field name,
 // allowing for more contextual error messages.
@@ -338,10 +576,10 @@ func (bv *BaseValidator) Custom(fn func(value interface{}, fieldName string) err
 			bv.negated = false
 			return bv
 		}
-		bv.result.AddError(err)
+		bv.Result().AddError(err)
 	} else if bv.negated {
 		// If negated and custom validation passed, it's invalid
-		bv.result.AddError(erm.NewValidationError("validation.custom_negated", bv.fieldName, bv.value))
+		bv.Result().AddError(erm.NewValidationError("validation.custom_negated", bv.fieldName, bv.value))
 	}
 
 	bv.negated = false
@@ -352,13 +590,17 @@ func (bv *BaseValidator) Custom(fn func(value interface{}, fieldName string) err
 // Constants and Patterns
 // =============================================================================
 
-// Common validation patterns
+// Common validation patterns. They are compiled once at package
+// initialization and shared by the built-in String validators; none of the
+// validators compiles a pattern per call.
 var (
 	EmailRegex        = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
 	URLRegex          = regexp.MustCompile(`^https?://[^\s/$.?#].[^\s]*$`)
 	NumericRegex      = regexp.MustCompile(`^[0-9]+$`)
 	AlphaRegex        = regexp.MustCompile(`^[a-zA-Z]+$`)
 	AlphaNumericRegex = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
+	UUIDRegex         = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	SlugRegex         = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
 )
 
 // =============================================================================
@@ -430,6 +672,10 @@ func toString(value interface{}) string {
 	if value == nil {
 		return ""
 	}
+	// Avoid fmt's allocation for the common case
+	if str, ok := value.(string); ok {
+		return str
+	}
 	return fmt.Sprintf("%v", value)
 }
 
@@ -3,12 +3,17 @@ package vix
 import (
 	"fmt"
 	"math"
+	"net/http"
 	"regexp"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/c3p0-box/utils/erm"
+	"github.com/c3p0-box/utils/i18n"
+	"github.com/c3p0-box/utils/set"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
 )
 
 // =============================================================================
@@ -147,6 +152,49 @@ func TestStringValidatorEmail(t *testing.T) {
 	}
 }
 
+func TestStringValidatorNormalizeEmail(t *testing.T) {
+	custom := EmailNormalizationConfig{
+		Providers: map[string]EmailProviderRule{
+			"example.com": {StripTags: true, TagSeparator: "-"},
+		},
+	}
+	lowercaseAll := EmailNormalizationConfig{LowercaseLocal: true}
+
+	tests := []struct {
+		name     string
+		value    string
+		config   *EmailNormalizationConfig
+		expected string
+	}{
+		{"gmail dots and tag", "John.Doe+news@GMail.com", nil, "johndoe@gmail.com"},
+		{"googlemail alias", "john.doe@googlemail.com", nil, "johndoe@gmail.com"},
+		{"outlook keeps dots", "John.Doe+x@outlook.com", nil, "john.doe@outlook.com"},
+		{"unknown provider keeps local part", "John.Doe+x@Example.ORG", nil, "John.Doe+x@example.org"},
+		{"lowercase all local parts", "John.Doe+x@Example.ORG", &lowercaseAll, "john.doe+x@example.org"},
+		{"not an email", "not-an-email", nil, "not-an-email"},
+		{"custom separator", "Bob-sales@example.com", &custom, "Bob@example.com"},
+		{"custom config ignores gmail", "a.b+c@gmail.com", &custom, "a.b+c@gmail.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := String(tt.value, "email")
+			if tt.config != nil {
+				v.NormalizeEmailWithConfig(*tt.config)
+			} else {
+				v.NormalizeEmail()
+			}
+			if got := v.Result().Value; got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	if err := String("John.Doe+news@GMail.com", "email").NormalizeEmail().Email().Validate(); err != nil {
+		t.Errorf("unexpected error after normalization: %v", err)
+	}
+}
+
 func TestStringValidatorURL(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -199,6 +247,203 @@ func TestStringValidatorNumeric(t *testing.T) {
 	}
 }
 
+func TestStringValidatorLuhn(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		shouldErr bool
+	}{
+		{"valid IMEI", "490154203237518", false},
+		{"valid reference number", "79927398713", false},
+		{"invalid checksum", "79927398710", true},
+		{"non-digit", "7992-7398713", true},
+		{"single digit", "0", true},
+		{"empty string", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := String(tt.value, "imei").Luhn().Validate()
+			if tt.shouldErr && err == nil {
+				t.Error("expected error but got none")
+			}
+			if !tt.shouldErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	if err := String("79927398713", "imei").Not().Luhn().Validate(); err == nil {
+		t.Error("expected negated Luhn to fail for a valid number")
+	}
+}
+
+func TestStringValidatorISBN(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		validate  func(*StringValidator) *StringValidator
+		shouldErr bool
+	}{
+		{"ISBN-10", "0306406152", (*StringValidator).ISBN, false},
+		{"ISBN-10 with X", "0-8044-2957-X", (*StringValidator).ISBN, false},
+		{"ISBN-13 with hyphens", "978-0-306-40615-7", (*StringValidator).ISBN, false},
+		{"ISBN-13 with spaces", "978 0 306 40615 7", (*StringValidator).ISBN, false},
+		{"bad checksum", "978-0-306-40615-8", (*StringValidator).ISBN, true},
+		{"X not last", "080442X957", (*StringValidator).ISBN, true},
+		{"wrong length", "12345", (*StringValidator).ISBN, true},
+		{"ISBN10 rejects ISBN-13", "9780306406157", (*StringValidator).ISBN10, true},
+		{"ISBN10 accepts ISBN-10", "0306406152", (*StringValidator).ISBN10, false},
+		{"ISBN13 rejects ISBN-10", "0306406152", (*StringValidator).ISBN13, true},
+		{"ISBN13 accepts ISBN-13", "9780306406157", (*StringValidator).ISBN13, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validate(String(tt.value, "isbn")).Validate()
+			if tt.shouldErr && err == nil {
+				t.Error("expected error but got none")
+			}
+			if !tt.shouldErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestStringValidatorBarcodes(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		validate  func(*StringValidator) *StringValidator
+		shouldErr bool
+	}{
+		{"EAN-13", "4006381333931", (*StringValidator).EAN, false},
+		{"EAN-8", "73513537", (*StringValidator).EAN, false},
+		{"EAN-13 bad check digit", "4006381333932", (*StringValidator).EAN, true},
+		{"EAN wrong length", "036000291452", (*StringValidator).EAN, true},
+		{"EAN non-digit", "400638133393A", (*StringValidator).EAN, true},
+		{"UPC-A", "036000291452", (*StringValidator).UPC, false},
+		{"UPC-A bad check digit", "036000291453", (*StringValidator).UPC, true},
+		{"UPC wrong length", "4006381333931", (*StringValidator).UPC, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validate(String(tt.value, "barcode")).Validate()
+			if tt.shouldErr && err == nil {
+				t.Error("expected error but got none")
+			}
+			if !tt.shouldErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestStringValidatorPostalCode(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		country   string
+		shouldErr bool
+	}{
+		{"US 5 digit", "94105", "US", false},
+		{"US ZIP+4", "94105-1234", "US", false},
+		{"US too short", "9410", "US", true},
+		{"GB", "SW1A 1AA", "GB", false},
+		{"UK alias, lowercase", "ec1a1bb", "uk", false},
+		{"GB invalid", "12345", "GB", true},
+		{"DE", "10115", "DE", false},
+		{"DE invalid", "1011", "DE", true},
+		{"CA", "K1A 0B1", "CA", false},
+		{"NL", "1012 AB", "NL", false},
+		{"unknown country lenient", "AB-1234", "ZZ", false},
+		{"unknown country too long", "12345678901", "ZZ", true},
+		{"unknown country symbols", "12#45", "ZZ", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := String(tt.value, "zip").PostalCode(tt.country).Validate()
+			if tt.shouldErr && err == nil {
+				t.Error("expected error but got none")
+			}
+			if !tt.shouldErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestStringValidatorMinEntropy(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		bits      float64
+		shouldErr bool
+	}{
+		{"repeated character", "aaaaaaaaaaaaaaaa", 1, true},
+		{"short pattern", "abababab", 16, true},
+		{"random hex", "9f86d081884c7d65", 40, false},
+		{"empty", "", 1, true},
+		{"zero threshold", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := String(tt.value, "secret").MinEntropy(tt.bits).Validate()
+			if tt.shouldErr && err == nil {
+				t.Error("expected error but got none")
+			}
+			if !tt.shouldErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	if got := shannonEntropy("abab"); math.Abs(got-4) > 1e-9 {
+		t.Errorf("expected 4 bits for \"abab\", got %v", got)
+	}
+}
+
+func TestStringValidatorBlocklist(t *testing.T) {
+	list := []string{"admin", "Root"}
+
+	tests := []struct {
+		name      string
+		validate  func() *StringValidator
+		shouldErr bool
+		word      string
+	}{
+		{"clean", func() *StringValidator { return String("johndoe", "username").Blocklist(list) }, false, ""},
+		{"case-insensitive", func() *StringValidator { return String("SuperAdmin", "username").Blocklist(list) }, true, "admin"},
+		{"list word case", func() *StringValidator { return String("groot", "username").Blocklist(list) }, true, "Root"},
+		{"leetspeak ignored", func() *StringValidator { return String("4dm1n", "username").Blocklist(list) }, false, ""},
+		{"leetspeak normalized", func() *StringValidator { return String("4dm1n", "username").BlocklistLeet(list) }, true, "admin"},
+		{"variadic", func() *StringValidator { return String("hello world", "bio").NotContainsAny("WORLD") }, true, "WORLD"},
+		{"negated", func() *StringValidator { return String("johndoe", "username").Not().Blocklist(list) }, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.validate().Result().AllErrors()
+			if !tt.shouldErr {
+				if len(errs) != 0 {
+					t.Errorf("unexpected errors: %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("expected 1 error, got %d", len(errs))
+			}
+			if tt.word != "" && errs[0].Params()["word"] != tt.word {
+				t.Errorf("expected matched word %q, got %v", tt.word, errs[0].Params()["word"])
+			}
+		})
+	}
+}
+
 func TestStringValidatorAlpha(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -305,6 +550,64 @@ func TestStringValidatorIn(t *testing.T) {
 	}
 }
 
+func TestStringValidatorInAllowedParams(t *testing.T) {
+	allowed := []string{"pending", "active", "archived"}
+	tests := []struct {
+		name           string
+		value          string
+		wantSuggestion string
+	}{
+		{"typo", "actve", "active"},
+		{"case difference", "Archived", "archived"},
+		{"no close match", "deleted", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := String(tt.value, "status").In(allowed...).Result().AllErrors()
+			if len(errs) != 1 {
+				t.Fatalf("expected 1 error, got %d", len(errs))
+			}
+			params := errs[0].Params()
+			if got, ok := params["allowed"].([]string); !ok || strings.Join(got, ",") != strings.Join(allowed, ",") {
+				t.Errorf("expected allowed param %v, got %v", allowed, params["allowed"])
+			}
+			suggestion, _ := params["suggestion"].(string)
+			if suggestion != tt.wantSuggestion {
+				t.Errorf("expected suggestion %q, got %q", tt.wantSuggestion, suggestion)
+			}
+		})
+	}
+
+	t.Run("number In", func(t *testing.T) {
+		errs := Int(3, "level").In(1, 2).Result().AllErrors()
+		if len(errs) != 1 {
+			t.Fatalf("expected 1 error, got %d", len(errs))
+		}
+		if got, ok := errs[0].Params()["allowed"].([]int); !ok || len(got) != 2 {
+			t.Errorf("expected allowed param [1 2], got %v", errs[0].Params()["allowed"])
+		}
+	})
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"héllo", "hello", 1},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
 func TestStringValidatorNotIn(t *testing.T) {
 	invalidValues := []string{"admin", "root", "system"}
 	tests := []struct {
@@ -674,6 +977,272 @@ func TestNumberValidatorIn(t *testing.T) {
 	}
 }
 
+func TestStringValidatorNumericLength(t *testing.T) {
+	tests := []struct {
+		name      string
+		validate  func() error
+		shouldErr bool
+	}{
+		{"valid PIN", func() error { return String("0123", "pin").NumericLength(4).Validate() }, false},
+		{"too short", func() error { return String("123", "pin").NumericLength(4).Validate() }, true},
+		{"too long", func() error { return String("12345", "pin").NumericLength(4).Validate() }, true},
+		{"not digits", func() error { return String("12a4", "pin").NumericLength(4).Validate() }, true},
+		{"non-ASCII digits", func() error { return String("١٢٣٤", "pin").NumericLength(4).Validate() }, true},
+		{"empty", func() error { return String("", "pin").NumericLength(0).Validate() }, true},
+		{"negated", func() error { return String("1234", "pin").Not().NumericLength(4).Validate() }, true},
+		{"between valid", func() error { return String("12345678", "account").NumericLengthBetween(8, 12).Validate() }, false},
+		{"between too short", func() error { return String("1234567", "account").NumericLengthBetween(8, 12).Validate() }, true},
+		{"between not digits", func() error { return String("1234-5678", "account").NumericLengthBetween(8, 12).Validate() }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validate()
+			if tt.shouldErr && err == nil {
+				t.Error("expected error but got none")
+			}
+			if !tt.shouldErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	err := String("12a", "pin").NumericLength(4).Validate()
+	if err == nil || err.Error() != "pin must be exactly 4 digits" {
+		t.Errorf("unexpected message: %v", err)
+	}
+	err = String("123", "account").NumericLengthBetween(8, 12).Validate()
+	if err == nil || err.Error() != "account must be between 8 and 12 digits" {
+		t.Errorf("unexpected message: %v", err)
+	}
+	err = String("1234", "pin").Not().NumericLength(4).Validate()
+	if err == nil || err.Error() != "pin must not be exactly 4 digits" {
+		t.Errorf("unexpected message: %v", err)
+	}
+	err = String("12345678", "account").Not().NumericLengthBetween(8, 12).Validate()
+	if err == nil || err.Error() != "account must not be between 8 and 12 digits" {
+		t.Errorf("unexpected message: %v", err)
+	}
+}
+
+func TestNumberValidatorWhole(t *testing.T) {
+	tests := []struct {
+		name      string
+		validate  func() error
+		shouldErr bool
+	}{
+		{"whole float", func() error { return Float64(3.0, "qty").Whole().Validate() }, false},
+		{"negative whole float", func() error { return Float64(-42, "qty").Whole().Validate() }, false},
+		{"large whole float", func() error { return Float64(1e300, "qty").Whole().Validate() }, false},
+		{"fractional float", func() error { return Float64(3.5, "qty").Whole().Validate() }, true},
+		{"tiny fraction", func() error { return Float64(1e-300, "qty").Whole().Validate() }, true},
+		{"float32 fraction", func() error { return Float32(2.25, "qty").Whole().Validate() }, true},
+		{"NaN", func() error { return Float64(math.NaN(), "qty").Whole().Validate() }, true},
+		{"infinity", func() error { return Float64(math.Inf(1), "qty").Whole().Validate() }, true},
+		{"integer type", func() error { return Int(7, "qty").Whole().Validate() }, false},
+		{"IsInteger alias", func() error { return Float64(0.1, "qty").IsInteger().Validate() }, true},
+		{"negated", func() error { return Float64(2, "qty").Not().Whole().Validate() }, true},
+		{"negated fraction", func() error { return Float64(2.5, "qty").Not().Whole().Validate() }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validate()
+			if tt.shouldErr && err == nil {
+				t.Error("expected error but got none")
+			}
+			if !tt.shouldErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	err := Float64(1.5, "qty").Whole().Validate()
+	if err == nil || err.Error() != "qty must be a whole number" {
+		t.Errorf("unexpected message: %v", err)
+	}
+
+	for name, result := range map[string]*ValidationResult{
+		"Whole":     Float64(2, "qty").Not().Whole().Result(),
+		"IsInteger": Float64(2, "qty").Not().IsInteger().Result(),
+	} {
+		errs := result.AllErrors()
+		if len(errs) != 1 || errs[0].MessageKey() != erm.MsgNotWholeNumber || errs[0].Error() != "qty must not be a whole number" {
+			t.Errorf("%s: expected %s message, got %v", name, erm.MsgNotWholeNumber, errs)
+		}
+	}
+}
+
+func TestNumericPrecision(t *testing.T) {
+	tests := []struct {
+		name      string
+		validate  func() error
+		shouldErr bool
+	}{
+		{"fits decimal(10,2)", func() error { return Float64(12345678.99, "amount").NumericPrecision(8, 2).Validate() }, false},
+		{"too many integer digits", func() error { return Float64(123456789.5, "amount").NumericPrecision(8, 2).Validate() }, true},
+		{"too many fraction digits", func() error { return Float64(1.234, "amount").NumericPrecision(8, 2).Validate() }, true},
+		{"negative", func() error { return Float64(-99.99, "amount").NumericPrecision(2, 2).Validate() }, false},
+		{"zero integer part", func() error { return Float64(0.5, "amount").NumericPrecision(0, 1).Validate() }, false},
+		{"large float without exponent", func() error { return Float64(1e21, "amount").NumericPrecision(20, 0).Validate() }, true},
+		{"float32", func() error { return Float32(0.1, "amount").NumericPrecision(0, 1).Validate() }, false},
+		{"int", func() error { return Int(123456, "qty").NumericPrecision(5, 0).Validate() }, true},
+		{"uint", func() error { return Uint64(math.MaxUint64, "qty").NumericPrecision(20, 0).Validate() }, false},
+		{"NaN", func() error { return Float64(math.NaN(), "amount").NumericPrecision(8, 2).Validate() }, true},
+		{"negated", func() error { return Float64(1.5, "amount").Not().NumericPrecision(8, 2).Validate() }, true},
+		{"string fits", func() error { return String("00123.450", "amount").NumericPrecision(3, 2).Validate() }, false},
+		{"string too long", func() error { return String("1234.5", "amount").NumericPrecision(3, 2).Validate() }, true},
+		{"string fraction only", func() error { return String("-.25", "amount").NumericPrecision(0, 2).Validate() }, false},
+		{"string not numeric", func() error { return String("12a", "amount").NumericPrecision(8, 2).Validate() }, true},
+		{"string double sign", func() error { return String("--1", "amount").NumericPrecision(8, 2).Validate() }, true},
+		{"string after normalization", func() error {
+			return String("1.234,5", "amount").NormalizeNumber(NumberFormatComma).NumericPrecision(4, 1).Validate()
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validate()
+			if tt.shouldErr && err == nil {
+				t.Error("expected error but got none")
+			}
+			if !tt.shouldErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	err := Float64(1.234, "amount").NumericPrecision(8, 2).Validate()
+	if err == nil || err.Error() != "amount must have at most 8 digits before and 2 digits after the decimal point" {
+		t.Errorf("unexpected message: %v", err)
+	}
+
+	negated := map[string]*ValidationResult{
+		"number": Float64(1.5, "amount").Not().NumericPrecision(8, 2).Result(),
+		"string": String("1.5", "amount").Not().NumericPrecision(8, 2).Result(),
+	}
+	expected := "amount must have more than 8 digits before or 2 digits after the decimal point"
+	for name, result := range negated {
+		errs := result.AllErrors()
+		if len(errs) != 1 || errs[0].MessageKey() != erm.MsgNotNumericPrecision || errs[0].Error() != expected {
+			t.Errorf("%s: expected %s %q, got %v", name, erm.MsgNotNumericPrecision, expected, errs)
+		}
+	}
+}
+
+type testStatus int
+
+const (
+	testStatusUnset testStatus = iota
+	testStatusDraft
+	testStatusPublished
+)
+
+func (s testStatus) String() string {
+	return [...]string{"unset", "draft", "published"}[s]
+}
+
+func TestEnum(t *testing.T) {
+	statuses := []testStatus{testStatusDraft, testStatusPublished}
+	tests := []struct {
+		name        string
+		validate    func() *ValidationResult
+		expectedKey string
+	}{
+		{"defined constant", func() *ValidationResult { return Enum(testStatusDraft, "status", statuses...).Result() }, ""},
+		{"undefined value", func() *ValidationResult { return Enum(testStatus(7), "status", statuses...).Result() }, erm.MsgEnum},
+		{"zero value is checked", func() *ValidationResult { return Enum(testStatusUnset, "status", statuses...).Result() }, erm.MsgEnum},
+		{"optional zero value", func() *ValidationResult { return Enum(testStatusUnset, "status", statuses...).Optional().Result() }, ""},
+		{"optional set value", func() *ValidationResult { return Enum(testStatus(7), "status", statuses...).Optional().Result() }, erm.MsgEnum},
+		{"negated member", func() *ValidationResult { return Enum(testStatusDraft, "status", statuses...).Not().Result() }, erm.MsgNotEnum},
+		{"condition after constructor", func() *ValidationResult {
+			return Enum(testStatus(7), "status", statuses...).When(func() bool { return false }).Result()
+		}, ""},
+		{"strings", func() *ValidationResult { return Enum("xl", "size", "s", "m", "l").Result() }, erm.MsgEnum},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.validate().AllErrors()
+			if tt.expectedKey == "" {
+				if len(errs) != 0 {
+					t.Errorf("unexpected errors: %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || errs[0].MessageKey() != tt.expectedKey {
+				t.Errorf("expected one %s error, got %v", tt.expectedKey, errs)
+			}
+		})
+	}
+
+	t.Run("terminals run the rule once", func(t *testing.T) {
+		v := Enum(testStatus(7), "status", statuses...)
+		if v.IsValid() || v.Validate() == nil {
+			t.Fatal("expected invalid enum")
+		}
+		errs := v.Result().AllErrors()
+		if len(errs) != 1 {
+			t.Fatalf("expected 1 error, got %d", len(errs))
+		}
+		params := errs[0].Params()
+		if params["values"] != "draft, published" || fmt.Sprint(params["allowed"]) != "[draft published]" {
+			t.Errorf("unexpected params: %v", params)
+		}
+		if got := errs[0].Error(); got != "status must be one of: draft, published" {
+			t.Errorf("unexpected message: %q", got)
+		}
+	})
+
+	t.Run("orchestrator", func(t *testing.T) {
+		if Is(Enum(testStatus(7), "status", statuses...)).Valid() {
+			t.Error("expected orchestrated enum validation to fail")
+		}
+	})
+}
+
+func TestNumberValidatorInSet(t *testing.T) {
+	allowed := set.New[int]()
+	allowed.AddList([]int{8, 1, 5})
+
+	tests := []struct {
+		name      string
+		validate  func() error
+		shouldErr bool
+	}{
+		{"member", func() error { return Int(5, "test").InSet(allowed).Validate() }, false},
+		{"non-member", func() error { return Int(4, "test").InSet(allowed).Validate() }, true},
+		{"empty set", func() error { return Int(4, "test").InSet(set.New[int]()).Validate() }, true},
+		{"not in set member", func() error { return Int(5, "test").NotInSet(allowed).Validate() }, true},
+		{"not in set non-member", func() error { return Int(4, "test").NotInSet(allowed).Validate() }, false},
+		{"negated member", func() error { return Int(5, "test").Not().InSet(allowed).Validate() }, true},
+		{"negated not in set member", func() error { return Int(5, "test").Not().NotInSet(allowed).Validate() }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validate()
+			if tt.shouldErr && err == nil {
+				t.Error("expected error but got none")
+			}
+			if !tt.shouldErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	t.Run("allowed values are sorted", func(t *testing.T) {
+		errs := Int(4, "test").InSet(allowed).Result().AllErrors()
+		if len(errs) != 1 {
+			t.Fatalf("expected 1 error, got %d", len(errs))
+		}
+		params := errs[0].Params()
+		if params["values"] != "1, 5, 8" || fmt.Sprint(params["allowed"]) != "[1 5 8]" {
+			t.Errorf("unexpected params: %v", params)
+		}
+	})
+}
+
 func TestNumberValidatorEven(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -752,6 +1321,163 @@ func TestNumberValidatorMultipleOf(t *testing.T) {
 	}
 }
 
+func TestNumberValidatorMultipleOfAllAny(t *testing.T) {
+	tests := []struct {
+		name        string
+		result      *ValidationResult
+		expectedKey string
+	}{
+		{"all: multiple of both", Int(30, "qty").MultipleOfAll(3, 5).Result(), ""},
+		{"all: multiple of one", Int(9, "qty").MultipleOfAll(3, 5).Result(), erm.MsgMultipleOfAll},
+		{"all: zero divisor", Int(15, "qty").MultipleOfAll(3, 0).Result(), erm.MsgDivisorZero},
+		{"all: negated", Int(15, "qty").Not().MultipleOfAll(3, 5).Result(), erm.MsgNotMultipleOfAll},
+		{"any: multiple of one", Int(10, "qty").MultipleOfAny(3, 5).Result(), ""},
+		{"any: multiple of none", Int(7, "qty").MultipleOfAny(3, 5).Result(), erm.MsgMultipleOfAny},
+		{"any: zero divisor", Int(9, "qty").MultipleOfAny(0, 3).Result(), erm.MsgDivisorZero},
+		{"any: negated", Int(7, "qty").Not().MultipleOfAny(3, 5).Result(), ""},
+		{"floats", Float64(1.5, "qty").MultipleOfAll(0.5, 0.25).Result(), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.result.AllErrors()
+			if tt.expectedKey == "" {
+				if len(errs) != 0 {
+					t.Errorf("unexpected errors: %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || errs[0].MessageKey() != tt.expectedKey {
+				t.Errorf("expected one %s error, got %v", tt.expectedKey, errs)
+			}
+		})
+	}
+
+	t.Run("single error lists all divisors", func(t *testing.T) {
+		err := Int(9, "qty").MultipleOfAll(3, 5).Validate()
+		if err == nil || err.Error() != "qty must be a multiple of all of: 3, 5" {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestNumberValidatorInRanges(t *testing.T) {
+	ranges := [][2]int{{200, 299}, {400, 499}}
+	tests := []struct {
+		name      string
+		value     int
+		negated   bool
+		shouldErr bool
+	}{
+		{"in first range", 204, false, false},
+		{"in second range", 404, false, false},
+		{"on range boundary", 499, false, false},
+		{"between ranges", 302, false, true},
+		{"above all ranges", 500, false, true},
+		{"negated outside ranges", 302, true, false},
+		{"negated inside range", 200, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := Int(tt.value, "status")
+			if tt.negated {
+				v = v.Not()
+			}
+			err := v.InRanges(ranges).Validate()
+			if tt.shouldErr && err == nil {
+				t.Error("expected error but got none")
+			}
+			if !tt.shouldErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	err := Int(302, "status").InRanges(ranges).Validate()
+	expected := "status must be within one of the ranges [200, 299], [400, 499]"
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected message %q, got %v", expected, err)
+	}
+
+	errs := Int(200, "status").Not().InRanges(ranges).Result().AllErrors()
+	expected = "status must not be within any of the ranges [200, 299], [400, 499]"
+	if len(errs) != 1 || errs[0].MessageKey() != erm.MsgNotInRanges || errs[0].Error() != expected {
+		t.Errorf("expected %s %q, got %v", erm.MsgNotInRanges, expected, errs)
+	}
+}
+
+func TestNumberValidatorStep(t *testing.T) {
+	tests := []struct {
+		name      string
+		validate  func() error
+		shouldErr bool
+	}{
+		{"base value", func() error { return Int(10, "price").Step(10, 5).Validate() }, false},
+		{"base plus steps", func() error { return Int(25, "price").Step(10, 5).Validate() }, false},
+		{"below base", func() error { return Int(0, "price").Step(10, 5).Validate() }, false},
+		{"off step", func() error { return Int(12, "price").Step(10, 5).Validate() }, true},
+		{"unsigned below base", func() error { return Uint(3, "price").Step(7, 2).Validate() }, false},
+		{"float step", func() error { return Float64(1.75, "size").Step(0.5, 0.25).Validate() }, false},
+		{"float off step", func() error { return Float64(1.6, "size").Step(0.5, 0.25).Validate() }, true},
+		{"decimal step", func() error { return Float64(0.3, "size").Step(0, 0.1).Validate() }, false},
+		{"decimal step from base", func() error { return Float64(1.3, "size").Step(0.1, 0.2).Validate() }, false},
+		{"decimal off step", func() error { return Float64(0.35, "size").Step(0, 0.1).Validate() }, true},
+		{"large unsigned", func() error { return Uint64(math.MaxUint64, "id").Step(0, 5).Validate() }, false},
+		{"large unsigned off step", func() error { return Uint64(math.MaxUint64, "id").Step(0, 2).Validate() }, true},
+		{"large unsigned below base", func() error { return Uint64(1, "id").Step(math.MaxUint64, 3).Validate() }, true},
+		{"zero step", func() error { return Int(10, "price").Step(10, 0).Validate() }, true},
+		{"negated on step", func() error { return Int(15, "price").Not().Step(10, 5).Validate() }, true},
+		{"negated off step", func() error { return Int(12, "price").Not().Step(10, 5).Validate() }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validate()
+			if tt.shouldErr && err == nil {
+				t.Error("expected error but got none")
+			}
+			if !tt.shouldErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	errs := Int(15, "price").Not().Step(10, 5).Result().AllErrors()
+	expected := "price must not be 10 plus a multiple of 5"
+	if len(errs) != 1 || errs[0].MessageKey() != erm.MsgNotStep || errs[0].Error() != expected {
+		t.Errorf("expected %s %q, got %v", erm.MsgNotStep, expected, errs)
+	}
+}
+
+func TestOptional(t *testing.T) {
+	tests := []struct {
+		name      string
+		validate  func() error
+		shouldErr bool
+	}{
+		{"empty string skips rules", func() error { return String("", "email").Optional().Email().Validate() }, false},
+		{"blank string skips rules", func() error { return String(" ", "email").Optional().Email().Validate() }, false},
+		{"present invalid string", func() error { return String("nope", "email").Optional().Email().Validate() }, true},
+		{"present valid string", func() error { return String("a@b.co", "email").Optional().Email().Validate() }, false},
+		{"rules before Optional still apply", func() error { return String("", "email").Required().Optional().Email().Validate() }, true},
+		{"zero number skips rules", func() error { return Int(0, "age").Optional().Min(18).Validate() }, false},
+		{"present number", func() error { return Int(5, "age").Optional().Min(18).Validate() }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validate()
+			if tt.shouldErr && err == nil {
+				t.Error("expected error but got none")
+			}
+			if !tt.shouldErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
 func TestLocalization(t *testing.T) {
 	// Test default locale
 	englishResult := String("", "name").Required().Result()
@@ -774,6 +1500,36 @@ func TestLocalization(t *testing.T) {
 	}
 }
 
+func TestValidatorLocale(t *testing.T) {
+	err := i18n.AddTranslations(language.French, map[string]*i18n.Translation{
+		erm.MsgRequired: {Singular: "{{.field}} est obligatoire"},
+	})
+	if err != nil {
+		t.Fatalf("failed to add translations: %v", err)
+	}
+
+	validator := String("", "email").Locale(language.French).Required()
+	if err := validator.Validate(); err == nil || err.Error() != "email est obligatoire" {
+		t.Errorf("expected French message, got %v", err)
+	}
+	if msgs := validator.Result().ErrMap()["email"]; len(msgs) != 1 || msgs[0] != "email est obligatoire" {
+		t.Errorf("expected French error map, got %v", msgs)
+	}
+	if errs := validator.Result().Errors(); errs[0].Error() != "email est obligatoire" {
+		t.Errorf("expected French individual error, got %q", errs[0].Error())
+	}
+
+	// Locale also applies to errors added earlier in the chain
+	if err := Int(0, "age").Required().Locale(language.French).Validate(); err == nil || err.Error() != "age est obligatoire" {
+		t.Errorf("expected French message, got %v", err)
+	}
+
+	// Other validators are unaffected
+	if err := String("", "email").Required().Validate(); err == nil || err.Error() != "email is required" {
+		t.Errorf("expected English message, got %v", err)
+	}
+}
+
 func TestValidationResult(t *testing.T) {
 	// Test successful validation
 	result := String("valid", "test").Required().Result()
@@ -783,20 +1539,219 @@ func TestValidationResult(t *testing.T) {
 	if result.Error() != nil {
 		t.Error("expected no error")
 	}
-	if len(result.AllErrors()) != 0 {
-		t.Error("expected no errors")
+	if len(result.AllErrors()) != 0 {
+		t.Error("expected no errors")
+	}
+
+	// Test failed validation
+	result = String("", "test").Required().Result()
+	if result.Valid() {
+		t.Error("expected invalid result")
+	}
+	if result.Error() == nil {
+		t.Error("expected error")
+	}
+	if len(result.AllErrors()) != 1 {
+		t.Error("expected one error")
+	}
+}
+
+func TestStringValidatorFold(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator *StringValidator
+		shouldErr bool
+	}{
+		{"prefix different case", String("HTTP://example.com", "url").StartsWithFold("http://"), false},
+		{"prefix mismatch", String("ftp://example.com", "url").StartsWithFold("http://"), true},
+		{"prefix longer than value", String("ht", "url").StartsWithFold("http"), true},
+		{"prefix unicode", String("ÉCOLE", "name").StartsWithFold("éc"), false},
+		{"prefix kelvin sign", String("\u212Aelvin", "unit").StartsWithFold("kel"), false},
+		{"negated prefix", String("HTTP://x", "url").Not().StartsWithFold("http://"), true},
+		{"suffix different case", String("photo.JPG", "file").EndsWithFold(".jpg"), false},
+		{"suffix mismatch", String("photo.png", "file").EndsWithFold(".jpg"), true},
+		{"suffix unicode", String("straSSE", "street").EndsWithFold("sse"), false},
+		{"contains different case", String("Hello World", "text").ContainsFold("WORLD"), false},
+		{"contains mismatch", String("Hello World", "text").ContainsFold("moon"), true},
+		{"contains empty", String("Hello", "text").ContainsFold(""), false},
+		{"negated contains", String("Hello World", "text").Not().ContainsFold("hello"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validator.Validate()
+			if tt.shouldErr && err == nil {
+				t.Error("expected error but got none")
+			}
+			if !tt.shouldErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	t.Run("value is not modified", func(t *testing.T) {
+		v := String("HTTP://Example.com", "url").StartsWithFold("http://").EqualTo("HTTP://Example.com")
+		if err := v.Validate(); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if err := String("HTTP://x", "url").StartsWithFold("ftp://").Validate(); err == nil || err.Error() != "url must start with 'ftp://'" {
+			t.Errorf("unexpected message: %v", err)
+		}
+	})
+}
+
+func TestStringValidatorStartsEndsWithAny(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		wantMsg string
+	}{
+		{"starts with one prefix", String("cus_123", "id").StartsWithAny("cus_", "acct_").Validate(), ""},
+		{"starts with another prefix", String("acct_9", "id").StartsWithAny("cus_", "acct_").Validate(), ""},
+		{"starts with none", String("usr_1", "id").StartsWithAny("cus_", "acct_").Validate(), "id must start with one of: cus_, acct_"},
+		{"no prefixes", String("usr_1", "id").StartsWithAny().Validate(), "id must start with one of: "},
+		{"negated match", String("tmp_1", "id").Not().StartsWithAny("tmp_", "test_").Validate(), "id must not start with any of: tmp_, test_"},
+		{"negated no match", String("cus_1", "id").Not().StartsWithAny("tmp_", "test_").Validate(), ""},
+		{"ends with one suffix", String("logo.png", "file").EndsWithAny(".png", ".jpg").Validate(), ""},
+		{"ends with none", String("logo.gif", "file").EndsWithAny(".png", ".jpg").Validate(), "file must end with one of: .png, .jpg"},
+		{"negated suffix match", String("run.exe", "file").Not().EndsWithAny(".exe", ".bat").Validate(), "file must not end with any of: .exe, .bat"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantMsg == "" {
+				if tt.err != nil {
+					t.Errorf("unexpected error: %v", tt.err)
+				}
+				return
+			}
+			if tt.err == nil || tt.err.Error() != tt.wantMsg {
+				t.Errorf("expected %q, got %v", tt.wantMsg, tt.err)
+			}
+		})
+	}
+}
+
+func TestValidatorIsValid(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+		want  bool
+	}{
+		{"passing string chain", String("john@example.com", "email").Required().Email().IsValid(), true},
+		{"failing string chain", String("nope", "email").Required().Email().IsValid(), false},
+		{"passing number chain", Int(20, "age").Min(18).IsValid(), true},
+		{"failing number chain", Float64(1.5, "ratio").Max(1).IsValid(), false},
+		{"result requested without errors", func() bool {
+			v := String("x", "f").Required()
+			v.Result()
+			return v.IsValid()
+		}(), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.valid != tt.want {
+				t.Errorf("expected IsValid() = %v", tt.want)
+			}
+		})
+	}
+}
+
+func TestValidationResultMergeWith(t *testing.T) {
+	address := Form().
+		Add(String("", "street").Required()).
+		Add(String("x", "zip").MinLength(5)).
+		Validate()
+
+	nested := NewValidationResult(nil, "nested")
+	nested.AddError(String("", "city").Required().Result().Error())
+
+	tests := []struct {
+		name   string
+		prefix string
+		child  *ValidationResult
+		want   []string
+	}{
+		{"prefixes field names", "billing_address", address, []string{"billing_address.street", "billing_address.zip"}},
+		{"empty prefix keeps names", "", address, []string{"street", "zip"}},
+		{"flattens nested containers", "shipping", nested, []string{"shipping.city"}},
+		{"valid child adds nothing", "billing", String("ok", "street").Required().Result(), nil},
+		{"nil child adds nothing", "billing", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := String("", "email").Result()
+			parent.MergeWith(tt.prefix, tt.child)
+
+			var fields []string
+			for _, err := range parent.AllErrors() {
+				fields = append(fields, err.FieldName())
+			}
+			if fmt.Sprint(fields) != fmt.Sprint(tt.want) {
+				t.Errorf("expected fields %v, got %v", tt.want, fields)
+			}
+			if len(tt.want) > 0 && len(parent.ErrMap()[tt.want[0]]) != 1 {
+				t.Errorf("expected ErrMap entry for %s, got %v", tt.want[0], parent.ErrMap())
+			}
+		})
+	}
+
+	if fields := address.AllErrors(); fields[0].FieldName() != "street" {
+		t.Errorf("expected child to be unchanged, got %s", fields[0].FieldName())
+	}
+}
+
+func TestAcquireValidationResult(t *testing.T) {
+	result := AcquireValidationResult(nil, "")
+	if !result.Valid() || result.FieldName != "value" {
+		t.Fatalf("expected fresh valid result, got %+v", result)
+	}
+
+	String("", "email").Required().ValidateInto(result)
+	Int(10, "age").Min(18).ValidateInto(result)
+	errs := result.Errors()
+	errMap := result.ErrMap()
+	err := result.Error()
+	result.Release()
+
+	reused := AcquireValidationResult("x", "signup")
+	defer reused.Release()
+	if !reused.Valid() || len(reused.AllErrors()) != 0 || reused.Value != "x" || reused.FieldName != "signup" {
+		t.Errorf("expected released result to be reset, got %+v", reused)
+	}
+	String("ok", "name").Required().ValidateInto(reused)
+	if !reused.Valid() {
+		t.Error("expected reused result to stay valid")
 	}
 
-	// Test failed validation
-	result = String("", "test").Required().Result()
-	if result.Valid() {
-		t.Error("expected invalid result")
+	// Copies taken before Release are unaffected by reuse
+	if len(errs) != 2 || len(errMap) != 2 || err == nil || len(err.(erm.Error).AllErrors()) != 2 {
+		t.Errorf("expected copies to survive Release, got %v, %v, %v", errs, errMap, err)
 	}
-	if result.Error() == nil {
-		t.Error("expected error")
+}
+
+func BenchmarkValidationResultNew(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		result := NewValidationResult(nil, "signup")
+		String("", "email").Required().ValidateInto(result)
+		if result.Valid() {
+			b.Fatal("expected errors")
+		}
 	}
-	if len(result.AllErrors()) != 1 {
-		t.Error("expected one error")
+}
+
+func BenchmarkValidationResultPooled(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		result := AcquireValidationResult(nil, "signup")
+		String("", "email").Required().ValidateInto(result)
+		if result.Valid() {
+			b.Fatal("expected errors")
+		}
+		result.Release()
 	}
 }
 
@@ -1596,6 +2551,32 @@ func TestNumberValidatorNotIn(t *testing.T) {
 			}
 		})
 	}
+
+	denied := set.New[int]()
+	denied.Add(3)
+
+	messages := []struct {
+		name        string
+		result      *ValidationResult
+		expectedKey string
+		expectedMsg string
+	}{
+		{"NotIn", Int(3, "p").NotIn(3).Result(), erm.MsgNotIn, "p must not be one of: 3"},
+		{"NotInSet", Int(3, "p").NotInSet(denied).Result(), erm.MsgNotIn, "p must not be one of: 3"},
+		{"Not In", Int(3, "p").Not().In(3, 4).Result(), erm.MsgNotIn, "p must not be one of: 3, 4"},
+		{"Not NotIn", Int(5, "p").Not().NotIn(3, 4).Result(), erm.MsgIn, "p must be one of: 3, 4"},
+	}
+	for _, tt := range messages {
+		t.Run(tt.name+" message", func(t *testing.T) {
+			errs := tt.result.AllErrors()
+			if len(errs) != 1 {
+				t.Fatalf("expected 1 error, got %d", len(errs))
+			}
+			if errs[0].MessageKey() != tt.expectedKey || errs[0].Error() != tt.expectedMsg {
+				t.Errorf("expected %s %q, got %s %q", tt.expectedKey, tt.expectedMsg, errs[0].MessageKey(), errs[0].Error())
+			}
+		})
+	}
 }
 
 // TestNumberValidatorFinite tests the Finite validation rule
@@ -1806,6 +2787,56 @@ func TestValidationResultMethods(t *testing.T) {
 		}
 	})
 
+	t.Run("ValidateInto shared result", func(t *testing.T) {
+		result := NewValidationResult(nil, "form")
+		String("", "email").Required().Email().ValidateInto(result)
+		Int(16, "age").Min(18).ValidateInto(result)
+		String("john", "name").Required().ValidateInto(result)
+
+		if result.Valid() {
+			t.Fatal("expected shared result to be invalid")
+		}
+		errorMap := result.ErrMap()
+		if len(errorMap) != 2 {
+			t.Fatalf("expected 2 fields in error map, got %v", errorMap)
+		}
+		if len(errorMap["email"]) != 2 || len(errorMap["age"]) != 1 {
+			t.Errorf("unexpected error map %v", errorMap)
+		}
+
+		valid := NewValidationResult(nil, "form")
+		String("john", "name").Required().ValidateInto(valid)
+		if !valid.Valid() {
+			t.Errorf("expected valid result, got %v", valid.ErrMap())
+		}
+	})
+
+	t.Run("Errors returns typed errors", func(t *testing.T) {
+		if errs := String("ok", "name").Required().Result().Errors(); errs != nil {
+			t.Errorf("expected nil for valid result, got %v", errs)
+		}
+
+		result := String("ab", "username").MinLength(3).Email().Result()
+		errs := result.Errors()
+		if len(errs) != 2 {
+			t.Fatalf("expected 2 errors, got %d", len(errs))
+		}
+		if errs[0].MessageKey() != erm.MsgMinLength || errs[0].FieldName() != "username" {
+			t.Errorf("unexpected first error: key=%s field=%s", errs[0].MessageKey(), errs[0].FieldName())
+		}
+		if errs[0].Params()["min"] != 3 {
+			t.Errorf("expected min param 3, got %v", errs[0].Params()["min"])
+		}
+		if errs[1].MessageKey() != erm.MsgEmail {
+			t.Errorf("expected email key, got %s", errs[1].MessageKey())
+		}
+
+		errs[0] = nil
+		if result.Errors()[0] == nil {
+			t.Error("Errors must return a copy")
+		}
+	})
+
 	t.Run("ToError method", func(t *testing.T) {
 		// Test valid result returns nil
 		result := NewValidationResult("test", "field")
@@ -1894,7 +2925,45 @@ func TestValidationErrorMethods(t *testing.T) {
 			t.Error("expected parameter to be set")
 		}
 	})
-	// ValidationCode functionality has been removed since vix always uses 400 status
+}
+
+// TestValidationStatus tests the configurable HTTP status of validation errors
+func TestValidationStatus(t *testing.T) {
+	defer SetValidationStatus(0)
+
+	tests := []struct {
+		name     string
+		global   int
+		result   int
+		expected int
+	}{
+		{"default", 0, 0, http.StatusBadRequest},
+		{"package-wide", http.StatusUnprocessableEntity, 0, http.StatusUnprocessableEntity},
+		{"per result", 0, http.StatusUnprocessableEntity, http.StatusUnprocessableEntity},
+		{"per result overrides package-wide", http.StatusUnprocessableEntity, http.StatusConflict, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetValidationStatus(tt.global)
+
+			result := String("", "name").Required().Result().WithStatus(tt.result)
+			if got := erm.Status(result.Error()); got != tt.expected {
+				t.Errorf("result status = %d, want %d", got, tt.expected)
+			}
+
+			if tt.result == 0 {
+				err := String("", "name").Required().Validate()
+				if got := erm.Status(err); got != tt.expected {
+					t.Errorf("Validate status = %d, want %d", got, tt.expected)
+				}
+				err = V().Is(String("", "name").Required()).Error()
+				if got := erm.Status(err); got != tt.expected {
+					t.Errorf("orchestrator status = %d, want %d", got, tt.expected)
+				}
+			}
+		})
+	}
 }
 
 // TestCustomValidationFunction tests custom validation functions
@@ -2217,6 +3286,223 @@ func TestUnicodeStringHandling(t *testing.T) {
 	})
 }
 
+func TestStringValidatorNormalizeNumber(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		format    NumberFormat
+		wantValue string
+		shouldErr bool
+	}{
+		{"comma decimal with dot groups", "1.234,56", NumberFormatComma, "1234.56", false},
+		{"comma decimal", "0,5", NumberFormatComma, "0.5", false},
+		{"negative", "-12.345.678,9", NumberFormatComma, "-12345678.9", false},
+		{"grouped integer", "1.234", NumberFormatComma, "1234", false},
+		{"point format", "1,234.56", NumberFormatPoint, "1234.56", false},
+		{"narrow no-break space groups", "1\u202f234,5", NumberFormatCommaSpace, "1234.5", false},
+		{"regular space groups", "12 345", NumberFormatCommaSpace, "12345", false},
+		{"apostrophe groups", "1'234.5", NumberFormatPointApostrophe, "1234.5", false},
+		{"misplaced group", "12.34,5", NumberFormatComma, "12.34,5", true},
+		{"empty group", "1..234", NumberFormatComma, "1..234", true},
+		{"canonical input passes through", "0.5", NumberFormatComma, "0.5", false},
+		{"two decimals", "1,2,3", NumberFormatComma, "1,2,3", true},
+		{"wrong convention", "1,234.56", NumberFormatComma, "1,234.56", true},
+		{"trailing decimal", "12,", NumberFormatComma, "12,", true},
+		{"letters", "abc", NumberFormatComma, "abc", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := String(tt.value, "price").NormalizeNumber(tt.format).Float()
+			err := v.Validate()
+			if tt.shouldErr != (err != nil) {
+				t.Errorf("unexpected error state: %v", err)
+			}
+			if got := v.Result().Value; got != tt.wantValue {
+				t.Errorf("expected value %q, got %q", tt.wantValue, got)
+			}
+		})
+	}
+
+	if err := String("1.234", "qty").NormalizeNumber(NumberFormatComma).Integer().Validate(); err != nil {
+		t.Errorf("expected grouped integer to pass Integer, got %v", err)
+	}
+}
+
+func TestNumberFormatFor(t *testing.T) {
+	tests := []struct {
+		tag  language.Tag
+		want NumberFormat
+	}{
+		{language.English, NumberFormatPoint},
+		{language.German, NumberFormatComma},
+		{language.MustParse("de-AT"), NumberFormatComma},
+		{language.MustParse("de-CH"), NumberFormatPointApostrophe},
+		{language.French, NumberFormatCommaSpace},
+		{language.MustParse("pt-BR"), NumberFormatComma},
+		{language.Japanese, NumberFormatPoint},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag.String(), func(t *testing.T) {
+			if got := NumberFormatFor(tt.tag); got != tt.want {
+				t.Errorf("NumberFormatFor(%s) = %+v, want %+v", tt.tag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStringValidatorNormalizeUnicode(t *testing.T) {
+	composed := "caf\u00e9"
+	decomposed := "cafe\u0301"
+
+	tests := []struct {
+		name      string
+		validator *StringValidator
+		wantValue string
+		shouldErr bool
+	}{
+		{"NFC composes", String(decomposed, "name").NormalizeUnicode(norm.NFC), composed, false},
+		{"NFD decomposes", String(composed, "name").NormalizeUnicode(norm.NFD), decomposed, false},
+		{"EqualTo across forms", String(decomposed, "name").NormalizeUnicode(norm.NFC).EqualTo(decomposed), composed, false},
+		{"EqualTo composed", String(decomposed, "name").NormalizeUnicode(norm.NFC).EqualTo(composed), composed, false},
+		{"later rules see normalized value", String(decomposed, "name").NormalizeUnicode(norm.NFC).MaxLength(4), composed, false},
+		{"skipped by Optional", String("", "name").Optional().NormalizeUnicode(norm.NFC), "", false},
+		{"result created before", func() *StringValidator {
+			v := String(decomposed, "name")
+			v.Result()
+			return v.NormalizeUnicode(norm.NFC)
+		}(), composed, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validator.Validate()
+			if tt.shouldErr != (err != nil) {
+				t.Errorf("unexpected error state: %v", err)
+			}
+			if got := tt.validator.Result().Value; got != tt.wantValue {
+				t.Errorf("expected value %q, got %q", tt.wantValue, got)
+			}
+		})
+	}
+
+	if err := String(decomposed, "name").EqualTo(composed).Validate(); err == nil {
+		t.Error("expected forms to differ without normalization")
+	}
+}
+
+func TestStringValidatorValidUTF8(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator *StringValidator
+		wantMsg   string
+	}{
+		{"ascii", String("hello", "name").ValidUTF8(), ""},
+		{"multibyte", String("héllo 世界 👋", "name").ValidUTF8(), ""},
+		{"empty", String("", "name").ValidUTF8(), ""},
+		{"invalid byte", String("abc\xff", "name").ValidUTF8(), "name must be valid UTF-8 text"},
+		{"truncated sequence", String("\xe4\xb8", "name").ValidUTF8(), "name must be valid UTF-8 text"},
+		{"surrogate half", String("\xed\xa0\x80", "name").ValidUTF8(), "name must be valid UTF-8 text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validator.Validate()
+			if tt.wantMsg == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantMsg {
+				t.Errorf("expected %q, got %v", tt.wantMsg, err)
+			}
+		})
+	}
+
+	errs := String("\xff", "name").ValidUTF8().Result().Errors()
+	if len(errs) != 1 || errs[0].MessageKey() != erm.MsgUTF8 {
+		t.Errorf("expected %s error, got %v", erm.MsgUTF8, errs)
+	}
+}
+
+func TestGraphemeCount(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  int
+	}{
+		{"empty", "", 0},
+		{"ascii", "hello", 5},
+		{"family emoji ZWJ sequence", "👨\u200d👩\u200d👧", 1},
+		{"skin tone modifier", "👋🏽", 1},
+		{"variation selector", "❤\ufe0f", 1},
+		{"flags pair up", "🇩🇪🇫🇷", 2},
+		{"odd regional indicators", "🇩🇪🇫", 2},
+		{"combining accent", "e\u0301te\u0301", 3},
+		{"hangul jamo", "\u1100\u1161\u11a8", 1},
+		{"hangul syllables", "한국어", 3},
+		{"CRLF", "a\r\nb", 3},
+		{"ZWJ without pictograph", "a\u200db", 2},
+		{"mixed", "Hello 👋 World 🌍", 15},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := graphemeCount(tt.value); got != tt.want {
+				t.Errorf("graphemeCount(%q) = %d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStringValidatorGraphemes(t *testing.T) {
+	family := "👨\u200d👩\u200d👧"
+	tests := []struct {
+		name      string
+		validator *StringValidator
+		shouldErr bool
+	}{
+		{"emoji counts once for max", String(family+"ab", "name").MaxGraphemes(3), false},
+		{"rune length exceeds same max", String(family+"ab", "name").MaxLength(3), true},
+		{"too many graphemes", String(family+"abc", "name").MaxGraphemes(3), true},
+		{"min graphemes", String(family, "name").MinGraphemes(2), true},
+		{"min graphemes met", String(family+family, "name").MinGraphemes(2), false},
+		{"between", String("e\u0301te\u0301", "name").GraphemesBetween(2, 4), false},
+		{"not between", String("e\u0301te\u0301", "name").GraphemesBetween(5, 6), true},
+		{"negated max", String("ab", "name").Not().MaxGraphemes(3), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validator.Validate()
+			if tt.shouldErr && err == nil {
+				t.Error("expected error but got none")
+			}
+			if !tt.shouldErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	negated := []struct {
+		validator   *StringValidator
+		expectedKey string
+		expectedMsg string
+	}{
+		{String(family+"ab", "name").Not().MinGraphemes(3), erm.MsgNotMinLength, "name must not be at least 3 characters long"},
+		{String(family+"ab", "name").Not().MaxGraphemes(3), erm.MsgNotMaxLength, "name must not be at most 3 characters long"},
+		{String(family+"ab", "name").Not().GraphemesBetween(2, 4), erm.MsgNotBetween, "name must not be between 2 and 4"},
+	}
+	for _, tt := range negated {
+		errs := tt.validator.Result().AllErrors()
+		if len(errs) != 1 || errs[0].MessageKey() != tt.expectedKey || errs[0].Error() != tt.expectedMsg {
+			t.Errorf("expected %s %q, got %v", tt.expectedKey, tt.expectedMsg, errs)
+		}
+	}
+}
+
 // TestLargeNumberEdgeCases tests validation with very large numbers
 func TestLargeNumberEdgeCases(t *testing.T) {
 	t.Run("Large integer validation", func(t *testing.T) {
@@ -2331,6 +3617,150 @@ func TestMemoryEfficiency(t *testing.T) {
 	})
 }
 
+// TestValidateFastPathAllocations guards the lazy ValidationResult: a passing
+// chain must not allocate a result or an erm container.
+func TestValidateFastPathAllocations(t *testing.T) {
+	email := "john@example.com"
+	allocs := testing.AllocsPerRun(100, func() {
+		if err := String(email, "email").Required().MinLength(4).MaxLength(100).Validate(); err != nil {
+			t.Fatal(err)
+		}
+	})
+	// Depending on escape analysis, the validator itself may be heap allocated
+	if allocs > 1 {
+		t.Errorf("expected at most 1 allocation for a passing chain, got %v", allocs)
+	}
+
+	if err := String("", "email").Required().Validate(); err == nil {
+		t.Error("expected error for failing chain")
+	}
+	if result := String(email, "email").Required().Result(); !result.Valid() || result.FieldName != "email" || result.Value != email {
+		t.Errorf("unexpected lazily created result: %+v", result)
+	}
+}
+
+func TestBuiltinPatternAllocations(t *testing.T) {
+	tests := []struct {
+		name     string
+		validate func() error
+	}{
+		{"Email", func() error { return String("john@example.com", "f").Email().Validate() }},
+		{"URL", func() error { return String("https://example.com", "f").URL().Validate() }},
+		{"UUID", func() error { return String("123e4567-e89b-12d3-a456-426614174000", "f").UUID().Validate() }},
+		{"Slug", func() error { return String("hello-world", "f").Slug().Validate() }},
+		{"Numeric", func() error { return String("12345", "f").Numeric().Validate() }},
+		{"Alpha", func() error { return String("hello", "f").Alpha().Validate() }},
+		{"AlphaNumeric", func() error { return String("hello123", "f").AlphaNumeric().Validate() }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allocs := testing.AllocsPerRun(100, func() {
+				if err := tt.validate(); err != nil {
+					t.Fatal(err)
+				}
+			})
+			// Compiling a pattern costs dozens of allocations; matching a
+			// precompiled one costs at most a few.
+			if allocs > 3 {
+				t.Errorf("expected no per-call pattern compilation, got %v allocations", allocs)
+			}
+		})
+	}
+}
+
+func BenchmarkStringBuiltinPatterns(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := String("123e4567-e89b-12d3-a456-426614174000", "id").UUID().Validate(); err != nil {
+			b.Fatal(err)
+		}
+		if err := String("hello-world", "slug").Slug().Validate(); err != nil {
+			b.Fatal(err)
+		}
+		if err := String("john@example.com", "email").Email().Validate(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkStringRequiredValid(b *testing.B) {
+	email := "john@example.com"
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := String(email, "email").Required().Validate(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkStringChainValid(b *testing.B) {
+	email := "john@example.com"
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := String(email, "email").Required().MinLength(4).MaxLength(100).Validate(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkStringRequiredInvalid(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := String("", "email").Required().Validate(); err == nil {
+			b.Fatal("expected error")
+		}
+	}
+}
+
+func BenchmarkIntRangeValid(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := Int(i+1, "count").Required().Min(1).Validate(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func largeIDs(n int) []int {
+	ids := make([]int, n)
+	for i := range ids {
+		ids[i] = i * 2
+	}
+	return ids
+}
+
+func BenchmarkIntInLarge(b *testing.B) {
+	ids := largeIDs(5000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := Int(9998, "id").In(ids...).Validate(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkIntInSetLarge(b *testing.B) {
+	ids := set.New[int]()
+	ids.AddList(largeIDs(5000))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := Int(9998, "id").InSet(ids).Validate(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkIntInLargeInvalid(b *testing.B) {
+	ids := largeIDs(5000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := Int(1, "id").In(ids...).Validate(); err == nil {
+			b.Fatal("expected error")
+		}
+	}
+}
+
 // TestNumberValidatorCustom tests the Custom method for NumberValidator
 func TestNumberValidatorCustom(t *testing.T) {
 	t.Run("Custom validation success", func(t *testing.T) {
@@ -2469,6 +3899,21 @@ func TestValidatorEdgeCasesForCoverage(t *testing.T) {
 		}
 	})
 
+	t.Run("formatValues per kind", func(t *testing.T) {
+		if got := formatValues([]int{-1, 2}); got != "-1, 2" {
+			t.Errorf("unexpected int format: %q", got)
+		}
+		if got := formatValues([]uint64{math.MaxUint64}); got != "18446744073709551615" {
+			t.Errorf("unexpected uint format: %q", got)
+		}
+		if got := formatValues([]float32{0.1, 2}); got != "0.1, 2" {
+			t.Errorf("unexpected float32 format: %q", got)
+		}
+		if got := formatValues([]float64{1.5, 1e21}); got != fmt.Sprintf("%v, %v", 1.5, 1e21) {
+			t.Errorf("unexpected float64 format: %q", got)
+		}
+	})
+
 	t.Run("Finite with special float values", func(t *testing.T) {
 		// Test with positive infinity
 		err := Float64(math.Inf(1), "test").Finite().Validate()
@@ -2503,3 +3948,104 @@ func TestValidatorEdgeCasesForCoverage(t *testing.T) {
 		}
 	})
 }
+
+// =============================================================================
+// Spec Tests
+// =============================================================================
+
+func TestFromSpec(t *testing.T) {
+	usernameSpec, err := ParseSpec([]byte(`{"required":true,"minLength":3,"maxLength":10,"pattern":"^[a-z0-9_]+$"}`))
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	ageSpec, err := ParseSpec([]byte(`{"type":"number","min":18,"max":120,"in":[18,21,30]}`))
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	colorSpec := Spec{In: []interface{}{"red", "green"}}
+
+	tests := []struct {
+		name      string
+		spec      Spec
+		value     interface{}
+		shouldErr bool
+		key       string
+	}{
+		{"valid string", usernameSpec, "john_doe", false, ""},
+		{"missing required", usernameSpec, "", true, erm.MsgRequired},
+		{"too short", usernameSpec, "jo", true, erm.MsgMinLength},
+		{"pattern mismatch", usernameSpec, "John", true, erm.MsgRegex},
+		{"optional empty", colorSpec, "", false, ""},
+		{"string in", colorSpec, "blue", true, erm.MsgIn},
+		{"email format", Spec{Format: "email"}, "nope", true, erm.MsgEmail},
+		{"valid number", ageSpec, 21, false, ""},
+		{"numeric string", ageSpec, "30", false, ""},
+		{"number below min", ageSpec, 10, true, erm.MsgMin},
+		{"number not in", ageSpec, 25, true, erm.MsgIn},
+		{"not a number", ageSpec, "abc", true, erm.MsgFloat},
+		{"Inf with only min", Spec{Type: "number", Min: floatPtr(1)}, "Inf", true, erm.MsgFloat},
+		{"-Inf with only max", Spec{Type: "number", Max: floatPtr(1)}, "-Inf", true, erm.MsgFloat},
+		{"NaN without rules", Spec{Type: "number"}, "NaN", true, erm.MsgFloat},
+		{"NaN float value", Spec{Type: "number"}, math.NaN(), true, erm.MsgFloat},
+		{"Inf float value", Spec{Type: "number", Min: floatPtr(1)}, math.Inf(1), true, erm.MsgFloat},
+		{"missing optional number", ageSpec, nil, false, ""},
+		{"missing required number", Spec{Type: "number", Required: true}, "", true, erm.MsgRequired},
+		{"explicit zero is checked", Spec{Type: "number", Min: floatPtr(1)}, 0, true, erm.MsgMin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sv, err := FromSpec("field", tt.spec)
+			if err != nil {
+				t.Fatalf("unexpected spec error: %v", err)
+			}
+			errs := sv.Validate(tt.value).Result().AllErrors()
+			if !tt.shouldErr {
+				if len(errs) != 0 {
+					t.Errorf("unexpected errors: %v", errs)
+				}
+				return
+			}
+			if len(errs) == 0 {
+				t.Fatal("expected error but got none")
+			}
+			if errs[0].MessageKey() != tt.key {
+				t.Errorf("expected key %q, got %q", tt.key, errs[0].MessageKey())
+			}
+		})
+	}
+}
+
+func TestFromSpecErrors(t *testing.T) {
+	if _, err := ParseSpec([]byte(`{"minLenght":3}`)); err == nil {
+		t.Error("expected error for unknown spec key")
+	}
+
+	invalid := []Spec{
+		{Type: "date"},
+		{Format: "phone"},
+		{Pattern: "("},
+		{Type: "number", In: []interface{}{"x"}},
+	}
+	for _, spec := range invalid {
+		if _, err := FromSpec("field", spec); err == nil {
+			t.Errorf("expected error for spec %+v", spec)
+		}
+	}
+}
+
+func TestSpecMerge(t *testing.T) {
+	base := Spec{MinLength: intPtr(3), MaxLength: intPtr(20)}
+	merged := base.Merge(Spec{Required: true, MaxLength: intPtr(10), Format: "slug"})
+
+	if !merged.Required || *merged.MinLength != 3 || *merged.MaxLength != 10 || merged.Format != "slug" {
+		t.Errorf("unexpected merged spec: %+v", merged)
+	}
+	if *base.MaxLength != 20 || base.Required {
+		t.Error("Merge must not modify the receiver")
+	}
+}
+
+func intPtr(v int) *int { return &v }
+
+func floatPtr(v float64) *float64 { return &v }